@@ -0,0 +1,92 @@
+package osc
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+/*
+ACL is an access control list of the networks that may send messages to a method. Both IPv4 and IPv6 networks may be
+included; IPv4 sources are matched whether they arrive as IPv4 or IPv4-mapped IPv6 addresses.
+*/
+type ACL []*net.IPNet
+
+/*
+ParseACL creates an ACL from a list of IP addresses and networks in CIDR notation, e.g. ParseACL("192.168.1.20",
+"10.0.0.0/8", "fd00::/8").
+*/
+func ParseACL(sources ...string) (ACL, error) {
+	acl := make(ACL, 0, len(sources))
+
+	for _, source := range sources {
+		if !strings.Contains(source, "/") {
+			ip := net.ParseIP(source)
+			if ip == nil {
+				return nil, fmt.Errorf("Invalid ACL source \"%s\"", source)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+
+			acl = append(acl, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(source)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ACL source \"%s\"", source)
+		}
+
+		acl = append(acl, network)
+	}
+
+	return acl, nil
+}
+
+/*
+Allows returns true if addr is within one of the networks in the ACL. Addresses that are not IP addresses (e.g. those
+of serial devices, or nil) are never allowed.
+*/
+func (acl ACL) Allows(addr net.Addr) bool {
+	var ip net.IP
+	switch a := addr.(type) {
+	case *net.UDPAddr:
+		ip = a.IP
+	case *net.TCPAddr:
+		ip = a.IP
+	case *net.IPAddr:
+		ip = a.IP
+	}
+
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range acl {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+HandleFrom adds an OSC method to the AddressSpace that is only invoked for matching messages sent from a source allowed
+by sources, e.g. so that only the lighting console may send to "/dmx/*". Messages from other sources, or whose source is
+unknown (including those passed to Dispatch), are ignored by this method, but are still dispatched to any other
+matching methods. If the AddressPattern is of invalid format, an error is returned.
+*/
+func (a *AddressSpace) HandleFrom(addressPattern string, fn MessageHandleFunc, sources ACL, filters ...MessageFilter) error {
+	method := Method{
+		AddressPattern: addressPattern,
+		Function:       fn,
+		Filters:        filters,
+		Sources:        sources,
+	}
+
+	return a.addMethod(method)
+}
@@ -0,0 +1,58 @@
+package osc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestACL(t *testing.T) {
+	acl, err := ParseACL("192.168.1.20", "10.0.0.0/8", "fd00::/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		addr     net.Addr
+		expected bool
+	}{
+		{&net.UDPAddr{IP: net.ParseIP("192.168.1.20"), Port: 8000}, true},
+		{&net.UDPAddr{IP: net.ParseIP("192.168.1.21"), Port: 8000}, false},
+		{&net.TCPAddr{IP: net.ParseIP("::ffff:10.1.2.3"), Port: 8000}, true},
+		{&net.UDPAddr{IP: net.ParseIP("fd12::1"), Port: 8000}, true},
+		{nil, false},
+	}
+
+	for _, test := range tests {
+		if result := acl.Allows(test.addr); result != test.expected {
+			t.Errorf("Got %v for %v, expected %v", result, test.addr, test.expected)
+		}
+	}
+
+	if _, err := ParseACL("console"); err == nil {
+		t.Error("Expected an error for an invalid source")
+	}
+}
+
+func TestHandleFrom(t *testing.T) {
+	var a AddressSpace
+	var received []string
+
+	acl, _ := ParseACL("192.168.1.20")
+	a.HandleFrom("/dmx/*", func(m *Message) {
+		received = append(received, "dmx")
+	}, acl)
+	a.Handle("/dmx/*", func(m *Message) {
+		received = append(received, "any")
+	})
+
+	console := newPeer(&net.UDPAddr{IP: net.ParseIP("192.168.1.20")}, nil)
+	laptop := newPeer(&net.UDPAddr{IP: net.ParseIP("192.168.1.99")}, nil)
+
+	a.DispatchFrom(NewMessage("/dmx/1"), console)
+	a.DispatchFrom(NewMessage("/dmx/1"), laptop)
+	a.Dispatch(NewMessage("/dmx/1"))
+
+	if len(received) != 4 || received[0] != "dmx" || received[1] != "any" {
+		t.Errorf("Got %v, expected [dmx any any any]", received)
+	}
+}
@@ -1,96 +1,630 @@
 package osc
 
 import (
-	"regexp"
+	"fmt"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dougfinl/go-osc/codec"
 )
 
+// The maximum number of aliases followed when resolving an address
+const maxAliasDepth = 16
+
 /*
 MessageHandleFunc is a function type that accepts a pointer to a Message.
 */
 type MessageHandleFunc func(*Message)
 
 /*
-Method represents an address pattern with associated invokable function.
+MessageFilter is a predicate evaluated against a Message after its address has matched a method. The method is only
+invoked if all of its filters return true.
+*/
+type MessageFilter func(*Message) bool
+
+/*
+Method represents an address pattern with associated invokable function. Group is the name of the handler group the
+method belongs to, if any (see HandleInGroup).
 */
 type Method struct {
 	AddressPattern string
 	Function       MessageHandleFunc
-	regexp         *regexp.Regexp
+	Filters        []MessageFilter
+	Sources        ACL
+	Group          string
+
+	handler func(*Message, *Peer)
+	id      uint64
+}
+
+/*
+call invokes the method's function, providing the peer to handlers that accept one.
+*/
+func (m Method) call(msg *Message, peer *Peer) {
+	if m.handler != nil {
+		m.handler(msg, peer)
+	} else if m.Function != nil {
+		m.Function(msg)
+	}
+}
+
+/*
+accepts returns true if all of the method's filters accept the message.
+*/
+func (m Method) accepts(msg *Message) bool {
+	for _, filter := range m.Filters {
+		if !filter(msg) {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+permits returns true if the method accepts messages from peer.
+*/
+func (m Method) permits(peer *Peer) bool {
+	if m.Sources == nil {
+		return true
+	}
+
+	return peer != nil && m.Sources.Allows(peer.Addr)
+}
+
+/*
+ArgumentEquals returns a MessageFilter accepting messages whose argument at index i is equal to value, e.g.
+ArgumentEquals(0, int32(1)).
+*/
+func ArgumentEquals(i int, value interface{}) MessageFilter {
+	return func(msg *Message) bool {
+		return i >= 0 && i < len(msg.Arguments) && reflect.DeepEqual(msg.Arguments[i], value)
+	}
+}
+
+/*
+ArgumentCount returns a MessageFilter accepting messages with exactly n arguments.
+*/
+func ArgumentCount(n int) MessageFilter {
+	return func(msg *Message) bool {
+		return len(msg.Arguments) == n
+	}
+}
+
+/*
+ArgumentSignature returns a MessageFilter accepting messages whose arguments match sig.
+*/
+func ArgumentSignature(sig Signature) MessageFilter {
+	return sig.Matches
+}
+
+/*
+PacketHook inspects a packet received by a server or client before it is decoded and dispatched. It returns false to
+consume the packet, preventing it from being dispatched to the AddressSpace's methods.
+*/
+type PacketHook func(data []byte, peer *Peer) bool
+
+/*
+BundleHook inspects a bundle received by a server or client, or passed to DispatchPacket, before its messages are
+dispatched. It returns false to consume the bundle, preventing its messages from being dispatched, e.g. so that an
+application can apply all of the changes in a bundle at once.
+*/
+type BundleHook func(b *Bundle, peer *Peer) bool
+
+/*
+alias redirects messages sent to one address (or any address below it) to another.
+*/
+type alias struct {
+	from       string
+	to         string
+	deprecated bool
 }
 
+// methodIDs generates the identifiers of methods, unique across all address spaces.
+var methodIDs uint64
+
 /*
-AddressSpace holds a set of methods that an OSC server can respond to.
+AddressSpace holds a set of methods that an OSC server can respond to. It is safe for concurrent use: methods may be
+added and messages dispatched from any number of goroutines, and handlers may themselves be called concurrently.
+
+An AddressSpace is embedded in each server, but its zero value is also ready to use on its own, dispatching packets
+passed to DispatchPacket or DispatchData.
 */
 type AddressSpace struct {
+	mu      sync.RWMutex
 	methods []Method
+	aliases []alias
+	hooks   []PacketHook
+	bundles []BundleHook
+	logger  Logger
+	tasks   taskPool
+
+	addressCase AddressCase
+	normalize   bool
+	thread      *dispatchThread
+	pooling     bool
+
+	watchdog  watchdog
+	scheduled scheduler
+	clock     Clock
+	skew      SkewEstimator
+	unhandled unhandled
+	cache     *ParameterSpace
+	disabled  map[string]bool
+	prefixes  []peerPrefix
+	waiting   replyWaiters
+
+	maxTransfer int64
+	cacheLimit  int
 }
 
 /*
 Handle adds an OSC method to the AddressSpace. If the AddressPattern is of invalid format, an error is returned.
 */
 func (a *AddressSpace) Handle(addressPattern string, fn MessageHandleFunc) error {
-	// Compile a regexp to use when matching the address pattern
-	regexp, err := addressPatternToRegexp(addressPattern)
-	if err != nil {
-		return err
-	}
+	return a.HandleFiltered(addressPattern, fn)
+}
 
+/*
+HandleFiltered adds an OSC method to the AddressSpace that is only invoked for matching messages accepted by all of the
+filters. If the AddressPattern is of invalid format, an error is returned.
+*/
+func (a *AddressSpace) HandleFiltered(addressPattern string, fn MessageHandleFunc, filters ...MessageFilter) error {
 	method := Method{
 		AddressPattern: addressPattern,
 		Function:       fn,
-		regexp:         regexp,
+		Filters:        filters,
+	}
+
+	return a.addMethod(method)
+}
+
+/*
+HandleGroup adds a set of OSC methods to the AddressSpace, keyed by address pattern. Either all of the methods are
+added, or none: if any AddressPattern is of invalid format, an error is returned and the AddressSpace is unchanged.
+Messages being dispatched concurrently see either none or all of the methods. The methods are added in order of their
+address patterns.
+*/
+func (a *AddressSpace) HandleGroup(handlers map[string]MessageHandleFunc) error {
+	patterns := make([]string, 0, len(handlers))
+	for pattern := range handlers {
+		err := codec.ValidatePattern(pattern)
+		if err != nil {
+			return err
+		}
+
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	methods := make([]Method, 0, len(patterns))
+	for _, pattern := range patterns {
+		methods = append(methods, Method{AddressPattern: pattern, Function: handlers[pattern]})
+	}
+
+	a.addMethods(methods)
+
+	return nil
+}
+
+/*
+addMethods adds methods with valid address patterns to the AddressSpace at once.
+*/
+func (a *AddressSpace) addMethods(methods []Method) {
+	for i := range methods {
+		methods[i].id = atomic.AddUint64(&methodIDs, 1)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Copy the methods, as messages being dispatched may hold the current slice
+	a.methods = append(append(make([]Method, 0, len(a.methods)+len(methods)), a.methods...), methods...)
+}
+
+/*
+handlePeer adds an OSC method whose function is also passed the peer that sent each message.
+*/
+func (a *AddressSpace) handlePeer(addressPattern string, fn func(*Message, *Peer)) error {
+	method := Method{
+		AddressPattern: addressPattern,
+		Function: func(m *Message) {
+			fn(m, nil)
+		},
+		handler: fn,
+	}
+
+	return a.addMethod(method)
+}
+
+/*
+addMethod validates the address pattern of a method, and adds it to the AddressSpace.
+*/
+func (a *AddressSpace) addMethod(method Method) error {
+	_, err := a.addMethodID(method)
+
+	return err
+}
+
+/*
+addMethodID is like addMethod, but also returns an identifier for the method which can be passed to removeMethod.
+*/
+func (a *AddressSpace) addMethodID(method Method) (uint64, error) {
+	err := codec.ValidatePattern(method.AddressPattern)
+	if err != nil {
+		return 0, err
 	}
 
+	method.id = atomic.AddUint64(&methodIDs, 1)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
 	a.methods = append(a.methods, method)
 
+	return method.id, nil
+}
+
+/*
+removeMethod removes the method with an identifier returned by addMethodID.
+*/
+func (a *AddressSpace) removeMethod(id uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Copy the methods, as messages being dispatched may hold the current slice
+	methods := make([]Method, 0, len(a.methods))
+	for _, method := range a.methods {
+		if method.id != id {
+			methods = append(methods, method)
+		}
+	}
+
+	a.methods = methods
+}
+
+/*
+Alias redirects messages sent to the address from, or any address below it, to the equivalent address under to. For
+example, after Alias("/old", "/new"), a message sent to "/old/level" is dispatched as "/new/level". Aliases may be
+chained, but an error is returned if the alias would create a loop.
+*/
+func (a *AddressSpace) Alias(from, to string) error {
+	return a.addAlias(from, to, false)
+}
+
+/*
+DeprecateAlias is like Alias, but also logs a warning each time the alias is used, to help find legacy controllers
+that still use the old address.
+*/
+func (a *AddressSpace) DeprecateAlias(from, to string) error {
+	return a.addAlias(from, to, true)
+}
+
+func (a *AddressSpace) addAlias(from, to string, deprecated bool) error {
+	from = strings.TrimSuffix(from, "/")
+	to = strings.TrimSuffix(to, "/")
+
+	if !strings.HasPrefix(from, "/") || !strings.HasPrefix(to, "/") {
+		return fmt.Errorf("Alias addresses must start with '/'")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	aliases := append(append([]alias(nil), a.aliases...), alias{from: from, to: to, deprecated: deprecated})
+
+	// Ensure that resolving the new alias (or anything beneath it) does not lead back to where it started. Addresses
+	// beneath either end of the alias may be rewritten by other aliases, so a loop can pass through those, e.g.
+	// /a/b -> /c/b followed by /c -> /a; each is checked as if it were beneath the new alias.
+	suffixes := []string{""}
+	for _, al := range aliases {
+		if strings.HasPrefix(al.from, from+"/") {
+			suffixes = append(suffixes, al.from[len(from):])
+		}
+		if strings.HasPrefix(al.from, to+"/") {
+			suffixes = append(suffixes, al.from[len(to):])
+		}
+	}
+
+	for _, suffix := range suffixes {
+		if _, _, err := resolveAlias(aliases, from+suffix); err != nil {
+			return err
+		}
+	}
+
+	a.aliases = aliases
+
 	return nil
 }
 
+/*
+resolveAlias follows the aliases matching address, returning the final address, and the deprecated aliases used along
+the way. An error is returned if the aliases form a loop.
+*/
+func resolveAlias(aliases []alias, address string) (string, []alias, error) {
+	var deprecated []alias
+
+	for depth := 0; ; depth++ {
+		matched := false
+
+		for _, al := range aliases {
+			if address == al.from || strings.HasPrefix(address, al.from+"/") {
+				if depth >= maxAliasDepth {
+					return "", nil, fmt.Errorf("Alias loop detected at \"%s\"", al.from)
+				}
+
+				address = al.to + address[len(al.from):]
+				if al.deprecated {
+					deprecated = append(deprecated, al)
+				}
+
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return address, deprecated, nil
+		}
+	}
+}
+
+/*
+Swap atomically replaces the methods and aliases of the AddressSpace with those of newSpace, e.g. to apply a reloaded
+configuration while a server is running. Messages being dispatched at the time of the swap complete using the old
+methods; all later messages use the new ones. newSpace is not modified, and may be reused.
+*/
+func (a *AddressSpace) Swap(newSpace *AddressSpace) {
+	newSpace.mu.RLock()
+	methods := append([]Method(nil), newSpace.methods...)
+	aliases := append([]alias(nil), newSpace.aliases...)
+	newSpace.mu.RUnlock()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.methods = methods
+	a.aliases = aliases
+}
+
+/*
+SetLogger sets the Logger used to report diagnostic messages (e.g. use of deprecated aliases). By default, messages are
+logged to standard error.
+*/
+func (a *AddressSpace) SetLogger(logger Logger) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.logger = logger
+}
+
+/*
+logf reports a diagnostic message to the AddressSpace's Logger.
+*/
+func (a *AddressSpace) logf(format string, v ...interface{}) {
+	a.mu.RLock()
+	logger := a.logger
+	a.mu.RUnlock()
+
+	if logger == nil {
+		logger = defaultLogger
+	}
+
+	logger.Printf(format, v...)
+}
+
+/*
+AddPacketHook adds a hook that inspects every packet received by the server or client the AddressSpace belongs to,
+before it is decoded. Hooks are run in the order they were added.
+*/
+func (a *AddressSpace) AddPacketHook(hook PacketHook) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.hooks = append(a.hooks, hook)
+}
+
+/*
+OnBundle adds a hook that is passed each bundle as a whole, before its messages are dispatched. Nested bundles are
+passed as part of the outermost bundle. Hooks are run in the order they were added.
+*/
+func (a *AddressSpace) OnBundle(hook BundleHook) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.bundles = append(a.bundles, hook)
+}
+
+/*
+runBundleHooks runs the bundle hooks on a bundle, returning false if one of them consumed it.
+*/
+func (a *AddressSpace) runBundleHooks(b *Bundle, peer *Peer) bool {
+	a.mu.RLock()
+	hooks := a.bundles
+	a.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if !hook(b, peer) {
+			return false
+		}
+	}
+
+	return true
+}
+
 /*
 Methods returns the OSC methods held in an AddressSpace.
 */
-func (a AddressSpace) Methods() []Method {
-	return a.methods
+func (a *AddressSpace) Methods() []Method {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return append([]Method(nil), a.methods...)
 }
 
 /*
 Dispatch finds a matching OSC method for the Message m, and invokes it if found.
 */
-func (a AddressSpace) Dispatch(m *Message) {
+func (a *AddressSpace) Dispatch(m *Message) {
+	a.DispatchFrom(m, nil)
+}
+
+/*
+DispatchFrom is like Dispatch, but also records the peer that sent the message, so that methods can reply to it. The
+peer may be nil if it is unknown.
+*/
+func (a *AddressSpace) DispatchFrom(m *Message, peer *Peer) {
 	if m == nil {
 		return
 	}
 
-	for _, h := range a.methods {
-		if h.regexp.MatchString(m.Address) {
-			h.Function(m)
+	a.mu.RLock()
+	methods := a.methods
+	aliases := a.aliases
+	logger := a.logger
+	watchdog := a.watchdog
+	addressCase := a.addressCase
+	normalize := a.normalize
+	thread := a.thread
+	cache := a.cache
+	cacheLimit := a.cacheLimit
+	disabled := a.disabled
+	prefixes := a.prefixes
+	a.mu.RUnlock()
+
+	if logger == nil {
+		logger = defaultLogger
+	}
+
+	if normalize || addressCase == LowerCase {
+		address := m.Address
+		if normalize {
+			address = normalizeAddress(address)
+		}
+		if addressCase == LowerCase {
+			address = strings.ToLower(address)
+		}
+
+		if address != m.Address {
+			normalized := *m
+			normalized.Address = address
+			m = &normalized
 		}
 	}
+
+	if prefix := peerPrefixFor(prefixes, peer); prefix != "" {
+		prefixed := *m
+		prefixed.Address = prefix + m.Address
+		m = &prefixed
+	}
+
+	if len(aliases) > 0 {
+		address, deprecated, err := resolveAlias(aliases, m.Address)
+		if err != nil {
+			logger.Printf("%v", err)
+			return
+		}
+
+		for _, al := range deprecated {
+			logger.Printf("Deprecated address %s used, use %s instead", al.from, al.to)
+		}
+
+		if address != m.Address {
+			aliased := *m
+			aliased.Address = address
+			m = &aliased
+		}
+	}
+
+	if cache != nil {
+		if address, ok := cacheQuery(m); ok && queryPermitted(methods, disabled, address, addressCase, peer) {
+			answerCacheQuery(cache, address, peer)
+			return
+		}
+	}
+
+	invoke := func() {
+		handled := false
+		for _, h := range methods {
+			if h.Group != "" && disabled[h.Group] {
+				continue
+			}
+
+			if matchAddress(h.AddressPattern, m.Address, addressCase) && h.permits(peer) && h.accepts(m) {
+				watchdog.call(h, m, peer, logger)
+				handled = true
+			}
+		}
+
+		if !handled {
+			a.handleUnhandled(m, peer)
+		} else if cache != nil {
+			// Only values accepted by a method are cached, so that rejected peers cannot set them
+			cacheValue(cache, cacheLimit, m)
+		}
+	}
+
+	if thread != nil {
+		thread.run(invoke)
+	} else {
+		invoke()
+	}
 }
 
 /*
-addressPatternToRegexp creates a regular expression used to efficiently match the address pattern.
+dispatchData runs the packet hooks on a received packet, then attempts to decode and dispatch it. If the data is not
+a valid OSC packet, an error is logged and it is ignored. Messages with trailing bytes after their arguments are
+dispatched, with a warning logged. Bundles are dispatched as by DispatchPacketFrom, with the messages of future
+bundles scheduled for their time tags; invalid bundles are logged and dropped.
 */
-func addressPatternToRegexp(addressPattern string) (*regexp.Regexp, error) {
-	// Escape forward slashes
-	apRegexp := strings.Replace(addressPattern, "/", "\\/", -1)
-	// apRegexp := addressPattern
+func (a *AddressSpace) dispatchData(data []byte, peer *Peer) {
+	a.mu.RLock()
+	hooks := a.hooks
+	logger := a.logger
+	pooling := a.pooling
+	a.mu.RUnlock()
 
-	// Convert basic wildcard expressions and classes
-	apRegexp = strings.Replace(apRegexp, "?", ".", -1)
-	apRegexp = strings.Replace(apRegexp, "*", ".*", -1)
-	apRegexp = strings.Replace(apRegexp, "![", "[^", -1)
+	if logger == nil {
+		logger = defaultLogger
+	}
 
-	// Convert group notation
-	apRegexp = strings.Replace(apRegexp, "{", "(", -1)
-	apRegexp = strings.Replace(apRegexp, "}", ")", -1)
-	apRegexp = strings.Replace(apRegexp, ",", "|", -1)
+	logWire(wireIn, peer, data)
 
-	re, err := regexp.Compile(apRegexp)
-	if err != nil {
-		return nil, err
+	for _, hook := range hooks {
+		if !hook(data, peer) {
+			return
+		}
 	}
 
-	return re, nil
+	if pooling && len(data) > 0 && data[0] == '/' {
+		m, err := decodePooledMessage(data)
+		if isTrailingData(err) {
+			logger.Printf("%v (from %s)", err, peer)
+		} else if err != nil {
+			logger.Printf("%v (from %s)", err, peer)
+			return
+		}
+
+		a.DispatchFrom(m, peer)
+		releaseMessage(m)
+
+		return
+	}
+
+	p, err := decodePacket(data)
+	if isTrailingData(err) {
+		// The packet was decoded, but may be truncated or corrupted
+		logger.Printf("%v (from %s)", err, peer)
+	} else if err != nil {
+		logger.Printf("%v (from %s)", err, peer)
+		return
+	}
+
+	err = a.DispatchPacketFrom(p, peer)
+	if err != nil {
+		logger.Printf("%v (from %s)", err, peer)
+	}
 }
@@ -0,0 +1,308 @@
+package osc
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type testLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+// count returns the number of lines logged, for loggers written to from other goroutines.
+func (l *testLogger) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.lines)
+}
+
+func TestDispatch(t *testing.T) {
+	var a AddressSpace
+	var received []string
+
+	a.Handle("/mixer/*/fader", func(m *Message) {
+		received = append(received, m.Address)
+	})
+
+	a.Dispatch(NewMessage("/mixer/1/fader"))
+	a.Dispatch(NewMessage("/mixer/1/mute"))
+	a.Dispatch(nil)
+
+	if len(received) != 1 || received[0] != "/mixer/1/fader" {
+		t.Errorf("Got %v, expected [/mixer/1/fader]", received)
+	}
+}
+
+func TestAlias(t *testing.T) {
+	var a AddressSpace
+	var received []string
+	logger := &testLogger{}
+
+	a.SetLogger(logger)
+	a.Handle("/new/level", func(m *Message) {
+		received = append(received, m.Address)
+	})
+
+	if err := a.Alias("/old", "/older"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.DeprecateAlias("/older", "/new"); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := NewMessage("/old/level")
+	a.Dispatch(msg)
+
+	if len(received) != 1 || received[0] != "/new/level" {
+		t.Errorf("Got %v, expected [/new/level]", received)
+	}
+
+	if msg.Address != "/old/level" {
+		t.Errorf("Original message address changed to %s", msg.Address)
+	}
+
+	if len(logger.lines) != 1 {
+		t.Errorf("Got %d log lines, expected 1", len(logger.lines))
+	}
+
+	// Aliases leading back to themselves are rejected
+	if err := a.Alias("/new", "/old"); err == nil {
+		t.Error("Expected an error for an alias loop")
+	}
+
+	if err := a.Alias("/a", "/a/b"); err == nil {
+		t.Error("Expected an error for a self-nesting alias")
+	}
+
+	// Loops through addresses beneath an alias are rejected too
+	if err := a.Alias("/x/y", "/z/y"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Alias("/z", "/x"); err == nil {
+		t.Error("Expected an error for an alias loop beneath an alias")
+	}
+
+	// Aliases beneath the target which lead elsewhere are not loops
+	if err := a.Alias("/p/q", "/r"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Alias("/p", "/p/q"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSwap(t *testing.T) {
+	var a, b AddressSpace
+	var received []string
+
+	a.Handle("/foo", func(m *Message) {
+		received = append(received, "a")
+	})
+	b.Handle("/foo", func(m *Message) {
+		received = append(received, "b")
+	})
+
+	a.Dispatch(NewMessage("/foo"))
+	a.Swap(&b)
+	a.Dispatch(NewMessage("/foo"))
+
+	if len(received) != 2 || received[0] != "a" || received[1] != "b" {
+		t.Errorf("Got %v, expected [a b]", received)
+	}
+}
+
+func TestHandleFiltered(t *testing.T) {
+	var a AddressSpace
+	var received int
+
+	a.HandleFiltered("/button", func(m *Message) {
+		received++
+	}, ArgumentCount(1), ArgumentEquals(0, int32(1)))
+
+	pressed := NewMessage("/button")
+	pressed.AddArgument(int32(1))
+	released := NewMessage("/button")
+	released.AddArgument(int32(0))
+
+	a.Dispatch(pressed)
+	a.Dispatch(released)
+	a.Dispatch(NewMessage("/button"))
+
+	if received != 1 {
+		t.Errorf("Handler invoked %d times, expected 1", received)
+	}
+}
+
+func TestArgumentSignature(t *testing.T) {
+	var a AddressSpace
+	var received int
+
+	sig, _ := ParseSignature("if")
+	a.HandleFiltered("/fader", func(m *Message) {
+		received++
+	}, ArgumentSignature(sig))
+
+	valid := NewMessage("/fader")
+	valid.AddArgument(int32(1))
+	valid.AddArgument(float32(0.5))
+	invalid := NewMessage("/fader")
+	invalid.AddArgument(float32(0.5))
+
+	a.Dispatch(valid)
+	a.Dispatch(invalid)
+
+	if received != 1 {
+		t.Errorf("Handler invoked %d times, expected 1", received)
+	}
+}
+
+func TestAddressCase(t *testing.T) {
+	var a AddressSpace
+	var received []string
+
+	a.Handle("/Mixer/[A-C]/fader", func(m *Message) {
+		received = append(received, m.Address)
+	})
+
+	// Case sensitive by default
+	a.Dispatch(NewMessage("/mixer/b/FADER"))
+
+	a.SetAddressCase(CaseInsensitive)
+	a.Dispatch(NewMessage("/mixer/b/FADER"))
+
+	a.SetAddressCase(LowerCase)
+	a.Dispatch(NewMessage("/MIXER/B/Fader"))
+
+	expected := []string{"/mixer/b/FADER", "/mixer/b/fader"}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("Got %v, expected %v", received, expected)
+	}
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	test1 := "/foo/"
+	expected1 := "/foo"
+	result1 := normalizeAddress(test1)
+
+	if result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	test2 := "//foo///bar//"
+	expected2 := "/foo/bar"
+	result2 := normalizeAddress(test2)
+
+	if result2 != expected2 {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+
+	test3 := "/"
+	expected3 := "/"
+	result3 := normalizeAddress(test3)
+
+	if result3 != expected3 {
+		t.Errorf("Got %v, expected %v", result3, expected3)
+	}
+
+	var a AddressSpace
+	var received []string
+
+	a.Handle("/foo/bar", func(m *Message) {
+		received = append(received, m.Address)
+	})
+
+	a.Dispatch(NewMessage("/foo//bar/"))
+	a.SetNormalizeAddresses(true)
+	a.Dispatch(NewMessage("/foo//bar/"))
+
+	if len(received) != 1 || received[0] != "/foo/bar" {
+		t.Errorf("Got %v, expected [/foo/bar]", received)
+	}
+}
+
+func TestDispatchMode(t *testing.T) {
+	var a AddressSpace
+	var goroutines [][]byte
+	var mu sync.Mutex
+
+	a.Handle("/outer", func(m *Message) {
+		// Messages dispatched by a handler must not deadlock
+		a.Dispatch(NewMessage("/inner"))
+	})
+	a.Handle("/*", func(m *Message) {
+		mu.Lock()
+		goroutines = append(goroutines, currentGoroutine())
+		mu.Unlock()
+	})
+
+	a.SetDispatchMode(LockedThreadDispatch)
+	defer a.SetDispatchMode(ConcurrentDispatch)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.Dispatch(NewMessage("/outer"))
+		}()
+	}
+	wg.Wait()
+
+	if len(goroutines) != 8 {
+		t.Fatalf("Got %d handler calls, expected 8", len(goroutines))
+	}
+
+	for _, g := range goroutines {
+		if !bytes.Equal(g, goroutines[0]) {
+			t.Errorf("Handlers called on %s and %s, expected one goroutine", goroutines[0], g)
+		}
+	}
+}
+
+func TestHandleGroup(t *testing.T) {
+	var a AddressSpace
+	var received []string
+
+	handler := func(m *Message) {
+		received = append(received, m.Address)
+	}
+
+	err := a.HandleGroup(map[string]MessageHandleFunc{
+		"/mixer/fader": handler,
+		"/mixer/mute":  handler,
+		"invalid":      handler,
+	})
+	if err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	} else if len(a.Methods()) != 0 {
+		t.Errorf("Got %d methods, expected none after a failed registration", len(a.Methods()))
+	}
+
+	err = a.HandleGroup(map[string]MessageHandleFunc{
+		"/mixer/fader": handler,
+		"/mixer/mute":  handler,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.Dispatch(NewMessage("/mixer/mute"))
+	a.Dispatch(NewMessage("/mixer/fader"))
+
+	expected := []string{"/mixer/mute", "/mixer/fader"}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("Got %v, expected %v", received, expected)
+	}
+}
@@ -0,0 +1,178 @@
+package osc
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	// AuthChallengeAddress is the address of the challenge sent by a server to a newly connected TCP client.
+	AuthChallengeAddress = "/auth/challenge"
+	// AuthResponseAddress is the address of the client's response to an authentication challenge.
+	AuthResponseAddress = "/auth/response"
+	// AuthOKAddress is the address of the message sent by the server when authentication succeeds.
+	AuthOKAddress = "/auth/ok"
+	// AuthFailedAddress is the address of the message sent by the server when authentication fails.
+	AuthFailedAddress = "/auth/failed"
+
+	// The number of random bytes in an authentication challenge
+	authNonceSize = 16
+)
+
+// The time a client has to answer an authentication challenge before it is disconnected
+var authTimeout = 10 * time.Second
+
+/*
+CredentialsFunc returns the shared secret of the named user, or false if there is no such user.
+*/
+type CredentialsFunc func(user string) (secret []byte, ok bool)
+
+/*
+SetCredentials requires TCP clients to authenticate before their messages are dispatched. When a client connects, the
+server sends an "/auth/challenge" message containing a random nonce (blob). The client must reply with an
+"/auth/response" message containing its user name (string) and the HMAC-SHA256 of the nonce keyed with its secret
+(blob). The server replies with "/auth/ok", or "/auth/failed" before closing the connection. Passing nil disables
+authentication. It must be called before StartListening.
+*/
+func (s *TCPServer) SetCredentials(fn CredentialsFunc) {
+	s.credentials = fn
+}
+
+/*
+authenticate performs the server side of the challenge/response handshake on a newly accepted connection, failing if
+the client does not respond within authTimeout.
+*/
+func authenticate(conn net.Conn, reader *bufio.Reader, framing Framing, send func(p Packet) error,
+	credentials CredentialsFunc) error {
+	nonce := make([]byte, authNonceSize)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+
+	err = conn.SetReadDeadline(time.Now().Add(authTimeout))
+	if err != nil {
+		return err
+	}
+
+	challenge := NewMessage(AuthChallengeAddress)
+	challenge.AddArgument(nonce)
+
+	err = send(challenge)
+	if err != nil {
+		return err
+	}
+
+	data, err := framing.ReadPacket(reader)
+	if err != nil {
+		return err
+	}
+
+	response, err := NewMessageFromData(data)
+	if err != nil || response.Address != AuthResponseAddress || len(response.Arguments) != 2 {
+		send(NewMessage(AuthFailedAddress))
+		return fmt.Errorf("Malformed authentication response")
+	}
+
+	user, userOk := response.Arguments[0].(string)
+	mac, macOk := response.Arguments[1].([]byte)
+	if !userOk || !macOk {
+		send(NewMessage(AuthFailedAddress))
+		return fmt.Errorf("Malformed authentication response")
+	}
+
+	secret, ok := credentials(user)
+	if !ok || !hmac.Equal(mac, authMAC(secret, nonce)) {
+		send(NewMessage(AuthFailedAddress))
+		return fmt.Errorf("Authentication failed for user \"%s\"", user)
+	}
+
+	err = conn.SetReadDeadline(time.Time{})
+	if err != nil {
+		return err
+	}
+
+	return send(NewMessage(AuthOKAddress))
+}
+
+/*
+authMAC computes the response to an authentication challenge.
+*/
+func authMAC(secret, nonce []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(nonce)
+
+	return mac.Sum(nil)
+}
+
+/*
+authOptions holds the credentials a TCP client uses to answer authentication challenges.
+*/
+type authOptions struct {
+	user   string
+	secret []byte
+}
+
+/*
+SetCredentials sets the user name and shared secret used to authenticate with a server that requires it (see
+TCPServer.SetCredentials). When set, Connect waits for the server's challenge and returns an error if authentication
+fails. It must be called before Connect.
+*/
+func (o *authOptions) SetCredentials(user string, secret []byte) {
+	o.user = user
+	o.secret = secret
+}
+
+/*
+login performs the client side of the challenge/response handshake on a newly established connection, failing if the
+server does not complete it within authTimeout.
+*/
+func (c *TCPClient) login(reader *bufio.Reader) error {
+	framing := c.framingOrDefault(LengthPrefix)
+
+	err := c.conn.SetReadDeadline(time.Now().Add(authTimeout))
+	if err != nil {
+		return err
+	}
+
+	data, err := framing.ReadPacket(reader)
+	if err != nil {
+		return err
+	}
+
+	challenge, err := NewMessageFromData(data)
+	if err != nil || challenge.Address != AuthChallengeAddress || len(challenge.Arguments) != 1 {
+		return fmt.Errorf("Server did not send an authentication challenge")
+	}
+
+	nonce, ok := challenge.Arguments[0].([]byte)
+	if !ok {
+		return fmt.Errorf("Server did not send an authentication challenge")
+	}
+
+	response := NewMessage(AuthResponseAddress)
+	response.AddArgument(c.user)
+	response.AddArgument(authMAC(c.secret, nonce))
+
+	err = c.Send(response)
+	if err != nil {
+		return err
+	}
+
+	data, err = framing.ReadPacket(reader)
+	if err != nil {
+		return err
+	}
+
+	result, err := NewMessageFromData(data)
+	if err != nil || result.Address != AuthOKAddress {
+		return fmt.Errorf("Authentication failed")
+	}
+
+	return c.conn.SetReadDeadline(time.Time{})
+}
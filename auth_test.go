@@ -0,0 +1,151 @@
+package osc
+
+import (
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTCPAuthentication(t *testing.T) {
+	logger := &testLogger{}
+	server := &TCPServer{}
+	server.SetLocalAddr("127.0.0.1", 0)
+	server.SetLogger(logger)
+	server.SetCredentials(func(user string) ([]byte, bool) {
+		return []byte("secret"), user == "console"
+	})
+
+	received := make(chan *Message, 1)
+	server.Handle("/go", func(m *Message) {
+		received <- m
+	})
+
+	err := server.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopListening()
+
+	port := server.LocalAddr().(*net.TCPAddr).Port
+
+	// A client with the wrong secret is rejected
+	client1 := &TCPClient{}
+	client1.SetAddr("127.0.0.1", port)
+	client1.SetCredentials("console", []byte("wrong"))
+
+	if err := client1.Connect(); err == nil {
+		t.Error("Expected an error connecting with the wrong secret")
+	}
+
+	// The failure is logged once the server has closed the connection
+	deadline := time.Now().Add(time.Second)
+	for logger.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if result1 := logger.count(); result1 != 1 {
+		t.Errorf("Got %d log lines, expected 1", result1)
+	}
+
+	// A client with the right secret can send messages
+	client2 := &TCPClient{}
+	client2.SetAddr("127.0.0.1", port)
+	client2.SetCredentials("console", []byte("secret"))
+
+	if err := client2.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client2.Disconnect()
+
+	client2.Send(NewMessage("/go"))
+
+	select {
+	case m := <-received:
+		if m.Address != "/go" {
+			t.Errorf("Got %v, expected /go", m)
+		}
+	case <-time.After(time.Second):
+		t.Error("Message was not received")
+	}
+}
+
+func TestTCPAuthenticationTimeout(t *testing.T) {
+	timeout := authTimeout
+	authTimeout = 50 * time.Millisecond
+	defer func() {
+		authTimeout = timeout
+	}()
+
+	server := &TCPServer{}
+	server.SetLocalAddr("127.0.0.1", 0)
+	server.SetCredentials(func(user string) ([]byte, bool) {
+		return []byte("secret"), true
+	})
+
+	err := server.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopListening()
+
+	conn, err := net.Dial("tcp", server.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// A client which never answers the challenge is disconnected
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = ioutil.ReadAll(conn)
+	if err != nil {
+		t.Errorf("Got %v, expected the connection to be closed", err)
+	}
+}
+
+func TestTCPClientLoginTimeout(t *testing.T) {
+	timeout := authTimeout
+	authTimeout = 50 * time.Millisecond
+	defer func() {
+		authTimeout = timeout
+	}()
+
+	listener, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// The server accepts the connection, but never sends a challenge
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+
+	client := &TCPClient{}
+	client.SetAddr("127.0.0.1", listener.Addr().(*net.TCPAddr).Port)
+	client.SetCredentials("operator", []byte("secret"))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Connect()
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Expected an error when the server sends no challenge")
+		}
+	case <-time.After(time.Second):
+		t.Error("Connect did not time out waiting for the challenge")
+	}
+
+	select {
+	case conn := <-accepted:
+		conn.Close()
+	default:
+	}
+}
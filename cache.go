@@ -0,0 +1,105 @@
+package osc
+
+import (
+	"errors"
+	"strings"
+)
+
+// GetSuffix is appended to an address to form the address of a query for its cached value (see SetValueCache).
+const GetSuffix = "/get"
+
+// DefaultValueCacheLimit is the number of addresses whose values are cached, unless changed with SetValueCacheLimit.
+const DefaultValueCacheLimit = 4096
+
+/*
+SetValueCache makes the AddressSpace remember the last value (the arguments of the last message) received at each
+address in cache, and answer queries for them, so that controllers can fetch the current state when they connect. A
+message to an address followed by "/get" (e.g. "/mixer/fader/get") is answered with a "/reply" message containing the
+address and its cached value, or with an "/error" message if no value has been received, as described by HandleReply;
+queries are not dispatched to methods. Only values accepted by a method (see HandleFrom) are cached, after being
+dispatched as usual, and queries are only answered for peers permitted by a method handling the queried address;
+other queries are dispatched as usual.
+
+The cache may be shared with other code, e.g. to save it with SaveSnapshot, or to set initial values. If cache is nil,
+caching is disabled (the default). Values are only cached for a limited number of addresses (see SetValueCacheLimit),
+so that senders cannot exhaust memory with messages to ever more addresses.
+*/
+func (a *AddressSpace) SetValueCache(cache *ParameterSpace) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.cache = cache
+}
+
+/*
+SetValueCacheLimit sets the number of addresses whose values are cached by SetValueCache. Once the cache holds values
+for that many addresses, the values of other addresses are no longer cached, though cached values are still updated.
+The default is DefaultValueCacheLimit.
+*/
+func (a *AddressSpace) SetValueCacheLimit(n int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.cacheLimit = n
+}
+
+/*
+cacheQuery returns the address whose cached value is queried by m, or false if m is not a query.
+*/
+func cacheQuery(m *Message) (string, bool) {
+	if !strings.HasSuffix(m.Address, GetSuffix) || m.Address == GetSuffix {
+		return "", false
+	}
+
+	return strings.TrimSuffix(m.Address, GetSuffix), true
+}
+
+/*
+queryPermitted returns true if a method handling address accepts messages from peer, so that peers can only read the
+values they could set.
+*/
+func queryPermitted(methods []Method, disabled map[string]bool, address string, addressCase AddressCase,
+	peer *Peer) bool {
+	for _, h := range methods {
+		if h.Group != "" && disabled[h.Group] {
+			continue
+		}
+
+		if matchAddress(h.AddressPattern, address, addressCase) && h.permits(peer) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+answerCacheQuery answers a query for the cached value of address.
+*/
+func answerCacheQuery(cache *ParameterSpace, address string, peer *Peer) {
+	var value interface{}
+	var err error
+	if args, ok := cache.Get(address); ok {
+		value = args
+	} else {
+		err = errors.New("No value")
+	}
+
+	replyResult(&Message{Address: address}, peer, value, err)
+}
+
+/*
+cacheValue caches the value of a message which is not a query, unless limit addresses already have cached values.
+*/
+func cacheValue(cache *ParameterSpace, limit int, m *Message) {
+	if _, query := cacheQuery(m); query {
+		return
+	}
+
+	if limit <= 0 {
+		limit = DefaultValueCacheLimit
+	}
+
+	// The message may be pooled, so its arguments are copied
+	cache.setLimited(m.Address, limit, m.Clone().Arguments...)
+}
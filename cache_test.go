@@ -0,0 +1,133 @@
+package osc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestValueCache(t *testing.T) {
+	var replies []Packet
+	peer := newPeer(&net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 9000}, func(p Packet) error {
+		replies = append(replies, p)
+		return nil
+	})
+
+	var a AddressSpace
+	var handled []string
+	a.Handle("//*", func(m *Message) {
+		handled = append(handled, m.Address)
+	})
+
+	cache := NewParameterSpace()
+	a.SetValueCache(cache)
+
+	fader := NewMessage("/mixer/fader")
+	fader.AddArgument(float32(0.75))
+	a.DispatchFrom(fader, peer)
+	a.DispatchFrom(NewMessage("/mixer/fader/get"), peer)
+	a.DispatchFrom(NewMessage("/mixer/mute/get"), peer)
+
+	// Queries are answered, not dispatched
+	if len(handled) != 1 || handled[0] != "/mixer/fader" {
+		t.Errorf("Got %v, expected [/mixer/fader]", handled)
+	}
+
+	if len(replies) != 2 {
+		t.Fatalf("Got %d replies, expected 2", len(replies))
+	}
+
+	expected1 := "Message: /reply (s)/mixer/fader (f)0.75"
+	if result1 := replies[0].String(); result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	expected2 := "Message: /error (s)/mixer/mute (s)No value"
+	if result2 := replies[1].String(); result2 != expected2 {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+
+	if _, ok := cache.Get("/mixer/fader"); !ok {
+		t.Errorf("Got no cached value for /mixer/fader")
+	}
+}
+
+func TestValueCacheLimit(t *testing.T) {
+	var a AddressSpace
+	a.Handle("//*", func(*Message) {})
+	cache := NewParameterSpace()
+	a.SetValueCache(cache)
+	a.SetValueCacheLimit(2)
+
+	for _, address := range []string{"/fader/1", "/fader/2", "/fader/3"} {
+		a.Dispatch(NewMessage(address))
+	}
+
+	// Once the limit is reached, only values already cached are updated
+	fader := NewMessage("/fader/1")
+	fader.AddArgument(float32(0.5))
+	a.Dispatch(fader)
+
+	if _, ok := cache.Get("/fader/3"); ok {
+		t.Errorf("Got a cached value for /fader/3, expected none")
+	}
+
+	result1, _ := cache.Get("/fader/1")
+	if len(result1) != 1 || result1[0] != float32(0.5) {
+		t.Errorf("Got %v, expected [0.5]", result1)
+	}
+}
+
+func TestValueCacheACL(t *testing.T) {
+	var replies []Packet
+	reply := func(p Packet) error {
+		replies = append(replies, p)
+		return nil
+	}
+	allowed := newPeer(&net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 9000}, reply)
+	rejected := newPeer(&net.UDPAddr{IP: net.IPv4(192, 168, 1, 1), Port: 9000}, reply)
+
+	acl, err := ParseACL("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var a AddressSpace
+	var handled []string
+	a.HandleFrom("/mixer/*", func(m *Message) {
+		handled = append(handled, m.Address)
+	}, acl)
+
+	cache := NewParameterSpace()
+	a.SetValueCache(cache)
+
+	// Values from rejected peers are not cached
+	fader := NewMessage("/mixer/fader")
+	fader.AddArgument(float32(0.75))
+	a.DispatchFrom(fader, rejected)
+
+	if _, ok := cache.Get("/mixer/fader"); ok {
+		t.Errorf("Got a cached value for /mixer/fader, expected none")
+	}
+
+	a.DispatchFrom(fader, allowed)
+
+	// Queries from rejected peers are not answered
+	a.DispatchFrom(NewMessage("/mixer/fader/get"), rejected)
+	if len(replies) != 0 {
+		t.Errorf("Got %v, expected no replies", replies)
+	}
+
+	a.DispatchFrom(NewMessage("/mixer/fader/get"), allowed)
+	if len(replies) != 1 {
+		t.Fatalf("Got %d replies, expected 1", len(replies))
+	}
+
+	expected1 := "Message: /reply (s)/mixer/fader (f)0.75"
+	if result1 := replies[0].String(); result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	if len(handled) != 1 || handled[0] != "/mixer/fader" {
+		t.Errorf("Got %v, expected [/mixer/fader]", handled)
+	}
+}
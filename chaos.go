@@ -0,0 +1,206 @@
+package osc
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// The longest time a packet is held back to be reordered, if no other packet is sent
+const chaosHoldTime = 50 * time.Millisecond
+
+/*
+ChaosConfig configures fault injection, for testing how applications cope with unreliable networks. Rates are
+probabilities from 0 to 1, applied independently to each packet.
+*/
+type ChaosConfig struct {
+	// DropRate is the probability that a packet is lost
+	DropRate float64
+	// DuplicateRate is the probability that a packet is delivered twice
+	DuplicateRate float64
+	// ReorderRate is the probability that a packet is held back, and delivered after the next packet
+	ReorderRate float64
+	// Jitter is the maximum random delay added to each packet
+	Jitter time.Duration
+	// Seed seeds the random number generator, so that faults are reproducible
+	Seed int64
+}
+
+/*
+chaos decides the fate of each packet according to a ChaosConfig.
+*/
+type chaos struct {
+	config ChaosConfig
+
+	mu   sync.Mutex
+	rand *rand.Rand
+	held *heldPacket
+}
+
+/*
+heldPacket is a packet held back to be delivered after the next one.
+*/
+type heldPacket struct {
+	once    sync.Once
+	deliver func() error
+}
+
+func (h *heldPacket) release() {
+	h.once.Do(func() {
+		h.deliver()
+	})
+}
+
+func newChaos(config ChaosConfig) *chaos {
+	return &chaos{config: config, rand: rand.New(rand.NewSource(config.Seed))}
+}
+
+/*
+deliver delivers a packet by calling send, possibly after a delay, more than once, out of order, or not at all. The
+error returned by send is returned if it is called before deliver returns; otherwise nil is returned.
+*/
+func (c *chaos) deliver(send func() error) error {
+	c.mu.Lock()
+
+	if c.rand.Float64() < c.config.DropRate {
+		c.mu.Unlock()
+		return nil
+	}
+
+	duplicate := c.rand.Float64() < c.config.DuplicateRate
+
+	var delay time.Duration
+	if c.config.Jitter > 0 {
+		delay = time.Duration(c.rand.Int63n(int64(c.config.Jitter)))
+	}
+
+	packet := func() error {
+		err := send()
+		if duplicate {
+			send()
+		}
+
+		return err
+	}
+
+	if c.held == nil && c.rand.Float64() < c.config.ReorderRate {
+		held := &heldPacket{deliver: packet}
+		c.held = held
+		c.mu.Unlock()
+
+		time.AfterFunc(chaosHoldTime, func() {
+			c.mu.Lock()
+			if c.held == held {
+				c.held = nil
+			}
+			c.mu.Unlock()
+
+			held.release()
+		})
+		return nil
+	}
+
+	held := c.held
+	c.held = nil
+	c.mu.Unlock()
+
+	if delay > 0 {
+		time.AfterFunc(delay, func() {
+			packet()
+			if held != nil {
+				held.release()
+			}
+		})
+		return nil
+	}
+
+	err := packet()
+	if held != nil {
+		held.release()
+	}
+
+	return err
+}
+
+/*
+ChaosTransport wraps a Transport, injecting faults into the packets sent and received over it.
+*/
+type ChaosTransport struct {
+	transport Transport
+	send      *chaos
+	receive   *chaos
+}
+
+/*
+NewChaosTransport creates a ChaosTransport injecting faults according to config into packets sent and received over
+transport.
+*/
+func NewChaosTransport(transport Transport, config ChaosConfig) *ChaosTransport {
+	receiveConfig := config
+	receiveConfig.Seed++
+
+	return &ChaosTransport{transport: transport, send: newChaos(config), receive: newChaos(receiveConfig)}
+}
+
+/*
+Open opens the underlying transport.
+*/
+func (t *ChaosTransport) Open(receive func(data []byte)) error {
+	return t.transport.Open(func(data []byte) {
+		t.receive.deliver(func() error {
+			receive(data)
+			return nil
+		})
+	})
+}
+
+/*
+Send sends the data of a packet over the underlying transport, unless it is dropped. Packets which are delayed are sent
+in the background, and any error is discarded.
+*/
+func (t *ChaosTransport) Send(data []byte) error {
+	return t.send.deliver(func() error {
+		return t.transport.Send(data)
+	})
+}
+
+/*
+Close closes the underlying transport.
+*/
+func (t *ChaosTransport) Close() error {
+	return t.transport.Close()
+}
+
+/*
+ChaosClient wraps a Client, injecting faults into the packets sent with it.
+*/
+type ChaosClient struct {
+	Client
+
+	chaos *chaos
+}
+
+/*
+NewChaosClient creates a ChaosClient injecting faults according to config into packets sent with client.
+*/
+func NewChaosClient(client Client, config ChaosConfig) *ChaosClient {
+	return &ChaosClient{Client: client, chaos: newChaos(config)}
+}
+
+/*
+Send sends an OSC packet, unless it is dropped. Packets which are delayed are sent in the background, and any error is
+discarded.
+*/
+func (c *ChaosClient) Send(p Packet) error {
+	return c.chaos.deliver(func() error {
+		return c.Client.Send(p)
+	})
+}
+
+/*
+SendAt sends an OSC packet wrapped in a bundle with a time tag of t plus the wrapped client's time tag offset, unless
+it is dropped.
+*/
+func (c *ChaosClient) SendAt(p Packet, t time.Time) error {
+	return c.Send(newTimedBundle(p, offsetTime(c.Client, t)))
+}
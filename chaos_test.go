@@ -0,0 +1,74 @@
+package osc
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedClient records the packets sent with it, from any goroutine.
+type lockedClient struct {
+	testClient
+	mu sync.Mutex
+}
+
+func (c *lockedClient) Send(p Packet) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.testClient.Send(p)
+}
+
+func (c *lockedClient) addresses() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var addresses []string
+	for _, p := range c.sent {
+		addresses = append(addresses, p.(*Message).Address)
+	}
+
+	return addresses
+}
+
+func TestChaosClient(t *testing.T) {
+	client := &testClient{}
+	chaos := NewChaosClient(client, ChaosConfig{DropRate: 0.5, Seed: 1})
+
+	for i := 0; i < 1000; i++ {
+		chaos.Send(NewMessage("/level"))
+	}
+
+	if sent := len(client.sent); sent < 400 || sent > 600 {
+		t.Errorf("Got %d packets sent, expected about 500", sent)
+	}
+
+	client.sent = nil
+	chaos = NewChaosClient(client, ChaosConfig{DuplicateRate: 1})
+	chaos.Send(NewMessage("/level"))
+
+	if len(client.sent) != 2 {
+		t.Errorf("Got %d packets sent, expected 2", len(client.sent))
+	}
+}
+
+func TestChaosReorder(t *testing.T) {
+	client := &lockedClient{}
+	chaos := NewChaosClient(client, ChaosConfig{ReorderRate: 1})
+
+	chaos.Send(NewMessage("/first"))
+	chaos.Send(NewMessage("/second"))
+
+	sent := client.addresses()
+	if len(sent) != 2 || sent[0] != "/second" {
+		t.Fatalf("Got %v, expected [/second /first]", sent)
+	}
+
+	// A held packet is eventually delivered even if no other packet is sent
+	chaos.Send(NewMessage("/third"))
+	time.Sleep(2 * chaosHoldTime)
+
+	if sent := client.addresses(); len(sent) != 3 {
+		t.Errorf("Got %v, expected 3 packets", sent)
+	}
+}
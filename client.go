@@ -1,13 +1,18 @@
 package osc
 
 import (
-	"bufio"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"sync"
+	"time"
 )
 
+// The maximum size of a packet received over a stream transport
+const maxStreamPacketSize = 1 << 20
+
 /*
 Client represents an OSC client (UDP or TCP) that can send OSC packets to a remote host.
 */
@@ -18,16 +23,41 @@ type Client interface {
 	Disconnect() error
 	IsConnected() bool
 	Send(p Packet) error
+	SendAt(p Packet, t time.Time) error
+	Stats() ClientStats
+	Healthy() bool
+	Transaction() *Transaction
+	WithPrefix(prefix string) Client
+}
+
+/*
+newTimedBundle wraps a packet in a bundle to be executed at time t.
+*/
+func newTimedBundle(p Packet, t time.Time) *Bundle {
+	bundle := NewBundle()
+	bundle.TimeTag = NewTimeTag(t)
+	bundle.AddPacket(p)
+
+	return bundle
 }
 
 /*
 UDPClient provides functionality to send OSC messages over UDP.
+It also contains an AddressSpace to handle responses sent back to the client's socket.
 */
 type UDPClient struct {
-	addr      *net.UDPAddr
-	localAddr *net.UDPAddr
-	conn      *net.UDPConn
-	connected bool
+	addr        *net.UDPAddr
+	localAddr   *net.UDPAddr
+	conn        *net.UDPConn
+	connected   bool
+	discoverMTU bool
+	dialOptions
+	multicastOptions
+	compatOptions
+	timeOffsetOptions
+	sendStats
+
+	AddressSpace
 }
 
 // Compile-time check to ensure UDPClient implements the Client interface.
@@ -84,13 +114,46 @@ func (c *UDPClient) Connect() error {
 		return err
 	}
 
+	err = c.applyDialOptions(conn, c.addr.IP)
+	if err == nil {
+		err = c.applyMulticastOptions(conn, c.addr.IP)
+	}
+	if err == nil {
+		err = c.applyMTUDiscovery(conn)
+	}
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
 	c.conn = conn
 
 	c.connected = true
 
+	go c.responseReaderLoop(conn)
+
 	return nil
 }
 
+/*
+responseReaderLoop dispatches the datagrams received from the remote host to the client's AddressSpace, until the
+connection is closed.
+*/
+func (c *UDPClient) responseReaderLoop(conn *net.UDPConn) {
+	for {
+		buf := make([]byte, udpReadBufSize)
+		n, err := conn.Read(buf)
+		if errors.Is(err, net.ErrClosed) {
+			return
+		} else if err != nil {
+			// e.g. the remote port was unreachable when a previous packet was sent
+			continue
+		}
+
+		c.AddressSpace.dispatchData(buf[:n], newPeer(conn.RemoteAddr(), c.Send))
+	}
+}
+
 /*
 Disconnect disconnects the client from the remote host.
 */
@@ -105,7 +168,7 @@ func (c *UDPClient) Disconnect() error {
 /*
 IsConnected returns true if the client is connected to the remote host.
 */
-func (c UDPClient) IsConnected() bool {
+func (c *UDPClient) IsConnected() bool {
 	return c.conn != nil && c.connected
 }
 
@@ -113,21 +176,37 @@ func (c UDPClient) IsConnected() bool {
 Send sends an OSC packet (message or bundle) from this client.
 */
 func (c *UDPClient) Send(p Packet) error {
+	n, err := c.send(p)
+	c.recordSend(n, err)
+
+	return err
+}
+
+func (c *UDPClient) send(p Packet) (int, error) {
 	if !c.IsConnected() {
-		return fmt.Errorf("Client is not connected")
+		return 0, fmt.Errorf("Client is not connected")
 	}
 
-	data, err := p.MarshalBinary()
+	data, err := c.marshal(p)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	_, err = c.conn.Write(data)
-	if err != nil {
-		return err
+	if c.discoverMTU {
+		return c.writeWithinMTU(p, data)
 	}
 
-	return nil
+	logWire(wireOut, c.addr, data)
+
+	return c.conn.Write(data)
+}
+
+/*
+SendAt sends an OSC packet wrapped in a bundle with a time tag of t plus the client's time tag offset (see
+SetTimeTagOffset), so that the receiver executes it at that time.
+*/
+func (c *UDPClient) SendAt(p Packet, t time.Time) error {
+	return c.Send(c.timedBundle(p, t))
 }
 
 /*
@@ -138,7 +217,15 @@ type TCPClient struct {
 	addr      *net.TCPAddr
 	localAddr *net.TCPAddr
 	conn      *net.TCPConn
+	stream    *StreamTransport
+	mu        sync.Mutex
 	connected bool
+	dialOptions
+	authOptions
+	framingOptions
+	compatOptions
+	timeOffsetOptions
+	sendStats
 
 	AddressSpace
 }
@@ -197,44 +284,44 @@ func (c *TCPClient) Connect() error {
 		return err
 	}
 
-	go c.responseReaderLoop()
-
-	c.conn = conn
-
-	return nil
-}
+	err = c.applyDialOptions(conn, c.addr.IP)
+	if err != nil {
+		conn.Close()
+		return err
+	}
 
-func (c *TCPClient) responseReaderLoop() {
-	buf := make([]byte, 65535)
-	reader := bufio.NewReader(c.conn)
+	stream := NewStreamTransport(conn, c.framingOrDefault(LengthPrefix))
+	stream.SetLogger(loggerFunc(c.AddressSpace.logf))
+	stream.onClose = func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
 
-	for {
-		var count uint32
-		err := binary.Read(reader, binary.BigEndian, &count)
-		if err != nil {
-			fmt.Println("WARNING found malformed packet")
-			break
+		// A later call to Connect may already have replaced the stream
+		if c.stream == stream {
+			c.connected = false
 		}
+	}
 
-		_, err = io.ReadFull(reader, buf[:int(count)])
-		if err != nil {
-			fmt.Println("WARNING found malformed packet")
-			break
-		}
+	c.mu.Lock()
+	c.conn = conn
+	c.stream = stream
+	c.connected = true
+	c.mu.Unlock()
 
-		p, err := decodePacket(buf[:int(count)])
+	if c.secret != nil {
+		err = c.login(stream.reader)
 		if err != nil {
-			fmt.Println(err)
-			continue
-		}
-
-		switch p.(type) {
-		case *Message:
-			c.AddressSpace.Dispatch(p.(*Message))
-		case *Bundle:
-			fmt.Println("ERROR bundles not yet supported")
+			c.mu.Lock()
+			c.connected = false
+			c.mu.Unlock()
+			conn.Close()
+			return err
 		}
 	}
+
+	return stream.Open(func(data []byte) {
+		c.AddressSpace.dispatchData(data, newPeer(conn.RemoteAddr(), c.Send))
+	})
 }
 
 /*
@@ -245,9 +332,12 @@ func (c *TCPClient) Disconnect() error {
 }
 
 /*
-IsConnected returns true if the client is connected to the remote host.
+IsConnected returns true if the client is connected to the remote host, and the connection has not been closed.
 */
-func (c TCPClient) IsConnected() bool {
+func (c *TCPClient) IsConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	return c.conn != nil && c.connected
 }
 
@@ -255,22 +345,66 @@ func (c TCPClient) IsConnected() bool {
 Send sends an OSC packet (message or bundle) from this client.
 */
 func (c *TCPClient) Send(p Packet) error {
-	packetEnc, err := p.MarshalBinary()
+	n, err := c.send(p)
+	c.recordSend(n, err)
+
+	return err
+}
+
+func (c *TCPClient) send(p Packet) (int, error) {
+	if !c.IsConnected() {
+		return 0, fmt.Errorf("Client is not connected")
+	}
+
+	packetEnc, err := c.marshal(p)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	// Count the data to be sent, and encode as uint32 (OSC 1.0)
-	count := len(packetEnc)
-	countEnc := make([]byte, 4)
-	binary.BigEndian.PutUint32(countEnc, uint32(count))
+	logWire(wireOut, c.addr, packetEnc)
 
-	data := append(countEnc, packetEnc...)
+	return c.stream.send(packetEnc)
+}
+
+/*
+encodeLengthPrefixed frames a packet for a stream transport by prefixing it with its length (OSC 1.0).
+*/
+func encodeLengthPrefixed(packet []byte) []byte {
+	// Count the data to be sent, and encode as uint32
+	data := make([]byte, 4, 4+len(packet))
+	binary.BigEndian.PutUint32(data, uint32(len(packet)))
+
+	return append(data, packet...)
+}
 
-	_, err = c.conn.Write(data)
+/*
+readLengthPrefixed reads the next packet framed with a length prefix (OSC 1.0) from a stream. An error is returned if
+the packet is larger than maxSize bytes.
+*/
+func readLengthPrefixed(reader io.Reader, maxSize int) ([]byte, error) {
+	var count uint32
+	err := binary.Read(reader, binary.BigEndian, &count)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	if int64(count) > int64(maxSize) {
+		return nil, fmt.Errorf("Packet size %d exceeds limit of %d bytes", count, maxSize)
+	}
+
+	data := make([]byte, count)
+	_, err = io.ReadFull(reader, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+/*
+SendAt sends an OSC packet wrapped in a bundle with a time tag of t plus the client's time tag offset (see
+SetTimeTagOffset), so that the receiver executes it at that time.
+*/
+func (c *TCPClient) SendAt(p Packet, t time.Time) error {
+	return c.Send(c.timedBundle(p, t))
 }
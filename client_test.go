@@ -0,0 +1,83 @@
+package osc
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestUDPClientSendAt(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client, _ := NewUDPClient("127.0.0.1", conn.LocalAddr().(*net.UDPAddr).Port)
+	err = client.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	at := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	err = client.SendAt(NewMessage("/cue/go"), at)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The message is sent in a bundle to be executed at the given time
+	bundle, err := NewBundleFromData(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result1 := bundle.TimeTag.Time()
+	if !result1.Equal(at) {
+		t.Errorf("Got %v, expected %v", result1, at)
+	}
+
+	if len(bundle.Elements) != 1 || bundle.Elements[0].(*Message).Address != "/cue/go" {
+		t.Errorf("Got %v, expected a bundle containing /cue/go", bundle)
+	}
+}
+
+func TestTCPClientClosedByServer(t *testing.T) {
+	listener, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			conn.Close()
+		}
+	}()
+
+	client := &TCPClient{}
+	client.SetAddr("127.0.0.1", listener.Addr().(*net.TCPAddr).Port)
+	err = client.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	// The client notices that the server closed the connection
+	deadline := time.Now().Add(time.Second)
+	for client.IsConnected() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if client.IsConnected() {
+		t.Error("Client is still connected after the server closed the connection")
+	}
+}
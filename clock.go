@@ -0,0 +1,131 @@
+package osc
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+Clock provides the current time and timers, so that time-dependent behaviour (e.g. the scheduling of time-tagged
+bundles) can be tested deterministically with a SimulatedClock instead of waiting in real time.
+*/
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// AfterFunc calls fn in its own goroutine once d has elapsed, unless the returned Timer is stopped first.
+	AfterFunc(d time.Duration, fn func()) Timer
+}
+
+/*
+Timer is a pending call created by Clock.AfterFunc.
+*/
+type Timer interface {
+	// Stop prevents the call from happening, returning false if it has already happened or been stopped.
+	Stop() bool
+}
+
+// SystemClock is the Clock of the operating system, used by default.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, fn func()) Timer {
+	return time.AfterFunc(d, fn)
+}
+
+/*
+SimulatedClock is a Clock whose time only moves when it is advanced, for tests. Timers due when the clock is advanced
+are called in order of their due time, on the goroutine advancing the clock. It is safe for concurrent use.
+*/
+type SimulatedClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*simulatedTimer
+}
+
+type simulatedTimer struct {
+	clock *SimulatedClock
+	due   time.Time
+	fn    func()
+}
+
+/*
+NewSimulatedClock creates a SimulatedClock set to start.
+*/
+func NewSimulatedClock(start time.Time) *SimulatedClock {
+	return &SimulatedClock{now: start}
+}
+
+/*
+Now returns the simulated time.
+*/
+func (c *SimulatedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+/*
+AfterFunc calls fn once the clock has been advanced by d.
+*/
+func (c *SimulatedClock) AfterFunc(d time.Duration, fn func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &simulatedTimer{clock: c, due: c.now.Add(d), fn: fn}
+	c.timers = append(c.timers, t)
+
+	return t
+}
+
+/*
+Advance moves the clock forward by d, calling the timers that become due along the way. Timers created by those calls
+are also called if they become due within d.
+*/
+func (c *SimulatedClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+		sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].due.Before(c.timers[j].due) })
+
+		if len(c.timers) == 0 || c.timers[0].due.After(end) {
+			c.now = end
+			c.mu.Unlock()
+			return
+		}
+
+		t := c.timers[0]
+		c.timers = c.timers[1:]
+		if t.due.After(c.now) {
+			c.now = t.due
+		}
+		c.mu.Unlock()
+
+		t.fn()
+	}
+}
+
+func (t *simulatedTimer) Stop() bool {
+	c := t.clock
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, pending := range c.timers {
+		if pending == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,272 @@
+/*
+Command oscgen generates a typed Go client for an OSC namespace, with one method per address whose parameters match the
+address's argument types, so that mistakes in addresses and argument types are found at compile time.
+
+Usage:
+
+	oscgen -in namespace.json -out mixer.go -package mixer -type Mixer
+
+The namespace is either an OSCQuery description (as served by an OSCQuery server's root), or a JSON object mapping
+addresses to type tag strings:
+
+	{"/ch/01/mix/fader": "f", "/ch/01/mix/on": "i", "/scene/recall": "s"}
+
+The generated client wraps an osc.Client:
+
+	mixer := mixer.NewMixer(client)
+	mixer.Ch01MixFader(0.75)
+*/
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dougfinl/go-osc/codec"
+)
+
+/*
+endpoint is an address in the namespace, with its argument types.
+*/
+type endpoint struct {
+	address     string
+	typeTags    string
+	description string
+}
+
+/*
+oscQueryNode is a node of an OSCQuery namespace description.
+*/
+type oscQueryNode struct {
+	FullPath    string                   `json:"FULL_PATH"`
+	Type        string                   `json:"TYPE"`
+	Description string                   `json:"DESCRIPTION"`
+	Contents    map[string]*oscQueryNode `json:"CONTENTS"`
+}
+
+// Go types of the parameters for each type tag
+var parameterTypes = map[codec.TypeTag]string{
+	codec.TypeInt32:   "int32",
+	codec.TypeFloat32: "float32",
+	codec.TypeString:  "string",
+	codec.TypeBlob:    "[]byte",
+	codec.TypeInt64:   "int64",
+	codec.TypeTimeTag: "osc.TimeTag",
+	codec.TypeFloat64: "float64",
+	codec.TypeTrue:    "bool",
+	codec.TypeFalse:   "bool",
+	codec.TypeChar:    "osc.Char",
+	codec.TypeRGBA:    "osc.RGBA",
+	codec.TypeMIDI:    "osc.MIDIMessage",
+}
+
+// The arguments sent for type tags without data, which take no parameter
+var fixedArguments = map[codec.TypeTag]string{
+	codec.TypeNil:       "nil",
+	codec.TypeInfinitum: "osc.Infinitum{}",
+}
+
+func main() {
+	in := flag.String("in", "", "namespace description (OSCQuery or address-to-type-tags JSON); standard input if empty")
+	out := flag.String("out", "", "output file; standard output if empty")
+	pkg := flag.String("package", "main", "package name of the generated code")
+	typeName := flag.String("type", "Client", "name of the generated client type")
+	flag.Parse()
+
+	var data []byte
+	var err error
+	if *in == "" {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(*in)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	endpoints, err := parseNamespace(data)
+	if err == nil {
+		data, err = generate(*pkg, *typeName, endpoints)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(data)
+	} else if err := ioutil.WriteFile(*out, data, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+/*
+parseNamespace parses a namespace description, returning its endpoints in order of address.
+*/
+func parseNamespace(data []byte) ([]endpoint, error) {
+	var raw map[string]json.RawMessage
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []endpoint
+
+	_, hasContents := raw["CONTENTS"]
+	_, hasPath := raw["FULL_PATH"]
+	if hasContents || hasPath {
+		var root oscQueryNode
+		err := json.Unmarshal(data, &root)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoints = oscQueryEndpoints(&root, endpoints)
+	} else {
+		for address, value := range raw {
+			var typeTags string
+			err := json.Unmarshal(value, &typeTags)
+			if err != nil {
+				return nil, fmt.Errorf("Type tags of %s are not a string", address)
+			}
+
+			endpoints = append(endpoints, endpoint{address: address, typeTags: typeTags})
+		}
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].address < endpoints[j].address })
+
+	return endpoints, nil
+}
+
+/*
+oscQueryEndpoints appends the endpoints of node and its descendants to endpoints. Nodes without a type are containers.
+*/
+func oscQueryEndpoints(node *oscQueryNode, endpoints []endpoint) []endpoint {
+	if node.Type != "" && node.FullPath != "" {
+		endpoints = append(endpoints, endpoint{address: node.FullPath, typeTags: node.Type, description: node.Description})
+	}
+
+	for _, child := range node.Contents {
+		endpoints = oscQueryEndpoints(child, endpoints)
+	}
+
+	return endpoints
+}
+
+/*
+generate generates the source of a client type for the endpoints.
+*/
+func generate(pkg, typeName string, endpoints []endpoint) ([]byte, error) {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "// Code generated by oscgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/dougfinl/go-osc\"\n\n")
+	fmt.Fprintf(&b, "/*\n%s sends messages to the addresses of an OSC namespace.\n*/\n", typeName)
+	fmt.Fprintf(&b, "type %s struct {\n\tClient osc.Client\n}\n\n", typeName)
+	fmt.Fprintf(&b, "/*\nNew%s creates a %s sending with client.\n*/\n", typeName, typeName)
+	fmt.Fprintf(&b, "func New%s(client osc.Client) *%s {\n\treturn &%s{Client: client}\n}\n", typeName, typeName, typeName)
+
+	// The names declared for the client type, which generated methods must not reuse
+	reserved := map[string]bool{"Client": true, "New" + typeName: true}
+
+	names := make(map[string]string)
+	for _, e := range endpoints {
+		name := methodName(e.address)
+		if name == "" {
+			return nil, fmt.Errorf("Cannot name a method for address %s", e.address)
+		}
+		if reserved[name] {
+			return nil, fmt.Errorf("Method name %s of address %s clashes with the generated %s", name, e.address, typeName)
+		}
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("Addresses %s and %s have the same method name %s", other, e.address, name)
+		}
+		names[name] = e.address
+
+		err := generateMethod(&b, typeName, name, e)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return format.Source(b.Bytes())
+}
+
+func generateMethod(b *bytes.Buffer, typeName, name string, e endpoint) error {
+	tags, err := codec.ParseTypeTags(e.typeTags)
+	if err != nil {
+		return fmt.Errorf("Invalid type tags \"%s\" for %s: %v", e.typeTags, e.address, err)
+	}
+
+	var params, args []string
+	for i, tag := range tags {
+		if fixed, ok := fixedArguments[tag]; ok {
+			args = append(args, fixed)
+			continue
+		}
+
+		goType, ok := parameterTypes[tag]
+		if !ok {
+			return fmt.Errorf("Type tag '%c' of %s is not supported", rune(tag), e.address)
+		}
+
+		arg := fmt.Sprintf("arg%d", i)
+		params = append(params, arg+" "+goType)
+		args = append(args, arg)
+	}
+
+	doc := fmt.Sprintf("%s sends a message to %s.", name, e.address)
+	if e.description != "" {
+		doc += " " + strings.TrimSpace(e.description)
+	}
+
+	fmt.Fprintf(b, "\n/*\n%s\n*/\n", doc)
+	fmt.Fprintf(b, "func (c *%s) %s(%s) error {\n", typeName, name, strings.Join(params, ", "))
+	fmt.Fprintf(b, "\tm := osc.NewMessage(%q)\n", e.address)
+	for _, arg := range args {
+		fmt.Fprintf(b, "\tm.AddArgument(%s)\n", arg)
+	}
+	fmt.Fprintf(b, "\n\treturn c.Client.Send(m)\n}\n")
+
+	return nil
+}
+
+/*
+methodName converts an address to an exported Go identifier, e.g. "/ch/01/mix/fader" to "Ch01MixFader".
+*/
+func methodName(address string) string {
+	var b strings.Builder
+	upper := true
+
+	for _, r := range address {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "Send" + name
+	}
+
+	return name
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMethodName(t *testing.T) {
+	test1 := "/ch/01/mix/fader"
+	expected1 := "Ch01MixFader"
+	result1 := methodName(test1)
+
+	if result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	test2 := "/1/fader_2"
+	expected2 := "Send1Fader2"
+	result2 := methodName(test2)
+
+	if result2 != expected2 {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	oscQuery := `{
+		"FULL_PATH": "/",
+		"CONTENTS": {
+			"synth": {
+				"FULL_PATH": "/synth",
+				"CONTENTS": {
+					"freq": {"FULL_PATH": "/synth/freq", "TYPE": "f", "DESCRIPTION": "Oscillator frequency in Hz."},
+					"note": {"FULL_PATH": "/synth/note", "TYPE": "isT"}
+				}
+			}
+		}
+	}`
+
+	endpoints, err := parseNamespace([]byte(oscQuery))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 2 || endpoints[0].address != "/synth/freq" {
+		t.Fatalf("Got %v, expected 2 endpoints", endpoints)
+	}
+
+	src, err := generate("synth", "Synth", endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"func (c *Synth) SynthFreq(arg0 float32) error {",
+		"SynthFreq sends a message to /synth/freq. Oscillator frequency in Hz.",
+		"func (c *Synth) SynthNote(arg0 int32, arg1 string, arg2 bool) error {",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("Generated code does not contain %q:\n%s", expected, src)
+		}
+	}
+
+	// The package's own format maps addresses to type tags
+	endpoints, err = parseNamespace([]byte(`{"/scene/recall": "s", "/scene/recall/": "i"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := generate("mixer", "Mixer", endpoints); err == nil {
+		t.Error("Expected an error for addresses with the same method name")
+	}
+}
+
+func TestGenerateFixedArguments(t *testing.T) {
+	endpoints, err := parseNamespace([]byte(`{"/cue/clear": "iNI"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := generate("cues", "Cues", endpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nil and infinitum arguments take no parameter, but are still sent
+	for _, expected := range []string{
+		"func (c *Cues) CueClear(arg0 int32) error {",
+		"m.AddArgument(arg0)\n\tm.AddArgument(nil)\n\tm.AddArgument(osc.Infinitum{})\n",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("Generated code does not contain %q:\n%s", expected, src)
+		}
+	}
+}
+
+func TestGenerateReservedNames(t *testing.T) {
+	for _, namespace := range []string{`{"/client": "i"}`, `{"/new/cues": "i"}`} {
+		endpoints, err := parseNamespace([]byte(namespace))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := generate("cues", "Cues", endpoints); err == nil {
+			t.Errorf("Expected an error for %s, whose method name clashes with the generated type", namespace)
+		}
+	}
+}
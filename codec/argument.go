@@ -0,0 +1,556 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Difference in seconds between the Unix epoch (1970) and OSC epoch (1900)
+	unixOSCEpochOffset = 2208988800
+	// Number of nanoseconds in 1 second
+	nanosPerSecond = 1e9
+	// Number of units of the fractional seconds field of a time tag in 1 second
+	fractionsPerSecond = 1 << 32
+	// The encoded value of an "immediate" time tag
+	timeTagImmediate = 0x01
+	// Encoded seconds values below this are taken to be in era 1 (after the 2036 rollover)
+	timeTagEraPivot = 1 << 31
+	// The number of seconds in an era of the 32-bit seconds field
+	timeTagEraSeconds = 1 << 32
+)
+
+var (
+	// MinTimeTagTime is the earliest time that can be represented by a TimeTag.
+	MinTimeTagTime = time.Unix(timeTagEraPivot-unixOSCEpochOffset, 0).In(time.UTC)
+	// MaxTimeTagTime is the latest time that can be represented by a TimeTag.
+	MaxTimeTagTime = time.Unix(timeTagEraSeconds+timeTagEraPivot-unixOSCEpochOffset, 0).Add(-1).In(time.UTC)
+)
+
+/*
+TimeTag represents an OSC time tag with an underlying Go time.Time, and an "immediate" flag.
+
+The 32-bit seconds field of an encoded time tag overflows on 7 February 2036. Following RFC 4330, encoded values with
+the most significant bit set are taken to be in era 0 (counting from 1900), and all others in era 1 (counting from the
+rollover in 2036). A TimeTag can therefore represent times between MinTimeTagTime (January 1968) and MaxTimeTagTime
+(February 2104). Encoding a TimeTag outside this range is an error.
+*/
+type TimeTag struct {
+	time      time.Time
+	Immediate bool
+}
+
+/*
+NewTimeTag returns a TimeTag with the specified Go Time.
+*/
+func NewTimeTag(t time.Time) TimeTag {
+	return TimeTag{time: t, Immediate: false}
+}
+
+/*
+NewImmediateTimeTag returns a TimeTag representing immediate execution.
+*/
+func NewImmediateTimeTag() TimeTag {
+	return TimeTag{Immediate: true}
+}
+
+/*
+InRange returns true if the TimeTag is immediate, or its time is between MinTimeTagTime and MaxTimeTagTime.
+*/
+func (tt TimeTag) InRange() bool {
+	return tt.Immediate || !(tt.time.Before(MinTimeTagTime) || tt.time.After(MaxTimeTagTime))
+}
+
+/*
+Time returns the Go time represented by the TimeTag. For an immediate TimeTag, the zero time is returned.
+*/
+func (tt TimeTag) Time() time.Time {
+	return tt.time
+}
+
+/*
+Raw returns the 64-bit OSC encoding of the TimeTag: seconds since 1900 in the upper 32 bits, and fractions of a second
+in the lower 32 bits. This is the value shown by other tools that display time tags in hex.
+*/
+func (tt TimeTag) Raw() uint64 {
+	if tt.Immediate {
+		// If the TimeTag has the "immediate" flag set, ignore the time value
+		return timeTagImmediate
+	}
+
+	// Encode the time with reference to the OSC epoch. Times after the era rollover overflow the 32-bit seconds
+	// field, and are decoded correctly so long as they are in range.
+	timeOSCSecs := uint64(tt.time.Unix() + unixOSCEpochOffset)
+	timeOSCFraction := uint64(tt.time.Nanosecond()) * fractionsPerSecond / nanosPerSecond
+
+	return timeOSCSecs<<32 | timeOSCFraction&0xFFFFFFFF
+}
+
+/*
+NewTimeTagFromRaw returns the TimeTag with the 64-bit OSC encoding raw (see Raw).
+*/
+func NewTimeTagFromRaw(raw uint64) TimeTag {
+	if raw == timeTagImmediate {
+		return NewImmediateTimeTag()
+	}
+
+	seconds := int64(raw >> 32)
+	if seconds < timeTagEraPivot {
+		seconds += timeTagEraSeconds
+	}
+	seconds -= unixOSCEpochOffset
+
+	// Convert the fraction of a second to nanoseconds, rounding to the nearest
+	nanoSeconds := int64(((raw&0xFFFFFFFF)*nanosPerSecond + fractionsPerSecond/2) / fractionsPerSecond)
+
+	return NewTimeTag(time.Unix(seconds, nanoSeconds).In(time.UTC))
+}
+
+/*
+String implements the fmt.Stringer interface. The raw 64-bit encoding is shown in hex after the time, e.g.
+"TimeTag: 2024-01-01 00:00:00 +0000 UTC (0xe93c9f0000000000)".
+*/
+func (tt TimeTag) String() string {
+	var str string
+
+	if tt.Immediate {
+		str = "TimeTag: (immediate)"
+	} else {
+		str = "TimeTag: " + tt.time.String()
+	}
+
+	return fmt.Sprintf("%s (0x%016x)", str, tt.Raw())
+}
+
+/*
+jsonTimeTag is the JSON representation of a TimeTag.
+*/
+type jsonTimeTag struct {
+	Time      *time.Time `json:"time,omitempty"`
+	Immediate bool       `json:"immediate"`
+	Raw       string     `json:"raw"`
+}
+
+/*
+MarshalJSON implements the json.Marshaler interface. The time tag is encoded as an object holding its time (omitted if
+immediate), its immediate flag, and its raw 64-bit encoding as a hex string, e.g.
+{"time":"2024-01-01T00:00:00Z","immediate":false,"raw":"0xe93c9f0000000000"}.
+*/
+func (tt TimeTag) MarshalJSON() ([]byte, error) {
+	encoded := jsonTimeTag{Immediate: tt.Immediate, Raw: fmt.Sprintf("0x%016x", tt.Raw())}
+	if !tt.Immediate {
+		encoded.Time = &tt.time
+	}
+
+	return json.Marshal(encoded)
+}
+
+/*
+UnmarshalJSON implements the json.Unmarshaler interface. The time tag is decoded from its raw encoding if present, and
+otherwise from its time and immediate flag.
+*/
+func (tt *TimeTag) UnmarshalJSON(data []byte) error {
+	var encoded jsonTimeTag
+
+	err := json.Unmarshal(data, &encoded)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case encoded.Raw != "":
+		raw, err := strconv.ParseUint(strings.TrimPrefix(encoded.Raw, "0x"), 16, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid raw time tag \"%s\"", encoded.Raw)
+		}
+		*tt = NewTimeTagFromRaw(raw)
+	case encoded.Immediate:
+		*tt = NewImmediateTimeTag()
+	case encoded.Time != nil:
+		*tt = NewTimeTag(*encoded.Time)
+	default:
+		return fmt.Errorf("Time tag has no time")
+	}
+
+	return nil
+}
+
+/*
+typeTag returns the appropriate OSC type tag for a value.
+*/
+func typeTag(argument interface{}) (string, error) {
+	typetag := ""
+	var err error
+
+	switch argType := argument.(type) {
+	case nil:
+		typetag = "N"
+	case int32:
+		typetag = "i"
+	case float32:
+		typetag = "f"
+	case string:
+		typetag = "s"
+	case []byte:
+		typetag = "b"
+	case bool:
+		val := argument.(bool)
+		if val {
+			typetag = "T"
+		} else {
+			typetag = "F"
+		}
+	case int64:
+		typetag = "h"
+	case float64:
+		typetag = "d"
+	case TimeTag:
+		typetag = "t"
+	case Char:
+		typetag = "c"
+	case RGBA:
+		typetag = "r"
+	case MIDIMessage:
+		typetag = "m"
+	case Infinitum:
+		typetag = "I"
+	case []interface{}:
+		// An array's type tags are enclosed in brackets
+		typetag = "["
+		for _, element := range argType {
+			var elementTag string
+			elementTag, err = typeTag(element)
+			if err != nil {
+				return "", err
+			}
+			typetag += elementTag
+		}
+		typetag += "]"
+	default:
+		typetag = ""
+		err = fmt.Errorf("Unsupported type: %T", argType)
+	}
+
+	return typetag, err
+}
+
+/*
+encodeArgument converts an argument to a byte slice.
+*/
+func encodeArgument(argument interface{}) ([]byte, error) {
+	return appendArgument(nil, argument)
+}
+
+/*
+appendArgument appends the encoding of an argument to dst, returning the extended slice.
+*/
+func appendArgument(dst []byte, argument interface{}) ([]byte, error) {
+	switch v := argument.(type) {
+	case nil:
+		// no bytes are allocated in the argument data
+	case int32:
+		dst = appendUint32(dst, uint32(v))
+	case float32:
+		dst = appendUint32(dst, math.Float32bits(v))
+	case string:
+		// sequence of non-null ASCII characters followed by a null, followed by 0-3 additional null characters to make
+		// the total number of bits a multiple of 32
+		dst = appendString(dst, v)
+	case []byte:
+		// int32 size count, followed by that many 8-bit bytes of arbitrary binary data, followed by 0-3 additional
+		// zero bytes to make the total number of bits a multiple of 32
+		dst = appendByteSlice(dst, v)
+	case bool:
+		// no bytes are allocated in the argument data
+	case int64:
+		dst = appendUint64(dst, uint64(v))
+	case float64:
+		dst = appendUint64(dst, math.Float64bits(v))
+	case TimeTag:
+		if !v.InRange() {
+			return nil, fmt.Errorf("Time tag %v out of range", argument)
+		}
+		dst = appendUint64(dst, v.Raw())
+	case Char:
+		dst = appendUint32(dst, uint32(v))
+	case RGBA:
+		dst = append(dst, v.R, v.G, v.B, v.A)
+	case MIDIMessage:
+		dst = append(dst, v.Port, v.Status, v.Data1, v.Data2)
+	case Infinitum:
+		// no bytes are allocated in the argument data
+	case []interface{}:
+		// The elements of an array are encoded in sequence, with no additional bytes for the array itself
+		for _, element := range v {
+			var err error
+			dst, err = appendArgument(dst, element)
+			if err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported argument type \"%T\"", argument)
+	}
+
+	return dst, nil
+}
+
+/*
+appendTypeTags appends the type tags of arguments to dst, as they appear in a type tag string (without its leading
+comma), returning the extended slice.
+*/
+func appendTypeTags(dst []byte, args []interface{}) ([]byte, error) {
+	for _, arg := range args {
+		var tag byte
+
+		switch v := arg.(type) {
+		case nil:
+			tag = 'N'
+		case int32:
+			tag = 'i'
+		case float32:
+			tag = 'f'
+		case string:
+			tag = 's'
+		case []byte:
+			tag = 'b'
+		case bool:
+			tag = 'F'
+			if v {
+				tag = 'T'
+			}
+		case int64:
+			tag = 'h'
+		case float64:
+			tag = 'd'
+		case TimeTag:
+			tag = 't'
+		case Char:
+			tag = 'c'
+		case RGBA:
+			tag = 'r'
+		case MIDIMessage:
+			tag = 'm'
+		case Infinitum:
+			tag = 'I'
+		case []interface{}:
+			// An array's type tags are enclosed in brackets
+			var err error
+			dst, err = appendTypeTags(append(dst, '['), v)
+			if err != nil {
+				return nil, err
+			}
+			dst = append(dst, ']')
+			continue
+		default:
+			return nil, fmt.Errorf("Unsupported type: %T", arg)
+		}
+
+		dst = append(dst, tag)
+	}
+
+	return dst, nil
+}
+
+/*
+readArguments reads a slice of OSC arguments (specific by the typeTagString) from a buffer. If the arguments do not
+match the typeTagString, an error is returned.
+*/
+func readArguments(typeTagString string, buf *bytes.Buffer) ([]interface{}, error) {
+	return appendArguments(nil, typeTagString, buf)
+}
+
+/*
+appendArguments is like readArguments, but appends the arguments to args.
+*/
+func appendArguments(args []interface{}, typeTagString string, buf *bytes.Buffer) ([]interface{}, error) {
+	// The enclosing argument lists of any arrays currently being read
+	var arrayStack [][]interface{}
+
+	// Ensure the type tag string starts with a comma
+	if len(typeTagString) == 0 || typeTagString[0] != ',' {
+		return nil, fmt.Errorf("Malformed type tag string")
+	}
+
+	// Iterate over the remaining type tags
+	for _, typeTag := range typeTagString[1:] {
+		var err error
+
+		switch typeTag {
+		case '[':
+			arrayStack = append(arrayStack, args)
+			args = []interface{}{}
+		case ']':
+			if len(arrayStack) == 0 {
+				return nil, fmt.Errorf("Malformed type tag string")
+			}
+			array := args
+			args = arrayStack[len(arrayStack)-1]
+			arrayStack = arrayStack[:len(arrayStack)-1]
+			args = append(args, array)
+		default:
+			var val interface{}
+			val, err = decodeArgument(typeTag, buf)
+			args = append(args, val)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Found malformed argument")
+		}
+	}
+
+	if len(arrayStack) != 0 {
+		return nil, fmt.Errorf("Malformed type tag string")
+	}
+
+	return args, nil
+}
+
+/*
+decodeArgument reads a single OSC argument of the type given by typeTag from a buffer.
+*/
+func decodeArgument(typeTag rune, buf *bytes.Buffer) (interface{}, error) {
+	switch typeTag {
+	case 'T':
+		return true, nil
+	case 'F':
+		return false, nil
+	case 'N':
+		return nil, nil
+	case 'i':
+		val, err := readUint32(buf)
+		return int32(val), err
+	case 'f':
+		val, err := readUint32(buf)
+		return math.Float32frombits(val), err
+	case 's':
+		return decodeString(buf)
+	case 'b':
+		return decodeByteSlice(buf)
+	case 'h':
+		val, err := readUint64(buf)
+		return int64(val), err
+	case 'd':
+		val, err := readUint64(buf)
+		return math.Float64frombits(val), err
+	case 't':
+		return decodeTimeTag(buf)
+	case 'c':
+		val, err := readUint32(buf)
+		return Char(val), err
+	case 'r':
+		return decodeRGBA(buf)
+	case 'm':
+		return decodeMIDIMessage(buf)
+	case 'I':
+		return Infinitum{}, nil
+	default:
+		return nil, fmt.Errorf("Found unsupported argument type")
+	}
+}
+
+/*
+encodeString converts a Go string to a 32-bit padded OSC String.
+*/
+func encodeString(s string) []byte {
+	return appendString(nil, s)
+}
+
+/*
+appendString appends a Go string to dst as a 32-bit padded OSC String, returning the extended slice.
+*/
+func appendString(dst []byte, s string) []byte {
+	dst = append(dst, s...)
+	dst = append(dst, '\x00')
+
+	return appendPadding(dst, len(s)+1)
+}
+
+/*
+encodeByteSlice converts a Go byte slice to an OSC byte array.
+*/
+func encodeByteSlice(data []byte) []byte {
+	return appendByteSlice(nil, data)
+}
+
+/*
+appendByteSlice appends a Go byte slice to dst as an OSC byte array, returning the extended slice.
+*/
+func appendByteSlice(dst []byte, data []byte) []byte {
+	dst = appendUint32(dst, uint32(len(data)))
+	dst = append(dst, data...)
+
+	return appendPadding(dst, len(data))
+}
+
+/*
+encodeTimeTag converts a TimeTag to a 64-bit OSC timetag.
+*/
+func encodeTimeTag(tt TimeTag) []byte {
+	return appendUint64(make([]byte, 0, 8), tt.Raw())
+}
+
+func decodeTimeTag(buf *bytes.Buffer) (TimeTag, error) {
+	timeTag64, err := readUint64(buf)
+	if err != nil {
+		return TimeTag{}, err
+	}
+
+	return NewTimeTagFromRaw(timeTag64), nil
+}
+
+/*
+appendUint32 appends a 32-bit big-endian integer to dst, returning the extended slice.
+*/
+func appendUint32(dst []byte, v uint32) []byte {
+	dst = append(dst, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(dst[len(dst)-4:], v)
+
+	return dst
+}
+
+/*
+appendUint64 appends a 64-bit big-endian integer to dst, returning the extended slice.
+*/
+func appendUint64(dst []byte, v uint64) []byte {
+	dst = append(dst, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint64(dst[len(dst)-8:], v)
+
+	return dst
+}
+
+/*
+appendPadding appends the null bytes needed to pad n bytes of data at the end of dst to a multiple of 32 bits,
+returning the extended slice.
+*/
+func appendPadding(dst []byte, n int) []byte {
+	for ; n%4 != 0; n++ {
+		dst = append(dst, '\x00')
+	}
+
+	return dst
+}
+
+/*
+padTo32Bits pads a byte slice to 32 bits by appending nil values.
+*/
+func padTo32Bits(data []byte) []byte {
+	origLength := len(data)
+
+	// Bit-twiddle to find the next multiple of 4 (4 bytes = 32 bits)
+	padLength := (origLength + 3) &^ 0x03
+
+	i := padLength - origLength - 1
+	for i >= 0 {
+		data = append(data, byte(0))
+		i--
+	}
+
+	return data
+}
@@ -1,7 +1,8 @@
-package osc
+package codec
 
 import (
 	"bytes"
+	"encoding/json"
 	"testing"
 	"time"
 )
@@ -28,7 +29,7 @@ func TestEncodeTimeTag(t *testing.T) {
 	// Add 0.5s to previous test to test encoding of fractional time
 	test3 := NewTimeTag(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
 	test3.time = test3.time.Add(500 * time.Millisecond)
-	expected3 := []byte{'\xDD', '\xF3', '\xF8', '\x80', '\x1D', '\xCD', '\x65', '\x00'}
+	expected3 := []byte{'\xDD', '\xF3', '\xF8', '\x80', '\x80', '\x00', '\x00', '\x00'}
 	result3 := encodeTimeTag(test3)
 
 	if !bytes.Equal(result3, expected3) {
@@ -60,7 +61,7 @@ func TestDecodeTimeTag(t *testing.T) {
 	}
 
 	// Same as previous test but with 0.5s added
-	test3 := []byte{'\xDD', '\xF3', '\xF8', '\x80', '\x1D', '\xCD', '\x65', '\x00'}
+	test3 := []byte{'\xDD', '\xF3', '\xF8', '\x80', '\x80', '\x00', '\x00', '\x00'}
 	expected3 := NewTimeTag(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
 	expected3.time = expected3.time.Add(500 * time.Millisecond)
 	result3, err3 := decodeTimeTag(bytes.NewBuffer(test3))
@@ -100,3 +101,80 @@ func TestPadTo32Bits(t *testing.T) {
 		t.Errorf("New value if %v, expected %v", result3, expected3)
 	}
 }
+
+func TestTimeTagEras(t *testing.T) {
+	tests := []time.Time{
+		MinTimeTagTime,
+		time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2036, 2, 7, 6, 28, 15, 0, time.UTC),
+		time.Date(2036, 2, 7, 6, 28, 16, 0, time.UTC),
+		time.Date(2050, 6, 1, 12, 0, 0, 0, time.UTC),
+		MaxTimeTagTime.Truncate(time.Second),
+	}
+
+	for _, test := range tests {
+		expected := NewTimeTag(test)
+		result, err := decodeTimeTag(bytes.NewBuffer(encodeTimeTag(expected)))
+
+		if err != nil {
+			t.Error(err)
+		} else if !result.Time().Equal(test) {
+			t.Errorf("Got %v, expected %v", result, expected)
+		}
+	}
+
+	// The 2036 rollover is encoded as 0 seconds in era 1
+	expected1 := []byte{'\x00', '\x00', '\x00', '\x00', '\x00', '\x00', '\x00', '\x00'}
+	result1 := encodeTimeTag(NewTimeTag(time.Date(2036, 2, 7, 6, 28, 16, 0, time.UTC)))
+
+	if !bytes.Equal(result1, expected1) {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	// Times outside of the representable range cannot be encoded
+	outOfRange := []time.Time{
+		MinTimeTagTime.Add(-time.Second),
+		MaxTimeTagTime.Add(time.Second),
+		time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	for _, test := range outOfRange {
+		_, err := encodeArgument(NewTimeTag(test))
+
+		if err == nil {
+			t.Errorf("Expected an error encoding %v", test)
+		}
+	}
+}
+
+func TestTimeTagRaw(t *testing.T) {
+	test1 := NewTimeTag(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC))
+	expected1 := uint64(0x83aa7e8000000000)
+	result1 := test1.Raw()
+
+	if result1 != expected1 {
+		t.Errorf("Got %x, expected %x", result1, expected1)
+	}
+
+	expected2 := "TimeTag: (immediate) (0x0000000000000001)"
+	result2 := NewImmediateTimeTag().String()
+
+	if result2 != expected2 {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+
+	expected3 := `{"time":"1970-01-01T00:00:00Z","immediate":false,"raw":"0x83aa7e8000000000"}`
+	data, err := json.Marshal(test1)
+	result3 := string(data)
+
+	if err != nil || result3 != expected3 {
+		t.Errorf("Got %v (%v), expected %v", result3, err, expected3)
+	}
+
+	var result4 TimeTag
+	err = json.Unmarshal(data, &result4)
+
+	if err != nil || result4.Raw() != expected1 {
+		t.Errorf("Got %v (%v), expected %v", result4, err, test1)
+	}
+}
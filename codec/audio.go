@@ -0,0 +1,62 @@
+package codec
+
+import (
+	"math"
+	"time"
+)
+
+/*
+Seconds returns the time of the TimeTag as a number of seconds since the OSC epoch (1 January 1900), as used by audio
+software such as SuperCollider. A float64 holds this with a resolution better than a microsecond, finer than one sample
+period at common sample rates. An immediate TimeTag returns 0.
+*/
+func (tt TimeTag) Seconds() float64 {
+	if tt.Immediate {
+		return 0
+	}
+
+	return float64(tt.time.Unix()+unixOSCEpochOffset) + float64(tt.time.Nanosecond())/nanosPerSecond
+}
+
+/*
+NewTimeTagFromSeconds returns a TimeTag for a number of seconds since the OSC epoch, as returned by Seconds.
+*/
+func NewTimeTagFromSeconds(seconds float64) TimeTag {
+	whole, fraction := math.Modf(seconds)
+	nanos := int64(math.Round(fraction * nanosPerSecond))
+
+	return NewTimeTag(time.Unix(int64(whole)-unixOSCEpochOffset, nanos).In(time.UTC))
+}
+
+/*
+FramesBetween returns the number of sample frames at sampleRate from one TimeTag to another, which is negative if to is
+earlier than from. The result is fractional, so that callers can choose how to round it (or interpolate).
+*/
+func FramesBetween(from, to TimeTag, sampleRate float64) float64 {
+	if from.Immediate || to.Immediate {
+		return 0
+	}
+
+	return to.time.Sub(from.time).Seconds() * sampleRate
+}
+
+/*
+FramesUntil returns the number of sample frames at sampleRate from now until the time of tt, which is negative if tt
+has passed. An immediate TimeTag returns 0.
+*/
+func FramesUntil(tt TimeTag, sampleRate float64) float64 {
+	if tt.Immediate {
+		return 0
+	}
+
+	return time.Until(tt.time).Seconds() * sampleRate
+}
+
+/*
+TimeTagForFrame returns the TimeTag of a sample frame, counting from frame 0 at origin, at sampleRate.
+*/
+func TimeTagForFrame(origin TimeTag, frame float64, sampleRate float64) TimeTag {
+	offset := time.Duration(math.Round(frame / sampleRate * nanosPerSecond))
+
+	return NewTimeTag(origin.time.Add(offset))
+}
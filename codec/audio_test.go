@@ -0,0 +1,40 @@
+package codec
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTimeTagSeconds(t *testing.T) {
+	test1 := NewTimeTag(time.Date(2018, 1, 1, 0, 0, 0, 500000000, time.UTC))
+	expected1 := 3723753600.5
+	if result1 := test1.Seconds(); result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	if result2 := NewTimeTagFromSeconds(expected1); result2 != test1 {
+		t.Errorf("Got %v, expected %v", result2, test1)
+	}
+}
+
+func TestFrames(t *testing.T) {
+	origin := NewTimeTag(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	test1 := TimeTagForFrame(origin, 48000*1.5, 48000)
+	expected1 := NewTimeTag(origin.Time().Add(1500 * time.Millisecond))
+	if test1 != expected1 {
+		t.Errorf("Got %v, expected %v", test1, expected1)
+	}
+
+	expected2 := 72000.0
+	if result2 := FramesBetween(origin, test1, 48000); result2 != expected2 {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+
+	// A time tag one second ahead is about 44100 frames away
+	result3 := FramesUntil(NewTimeTag(time.Now().Add(time.Second)), 44100)
+	if math.Abs(result3-44100) > 441 {
+		t.Errorf("Got %v, expected about 44100", result3)
+	}
+}
@@ -0,0 +1,60 @@
+package codec
+
+import (
+	"testing"
+	"time"
+)
+
+/*
+benchmarkMessage returns a message with one argument of each common type.
+*/
+func benchmarkMessage() *Message {
+	msg := NewMessage("/mixer/channel/1/fader")
+	msg.AddArgument(int32(1))
+	msg.AddArgument(float32(0.5))
+	msg.AddArgument("label")
+	msg.AddArgument(int64(1) << 40)
+	msg.AddArgument(0.25)
+	msg.AddArgument([]byte{1, 2, 3, 4, 5})
+	msg.AddArgument(NewTimeTag(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	return msg
+}
+
+func BenchmarkEncodeMessage(b *testing.B) {
+	msg := benchmarkMessage()
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		msg.MarshalBinary()
+	}
+}
+
+func BenchmarkEncodeBundle(b *testing.B) {
+	bundle := NewBundle()
+	for i := 0; i < 16; i++ {
+		bundle.AddPacket(benchmarkMessage())
+	}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		bundle.MarshalBinary()
+	}
+}
+
+func BenchmarkDecodeMessage(b *testing.B) {
+	data, err := benchmarkMessage().MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var decoded Message
+		decoded.UnmarshalBinary(data)
+	}
+}
@@ -0,0 +1,292 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+var (
+	// Encoded bundle identifier string
+	bundleString = []byte{'#', 'b', 'u', 'n', 'd', 'l', 'e', '\x00'}
+)
+
+/*
+Bundle represents an OSC bundle, which contains a time tag and multiple child elements. Bundles implement Packet by
+pointer, and should always be passed around as *Bundle.
+*/
+type Bundle struct {
+	TimeTag  TimeTag
+	Elements []Packet
+}
+
+// Compile-time check to ensure Bundle implements the Packet interface.
+var _ Packet = &Bundle{}
+
+/*
+NewBundle returns a bundle with immediate time tag.
+*/
+func NewBundle() *Bundle {
+	return &Bundle{TimeTag: TimeTag{Immediate: true}}
+}
+
+/*
+NewBundleFromData is a convenience factory to decode a bundle from a byte slice.
+*/
+func NewBundleFromData(data []byte) (*Bundle, error) {
+	bundle := NewBundle()
+	err := bundle.UnmarshalBinary(data)
+
+	return bundle, err
+}
+
+/*
+AddPacket adds a packet to the bundle's child elements.
+*/
+func (bun *Bundle) AddPacket(p Packet) {
+	if bun == nil {
+		return
+	}
+
+	bun.Elements = append(bun.Elements, p)
+}
+
+/*
+MarshalBinary encodes the Bundle as per the OSC standard.
+*/
+func (bun *Bundle) MarshalBinary() (data []byte, err error) {
+	if bun == nil {
+		return nil, errors.New("Cannot encode a nil bundle")
+	}
+
+	return bun.appendBinary(make([]byte, 0, 256))
+}
+
+/*
+appendBinary appends the encoding of the Bundle to dst, returning the extended slice. Its elements are encoded in
+place, with their sizes filled in afterwards.
+*/
+func (bun *Bundle) appendBinary(dst []byte) ([]byte, error) {
+	if !bun.TimeTag.InRange() {
+		return nil, fmt.Errorf("Bundle time tag %v out of range", bun.TimeTag)
+	}
+
+	dst = append(dst, bundleString...)
+	dst = appendUint64(dst, bun.TimeTag.Raw())
+
+	// Encode each child element
+	for _, e := range bun.Elements {
+		start := len(dst)
+		dst = append(dst, 0, 0, 0, 0)
+
+		var err error
+		switch v := e.(type) {
+		case *Message:
+			if v == nil {
+				return nil, errors.New("Cannot encode a nil message")
+			}
+			dst, err = v.appendBinary(dst)
+		case *Bundle:
+			if v == nil {
+				return nil, errors.New("Cannot encode a nil bundle")
+			}
+			dst, err = v.appendBinary(dst)
+		default:
+			var encoded []byte
+			encoded, err = e.MarshalBinary()
+			dst = append(dst, encoded...)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		binary.BigEndian.PutUint32(dst[start:], uint32(len(dst)-start-4))
+	}
+
+	return dst, nil
+}
+
+/*
+UnmarshalBinary attempts to create a new Bundle from an encoded byte slice.
+*/
+func (bun *Bundle) UnmarshalBinary(data []byte) error {
+	return bun.unmarshal(data, false)
+}
+
+/*
+UnmarshalStrict is like UnmarshalBinary, but decodes the messages in the bundle with Message.UnmarshalStrict. If any
+message has trailing bytes, the bundle is still decoded, and the first *TrailingDataError is returned.
+*/
+func (bun *Bundle) UnmarshalStrict(data []byte) error {
+	return bun.unmarshal(data, true)
+}
+
+/*
+UnmarshalBinaryN is like UnmarshalBinary, but also returns the number of bytes consumed. An OSC bundle does not encode
+its own length, so it always extends to the end of data; concatenated bundles must be framed (e.g. by length prefixes)
+to be told apart.
+*/
+func (bun *Bundle) UnmarshalBinaryN(data []byte) (n int, err error) {
+	err = bun.unmarshal(data, false)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+func (bun *Bundle) unmarshal(data []byte, strict bool) error {
+	buf := bytes.NewBuffer(data)
+
+	// Check the bundle identifier
+	identifier, err := decodeString(buf)
+	if err != nil {
+		return err
+	} else if identifier != "#bundle" {
+		return errors.New("Malformed bundle")
+	}
+
+	// Read the time tag
+	timeTag, err := decodeTimeTag(buf)
+	if err != nil {
+		return err
+	}
+
+	var elements []Packet
+	var trailingErr error
+
+	// Read the bundle's contents
+	for {
+		// Look for a size count
+		count, err := readUint32(buf)
+		if err == io.EOF {
+			// No more bundle data to read, terminate the loop
+			break
+		} else if err != nil {
+			return err
+		}
+
+		// Ensure the element fits in the remaining data before allocating for it
+		if uint64(count) > uint64(buf.Len()) {
+			return errors.New("Malformed bundle")
+		}
+
+		// Assign a byte array the exact size
+		packetData := make([]byte, count)
+		n, err := buf.Read(packetData)
+		if err != nil {
+			return errors.New("Malformed bundle")
+		}
+
+		// Ensure that the number of bytes read equals the expected count
+		if uint32(n) != count {
+			return errors.New("Malformed bundle")
+		}
+
+		p, err := decodePacket(packetData, strict)
+		if _, ok := err.(*TrailingDataError); ok {
+			if trailingErr == nil {
+				trailingErr = err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		elements = append(elements, p)
+	}
+
+	bun.TimeTag = timeTag
+	bun.Elements = elements
+
+	return trailingErr
+}
+
+/*
+DecodePacket attempts to decode a packet into a Message or a Bundle.
+*/
+func DecodePacket(data []byte) (Packet, error) {
+	return decodePacket(data, false)
+}
+
+/*
+DecodePacketStrict is like DecodePacket, but decodes messages with Message.UnmarshalStrict. If any message has trailing
+bytes, the packet is still returned along with the first *TrailingDataError.
+*/
+func DecodePacketStrict(data []byte) (Packet, error) {
+	return decodePacket(data, true)
+}
+
+func decodePacket(data []byte, strict bool) (Packet, error) {
+	// Ensure there is data to read, and ensure it is a multiple of 32 bits
+	lenData := len(data)
+	if lenData <= 0 || lenData%4 != 0 {
+		return nil, errors.New("Packet data is not a multiple of 4 bytes")
+	}
+
+	var p interface {
+		Packet
+		UnmarshalStrict([]byte) error
+	}
+
+	firstChar := data[0]
+	if firstChar == '/' {
+		// The packet is an OSC message
+		p = &Message{}
+	} else if firstChar == '#' {
+		// The packet is another bundle
+		p = NewBundle()
+	} else {
+		return nil, errors.New("Malformed packet")
+	}
+
+	var err error
+	if strict {
+		err = p.UnmarshalStrict(data)
+	} else {
+		err = p.UnmarshalBinary(data)
+	}
+
+	if _, ok := err.(*TrailingDataError); !ok && err != nil {
+		return nil, err
+	}
+
+	return p, err
+}
+
+func (bun *Bundle) String() string {
+	if bun == nil {
+		return "Bundle: <nil>"
+	}
+
+	buf := new(bytes.Buffer)
+
+	buf.WriteString("Bundle: {")
+	buf.WriteString(bun.TimeTag.String())
+	for _, e := range bun.Elements {
+		buf.WriteString(" ")
+		buf.WriteString(e.String())
+	}
+	buf.WriteString("}")
+
+	return buf.String()
+}
+
+/*
+Equals returns true if bun is equal to other, otherwise false.
+*/
+func (bun *Bundle) Equals(other *Bundle) bool {
+	if bun == other {
+		return true
+	} else if bun == nil || other == nil {
+		return false
+	}
+
+	timeTagEq := bun.TimeTag == other.TimeTag
+
+	elementsEq := reflect.DeepEqual(bun.Elements, other.Elements)
+
+	return timeTagEq && elementsEq
+}
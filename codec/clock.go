@@ -0,0 +1,91 @@
+package codec
+
+import (
+	"time"
+)
+
+/*
+MonotonicClock generates TimeTags from the local monotonic clock, mapped to wall clock time at a fixed epoch (the
+moment the clock was created). Scheduling with these TimeTags is immune to steps in the wall clock (e.g. from NTP)
+that happen after the epoch, at the cost of slowly diverging from wall clock time if the system clock is corrected.
+*/
+type MonotonicClock struct {
+	epoch time.Time
+}
+
+/*
+NewMonotonicClock returns a MonotonicClock with its epoch set to the current time.
+*/
+func NewMonotonicClock() *MonotonicClock {
+	return &MonotonicClock{epoch: time.Now()}
+}
+
+/*
+Now returns a TimeTag for the current instant.
+*/
+func (c *MonotonicClock) Now() TimeTag {
+	return c.TimeTag(time.Now())
+}
+
+/*
+After returns a TimeTag for the instant d after the current instant.
+*/
+func (c *MonotonicClock) After(d time.Duration) TimeTag {
+	return c.TimeTag(time.Now().Add(d))
+}
+
+/*
+TimeTag returns a TimeTag for the local instant t. If t carries a monotonic clock reading (as values from time.Now()
+do), only that reading is used.
+*/
+func (c *MonotonicClock) TimeTag(t time.Time) TimeTag {
+	elapsed := t.Sub(c.epoch)
+	return NewTimeTag(c.epoch.Round(0).Add(elapsed))
+}
+
+/*
+Time converts a TimeTag generated by the clock back to a local instant, carrying a monotonic clock reading so that it
+can be compared with time.Now() or used to set timers. An immediate TimeTag returns the current time.
+*/
+func (c *MonotonicClock) Time(tt TimeTag) time.Time {
+	if tt.Immediate {
+		return time.Now()
+	}
+
+	return c.epoch.Add(tt.time.Sub(c.epoch.Round(0)))
+}
+
+/*
+ToWall converts a TimeTag generated by the clock to the equivalent TimeTag in the current wall clock time, taking into
+account any steps to the wall clock since the epoch. Immediate TimeTags are returned unchanged.
+*/
+func (c *MonotonicClock) ToWall(tt TimeTag) TimeTag {
+	if tt.Immediate {
+		return tt
+	}
+
+	return NewTimeTag(tt.time.Add(c.drift()))
+}
+
+/*
+FromWall converts a wall clock TimeTag (e.g. one received from another host) to the equivalent TimeTag of the clock.
+Immediate TimeTags are returned unchanged.
+*/
+func (c *MonotonicClock) FromWall(tt TimeTag) TimeTag {
+	if tt.Immediate {
+		return tt
+	}
+
+	return NewTimeTag(tt.time.Add(-c.drift()))
+}
+
+/*
+drift returns how far the wall clock has moved relative to the monotonic clock since the epoch.
+*/
+func (c *MonotonicClock) drift() time.Duration {
+	now := time.Now()
+	wallElapsed := now.Round(0).Sub(c.epoch.Round(0))
+	monotonicElapsed := now.Sub(c.epoch)
+
+	return wallElapsed - monotonicElapsed
+}
@@ -0,0 +1,34 @@
+package codec
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMonotonicClock(t *testing.T) {
+	clock := NewMonotonicClock()
+
+	// A tag converted back to local time should be the same instant
+	tt := clock.After(time.Second)
+	result1 := clock.Time(tt)
+	expected1 := time.Now().Add(time.Second)
+
+	if diff := result1.Sub(expected1); diff < -100*time.Millisecond || diff > 100*time.Millisecond {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	// Converting to and from wall clock time should return the same instant
+	wall := NewTimeTag(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	result2 := clock.ToWall(clock.FromWall(wall))
+
+	if diff := result2.Time().Sub(wall.Time()); diff < -time.Millisecond || diff > time.Millisecond {
+		t.Errorf("Got %v, expected %v", result2, wall)
+	}
+
+	// Immediate tags are not converted
+	result3 := clock.ToWall(NewImmediateTimeTag())
+
+	if !result3.Immediate {
+		t.Errorf("Got %v, expected an immediate time tag", result3)
+	}
+}
@@ -0,0 +1,128 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// conformanceVector is an encoded packet as sent by another OSC implementation, with the packet it represents.
+type conformanceVector struct {
+	name   string
+	data   string
+	packet Packet
+}
+
+func vectorMessage(address string, args ...interface{}) *Message {
+	msg := NewMessage(address)
+	for _, arg := range args {
+		msg.AddArgument(arg)
+	}
+
+	return msg
+}
+
+func vectorBundle(tt TimeTag, elements ...Packet) *Bundle {
+	return &Bundle{TimeTag: tt, Elements: elements}
+}
+
+var conformanceVectors = []conformanceVector{
+	{
+		// python-osc: a single float argument
+		"python-osc float",
+		"/SYNC\x00\x00\x00,f\x00\x00\x3f\x00\x00\x00",
+		vectorMessage("/SYNC", float32(0.5)),
+	},
+	{
+		// liblo: lo_send(addr, "/foo", "ifsb", 1000, 1.0, "bar", blob), with a blob needing 3 bytes of padding
+		"liblo standard types",
+		"/foo\x00\x00\x00\x00,ifsb\x00\x00\x00\x00\x00\x03\xe8\x3f\x80\x00\x00bar\x00\x00\x00\x00\x05\x01\x02\x03\x04\x05\x00\x00\x00",
+		vectorMessage("/foo", int32(1000), float32(1), "bar", []byte{1, 2, 3, 4, 5}),
+	},
+	{
+		// Max: a bang is sent as a message with an empty type tag string
+		"Max bang",
+		"/bang\x00\x00\x00,\x00\x00\x00",
+		vectorMessage("/bang"),
+	},
+	{
+		// liblo: the argument-less types carry no data
+		"liblo TFN",
+		"/tfn\x00\x00\x00\x00,TFN\x00\x00\x00\x00",
+		vectorMessage("/tfn", true, false, nil),
+	},
+	{
+		// liblo: 64-bit types, with a time tag half a second after midnight on 1 January 2018
+		"liblo 64-bit types",
+		"/hdt\x00\x00\x00\x00,hdt\x00\x00\x00\x00" +
+			"\xff\xff\xff\xff\xff\xff\xff\xfe" +
+			"\x3f\xd0\x00\x00\x00\x00\x00\x00" +
+			"\xdd\xf3\xf8\x80\x80\x00\x00\x00",
+		vectorMessage("/hdt", int64(-2), float64(0.25),
+			NewTimeTag(time.Date(2018, 1, 1, 0, 0, 0, 500000000, time.UTC))),
+	},
+	{
+		// python-osc: an empty string takes 4 bytes, and a 4-character string is followed by 4 bytes of padding
+		"python-osc string padding",
+		"/s\x00\x00,ss\x00\x00\x00\x00\x00abcd\x00\x00\x00\x00",
+		vectorMessage("/s", "", "abcd"),
+	},
+	{
+		// python-osc: a blob whose length is a multiple of 4 has no padding
+		"python-osc aligned blob",
+		"/b\x00\x00,b\x00\x00\x00\x00\x00\x04\xde\xad\xbe\xef",
+		vectorMessage("/b", []byte{0xde, 0xad, 0xbe, 0xef}),
+	},
+	{
+		// liblo: an array of integers
+		"liblo array",
+		"/arr\x00\x00\x00\x00,[ii]\x00\x00\x00\x00\x00\x00\x01\x00\x00\x00\x02",
+		vectorMessage("/arr", []interface{}{int32(1), int32(2)}),
+	},
+	{
+		// python-osc: an immediate bundle containing one message
+		"python-osc bundle",
+		"#bundle\x00\x00\x00\x00\x00\x00\x00\x00\x01" +
+			"\x00\x00\x00\x10/SYNC\x00\x00\x00,f\x00\x00\x3f\x00\x00\x00",
+		vectorBundle(NewImmediateTimeTag(), vectorMessage("/SYNC", float32(0.5))),
+	},
+	{
+		// liblo: a timed bundle containing an immediate bundle and a message
+		"liblo nested bundle",
+		"#bundle\x00\xdd\xf3\xf8\x80\x80\x00\x00\x00" +
+			"\x00\x00\x00\x20#bundle\x00\x00\x00\x00\x00\x00\x00\x00\x01" +
+			"\x00\x00\x00\x0c/bang\x00\x00\x00,\x00\x00\x00" +
+			"\x00\x00\x00\x0c/bang\x00\x00\x00,\x00\x00\x00",
+		vectorBundle(NewTimeTag(time.Date(2018, 1, 1, 0, 0, 0, 500000000, time.UTC)),
+			vectorBundle(NewImmediateTimeTag(), vectorMessage("/bang")),
+			vectorMessage("/bang")),
+	},
+}
+
+func TestConformanceDecode(t *testing.T) {
+	for _, vector := range conformanceVectors {
+		result, err := DecodePacket([]byte(vector.data))
+		if err != nil {
+			t.Errorf("%s: %v", vector.name, err)
+			continue
+		}
+
+		if result.String() != vector.packet.String() {
+			t.Errorf("%s: Got %v, expected %v", vector.name, result, vector.packet)
+		}
+	}
+}
+
+func TestConformanceEncode(t *testing.T) {
+	for _, vector := range conformanceVectors {
+		result, err := vector.packet.MarshalBinary()
+		if err != nil {
+			t.Errorf("%s: %v", vector.name, err)
+			continue
+		}
+
+		if !bytes.Equal(result, []byte(vector.data)) {
+			t.Errorf("%s: Got %q, expected %q", vector.name, result, vector.data)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package codec
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+/*
+ArgumentConverter converts a Go value into a value of a supported OSC argument type.
+*/
+type ArgumentConverter func(value interface{}) (interface{}, error)
+
+var (
+	convertersMu sync.RWMutex
+	converters   = make(map[reflect.Type]ArgumentConverter)
+)
+
+/*
+RegisterConverter registers a function to convert values of the same type as sample when they are passed to
+AddArgument, allowing application types to be sent without manual conversion. A registered converter takes precedence
+over the built-in slice conversion, but not over natively supported argument types.
+*/
+func RegisterConverter(sample interface{}, fn ArgumentConverter) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+
+	converters[reflect.TypeOf(sample)] = fn
+}
+
+/*
+convertArgument returns arg as a supported OSC argument, converting it if necessary.
+*/
+func convertArgument(arg interface{}) (interface{}, error) {
+	// If we can get a type tag for the argument, then it is a supported type
+	if _, err := typeTag(arg); err == nil {
+		return arg, nil
+	}
+
+	t := reflect.TypeOf(arg)
+
+	convertersMu.RLock()
+	fn, ok := converters[t]
+	convertersMu.RUnlock()
+
+	if ok {
+		converted, err := fn(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := typeTag(converted); err != nil {
+			return nil, fmt.Errorf("Converter for type \"%T\" returned unsupported type \"%T\"", arg, converted)
+		}
+
+		return converted, nil
+	}
+
+	v := reflect.ValueOf(arg)
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		// Convert each element, and send the whole as an OSC array
+		array := make([]interface{}, v.Len())
+		for i := range array {
+			element, err := convertArgument(v.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			array[i] = element
+		}
+
+		return array, nil
+	case reflect.Map:
+		return nil, fmt.Errorf("Argument type \"%T\" not supported: maps have no OSC equivalent", arg)
+	}
+
+	return nil, fmt.Errorf("Argument type \"%T\" not supported", arg)
+}
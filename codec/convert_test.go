@@ -0,0 +1,73 @@
+package codec
+
+import (
+	"fmt"
+	"testing"
+)
+
+type testColour struct {
+	r, g, b uint8
+}
+
+func TestAddArgumentSlice(t *testing.T) {
+	msg := NewMessage("/levels")
+	err := msg.AddArgument([]float32{0.5, 1})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected1 := ",[ff]"
+	result1, err1 := msg.TypeTagString()
+
+	if err1 != nil {
+		t.Error(err1)
+	} else if result1 != expected1 {
+		t.Errorf("Type tag string is \"%v\", expected \"%v\"", result1, expected1)
+	}
+
+	// The array should survive encoding and decoding
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected2 := NewMessage("/levels")
+	expected2.Arguments = []interface{}{[]interface{}{float32(0.5), float32(1)}}
+	result2, err2 := NewMessageFromData(data)
+
+	if err2 != nil {
+		t.Error(err2)
+	} else if !result2.Equals(expected2) {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+}
+
+func TestAddArgumentMap(t *testing.T) {
+	msg := NewMessage("/map")
+	err := msg.AddArgument(map[string]int32{"a": 1})
+
+	if err == nil {
+		t.Error("Expected an error when adding a map argument")
+	}
+
+	if len(msg.Arguments) != 0 {
+		t.Errorf("Message has %d arguments, expected 0", len(msg.Arguments))
+	}
+}
+
+func TestRegisterConverter(t *testing.T) {
+	RegisterConverter(testColour{}, func(value interface{}) (interface{}, error) {
+		c := value.(testColour)
+		return fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b), nil
+	})
+
+	msg := NewMessage("/colour")
+	err := msg.AddArgument(testColour{255, 0, 16})
+
+	if err != nil {
+		t.Error(err)
+	} else if msg.Arguments[0] != "#ff0010" {
+		t.Errorf("Got %v, expected #ff0010", msg.Arguments[0])
+	}
+}
@@ -0,0 +1,92 @@
+//go:build !oscunsafe
+
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+/*
+readUint32 reads a 32-bit big-endian integer from a buffer.
+*/
+func readUint32(buf *bytes.Buffer) (uint32, error) {
+	var val uint32
+	err := binary.Read(buf, binary.BigEndian, &val)
+
+	return val, err
+}
+
+/*
+readUint64 reads a 64-bit big-endian integer from a buffer.
+*/
+func readUint64(buf *bytes.Buffer) (uint64, error) {
+	var val uint64
+	err := binary.Read(buf, binary.BigEndian, &val)
+
+	return val, err
+}
+
+/*
+decodeString reads a 32-bit padded OSC string from a byte slice.
+*/
+func decodeString(buf *bytes.Buffer) (string, error) {
+	stringNullTerm, err := buf.ReadString('\x00')
+
+	// Read a null-terminated string
+	if err != nil {
+		return "", err
+	}
+
+	// Trim the null-termination character
+	str := strings.Trim(stringNullTerm, "\x00")
+
+	// Calculate how many more null characters we expect to pop (padded to 32 bits)
+	stringLength := len(stringNullTerm)
+	paddedLength := (stringLength + 3) &^ 0x03
+
+	// Pop the padding, and ensure the values are null
+	toPop := paddedLength - stringLength
+	for toPop > 0 {
+		b, _ := buf.ReadByte()
+		if b != '\x00' {
+			return "", fmt.Errorf("Found a malformed OSC string")
+		}
+		toPop--
+	}
+
+	return str, nil
+}
+
+/*
+decodeByteSlice reads an OSC byte array into a Go byte slice.
+*/
+func decodeByteSlice(buf *bytes.Buffer) ([]byte, error) {
+	var n int32
+	err := binary.Read(buf, binary.BigEndian, &n)
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, nil
+	} else if n < 0 || int(n) > buf.Len() {
+		return nil, fmt.Errorf("Found a malformed OSC blob")
+	}
+
+	// Increase n to the next fourth byte
+	nExpected := int((n + 3) &^ 0x03)
+
+	data := make([]byte, nExpected)
+	nRead, err := buf.Read(data)
+	if err != nil {
+		return nil, err
+	} else if nRead != nExpected {
+		return nil, fmt.Errorf("Didn't read expected number of bytes")
+	}
+
+	// Return the slice of the data part of the count
+	return data[:n], nil
+}
@@ -0,0 +1,107 @@
+//go:build oscunsafe
+
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+/*
+This file provides the fast decode path enabled by the oscunsafe build tag. Integers are read directly from the packet
+data rather than through the reflection in binary.Read, and strings and blobs are not copied: they share memory with
+the packet data they were decoded from. The packet data must therefore not be modified once decoded (e.g. by a
+PacketHook), and retaining any decoded string or blob keeps the whole packet in memory.
+*/
+
+/*
+readUint32 reads a 32-bit big-endian integer from a buffer.
+*/
+func readUint32(buf *bytes.Buffer) (uint32, error) {
+	if buf.Len() == 0 {
+		return 0, io.EOF
+	}
+
+	data := buf.Next(4)
+	if len(data) < 4 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return binary.BigEndian.Uint32(data), nil
+}
+
+/*
+readUint64 reads a 64-bit big-endian integer from a buffer.
+*/
+func readUint64(buf *bytes.Buffer) (uint64, error) {
+	if buf.Len() == 0 {
+		return 0, io.EOF
+	}
+
+	data := buf.Next(8)
+	if len(data) < 8 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return binary.BigEndian.Uint64(data), nil
+}
+
+/*
+decodeString reads a 32-bit padded OSC string from a byte slice. The string shares memory with the buffer's data.
+*/
+func decodeString(buf *bytes.Buffer) (string, error) {
+	data := buf.Bytes()
+
+	n := bytes.IndexByte(data, '\x00')
+	if n < 0 {
+		buf.Next(len(data))
+		return "", io.EOF
+	}
+
+	// Consume the string, its null-termination character and its padding, ensuring the padding is null. As with the
+	// safe decode path, padding truncated by the end of the data is accepted.
+	paddedLength := (n + 4) &^ 0x03
+	consumed := buf.Next(paddedLength)
+	for i := n + 1; i < len(consumed); i++ {
+		if consumed[i] != '\x00' {
+			return "", fmt.Errorf("Found a malformed OSC string")
+		}
+	}
+
+	if n == 0 {
+		return "", nil
+	}
+
+	return unsafe.String(&data[0], n), nil
+}
+
+/*
+decodeByteSlice reads an OSC byte array into a Go byte slice, which shares memory with the buffer's data.
+*/
+func decodeByteSlice(buf *bytes.Buffer) ([]byte, error) {
+	count, err := readUint32(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	n := int32(count)
+	if n == 0 {
+		return nil, nil
+	} else if n < 0 || int(n) > buf.Len() {
+		return nil, fmt.Errorf("Found a malformed OSC blob")
+	}
+
+	// Increase n to the next fourth byte
+	nExpected := int((n + 3) &^ 0x03)
+
+	data := buf.Next(nExpected)
+	if len(data) != nExpected {
+		return nil, fmt.Errorf("Didn't read expected number of bytes")
+	}
+
+	// Return the slice of the data part of the count, limiting its capacity so that appending to it copies
+	return data[:n:n], nil
+}
@@ -0,0 +1,69 @@
+package codec
+
+import (
+	"bytes"
+	"fmt"
+)
+
+/*
+Char is an OSC 1.1 ASCII character argument (type tag 'c'), encoded in 32 bits.
+*/
+type Char rune
+
+/*
+String implements the fmt.Stringer interface, returning the character itself.
+*/
+func (c Char) String() string {
+	return string(rune(c))
+}
+
+/*
+RGBA is an OSC 1.1 32-bit color argument (type tag 'r').
+*/
+type RGBA struct {
+	R uint8 `json:"r"`
+	G uint8 `json:"g"`
+	B uint8 `json:"b"`
+	A uint8 `json:"a"`
+}
+
+/*
+String implements the fmt.Stringer interface, returning the color in hex, e.g. "#ff8000ff".
+*/
+func (c RGBA) String() string {
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+/*
+MIDIMessage is an OSC 1.1 4-byte MIDI message argument (type tag 'm'): a port ID, followed by a status byte and two
+data bytes.
+*/
+type MIDIMessage struct {
+	Port   uint8 `json:"port"`
+	Status uint8 `json:"status"`
+	Data1  uint8 `json:"data1"`
+	Data2  uint8 `json:"data2"`
+}
+
+/*
+Infinitum is the OSC 1.1 "infinitum" argument (type tag 'I'), which has no data. It is often used to mean an
+unbounded value, e.g. an endless loop.
+*/
+type Infinitum struct{}
+
+/*
+String implements the fmt.Stringer interface.
+*/
+func (Infinitum) String() string {
+	return "Infinitum"
+}
+
+func decodeRGBA(buf *bytes.Buffer) (RGBA, error) {
+	val, err := readUint32(buf)
+	return RGBA{R: uint8(val >> 24), G: uint8(val >> 16), B: uint8(val >> 8), A: uint8(val)}, err
+}
+
+func decodeMIDIMessage(buf *bytes.Buffer) (MIDIMessage, error) {
+	val, err := readUint32(buf)
+	return MIDIMessage{Port: uint8(val >> 24), Status: uint8(val >> 16), Data1: uint8(val >> 8), Data2: uint8(val)}, err
+}
@@ -0,0 +1,51 @@
+package codec
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestExtendedTypes(t *testing.T) {
+	msg := NewMessage("/light/colour")
+	msg.AddArgument(Char('A'))
+	msg.AddArgument(RGBA{R: 0xff, G: 0x80, B: 0x00, A: 0xff})
+	msg.AddArgument(MIDIMessage{Port: 1, Status: 0x90, Data1: 60, Data2: 127})
+	msg.AddArgument(Infinitum{})
+
+	result1, err := msg.TypeTagString()
+	expected1 := ",crmI"
+	if err != nil {
+		t.Fatal(err)
+	} else if result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	data, err := msg.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected2 := []byte{
+		'/', 'l', 'i', 'g', 'h', 't', '/', 'c', 'o', 'l', 'o', 'u', 'r', 0, 0, 0,
+		',', 'c', 'r', 'm', 'I', 0, 0, 0,
+		0, 0, 0, 'A',
+		0xff, 0x80, 0x00, 0xff,
+		1, 0x90, 60, 127,
+	}
+	if !bytes.Equal(data, expected2) {
+		t.Errorf("Got %v, expected %v", data, expected2)
+	}
+
+	result3, err := NewMessageFromData(data)
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(result3.Arguments, msg.Arguments) {
+		t.Errorf("Got %v, expected %v", result3.Arguments, msg.Arguments)
+	}
+
+	expected4 := "Message: /light/colour (c)A (r)#ff8000ff (m){1 144 60 127} (I)Infinitum"
+	if result4 := msg.String(); result4 != expected4 {
+		t.Errorf("Got %v, expected %v", result4, expected4)
+	}
+}
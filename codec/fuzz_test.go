@@ -0,0 +1,41 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+)
+
+/*
+FuzzDecodePacket checks that decoding arbitrary data never panics, and that any packet decoded is encoded and decoded
+again consistently. Run it with both decode paths:
+
+	go test -fuzz FuzzDecodePacket
+	go test -tags oscunsafe -fuzz FuzzDecodePacket
+*/
+func FuzzDecodePacket(f *testing.F) {
+	for _, vector := range conformanceVectors {
+		f.Add([]byte(vector.data))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		p, err := DecodePacket(data)
+		if err != nil {
+			return
+		}
+
+		encoded, err := p.MarshalBinary()
+		if err != nil {
+			return
+		}
+
+		decoded, err := DecodePacket(encoded)
+		if err != nil {
+			t.Fatalf("Cannot decode %v, encoded from %v: %v", encoded, p, err)
+		}
+
+		reencoded, err := decoded.MarshalBinary()
+		if err != nil || !bytes.Equal(reencoded, encoded) {
+			t.Errorf("Got %v (%v), expected %v", reencoded, err, encoded)
+		}
+	})
+}
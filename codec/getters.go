@@ -0,0 +1,170 @@
+package codec
+
+import (
+	"fmt"
+)
+
+/*
+NumArguments returns the number of arguments of the message.
+*/
+func (msg *Message) NumArguments() int {
+	if msg == nil {
+		return 0
+	}
+
+	return len(msg.Arguments)
+}
+
+/*
+argumentAt returns argument i of the message, or an error if there is no such argument.
+*/
+func (msg *Message) argumentAt(i int) (interface{}, error) {
+	if i < 0 || i >= msg.NumArguments() {
+		return nil, fmt.Errorf("Argument %d out of range (message has %d arguments)", i, msg.NumArguments())
+	}
+
+	return msg.Arguments[i], nil
+}
+
+/*
+wrongType returns the error for argument i of the message not being of the expected type.
+*/
+func (msg *Message) wrongType(i int, expected string) error {
+	return fmt.Errorf("Argument %d of %s is %T, not %s", i, msg.Address, msg.Arguments[i], expected)
+}
+
+/*
+Int32At returns argument i of the message, or an error if there is no such argument or it is not an int32.
+*/
+func (msg *Message) Int32At(i int) (int32, error) {
+	arg, err := msg.argumentAt(i)
+	if err != nil {
+		return 0, err
+	}
+
+	v, ok := arg.(int32)
+	if !ok {
+		return 0, msg.wrongType(i, "int32")
+	}
+
+	return v, nil
+}
+
+/*
+Int64At returns argument i of the message, or an error if there is no such argument or it is not an int64.
+*/
+func (msg *Message) Int64At(i int) (int64, error) {
+	arg, err := msg.argumentAt(i)
+	if err != nil {
+		return 0, err
+	}
+
+	v, ok := arg.(int64)
+	if !ok {
+		return 0, msg.wrongType(i, "int64")
+	}
+
+	return v, nil
+}
+
+/*
+Float32At returns argument i of the message, or an error if there is no such argument or it is not a float32.
+*/
+func (msg *Message) Float32At(i int) (float32, error) {
+	arg, err := msg.argumentAt(i)
+	if err != nil {
+		return 0, err
+	}
+
+	v, ok := arg.(float32)
+	if !ok {
+		return 0, msg.wrongType(i, "float32")
+	}
+
+	return v, nil
+}
+
+/*
+Float64At returns argument i of the message, or an error if there is no such argument or it is not a float64.
+*/
+func (msg *Message) Float64At(i int) (float64, error) {
+	arg, err := msg.argumentAt(i)
+	if err != nil {
+		return 0, err
+	}
+
+	v, ok := arg.(float64)
+	if !ok {
+		return 0, msg.wrongType(i, "float64")
+	}
+
+	return v, nil
+}
+
+/*
+StringAt returns argument i of the message, or an error if there is no such argument or it is not a string.
+*/
+func (msg *Message) StringAt(i int) (string, error) {
+	arg, err := msg.argumentAt(i)
+	if err != nil {
+		return "", err
+	}
+
+	v, ok := arg.(string)
+	if !ok {
+		return "", msg.wrongType(i, "string")
+	}
+
+	return v, nil
+}
+
+/*
+BlobAt returns argument i of the message, or an error if there is no such argument or it is not a blob.
+*/
+func (msg *Message) BlobAt(i int) ([]byte, error) {
+	arg, err := msg.argumentAt(i)
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := arg.([]byte)
+	if !ok {
+		return nil, msg.wrongType(i, "blob")
+	}
+
+	return v, nil
+}
+
+/*
+BoolAt returns argument i of the message, or an error if there is no such argument or it is not a boolean ('T' or 'F').
+*/
+func (msg *Message) BoolAt(i int) (bool, error) {
+	arg, err := msg.argumentAt(i)
+	if err != nil {
+		return false, err
+	}
+
+	v, ok := arg.(bool)
+	if !ok {
+		return false, msg.wrongType(i, "bool")
+	}
+
+	return v, nil
+}
+
+/*
+TimeTagAt returns argument i of the message, or an error if there is no such argument or it is not a time tag.
+*/
+func (msg *Message) TimeTagAt(i int) (TimeTag, error) {
+	arg, err := msg.argumentAt(i)
+	if err != nil {
+		return TimeTag{}, err
+	}
+
+	v, ok := arg.(TimeTag)
+	if !ok {
+		return TimeTag{}, msg.wrongType(i, "TimeTag")
+	}
+
+	return v, nil
+}
@@ -0,0 +1,73 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTypedGetters(t *testing.T) {
+	msg := NewMessage("/ch/1/config")
+	msg.AddArgument(int32(1))
+	msg.AddArgument(float32(0.5))
+	msg.AddArgument("Vocals")
+	msg.AddArgument([]byte{1, 2})
+	msg.AddArgument(true)
+	msg.AddArgument(int64(2))
+	msg.AddArgument(float64(0.25))
+
+	if result := msg.NumArguments(); result != 7 {
+		t.Errorf("Got %v, expected %v", result, 7)
+	}
+
+	result1, err := msg.Int32At(0)
+	if err != nil || result1 != 1 {
+		t.Errorf("Got %v (%v), expected %v", result1, err, 1)
+	}
+
+	result2, err := msg.Float32At(1)
+	if err != nil || result2 != 0.5 {
+		t.Errorf("Got %v (%v), expected %v", result2, err, 0.5)
+	}
+
+	result3, err := msg.StringAt(2)
+	if err != nil || result3 != "Vocals" {
+		t.Errorf("Got %v (%v), expected %v", result3, err, "Vocals")
+	}
+
+	result4, err := msg.BlobAt(3)
+	if err != nil || !bytes.Equal(result4, []byte{1, 2}) {
+		t.Errorf("Got %v (%v), expected %v", result4, err, []byte{1, 2})
+	}
+
+	result5, err := msg.BoolAt(4)
+	if err != nil || !result5 {
+		t.Errorf("Got %v (%v), expected %v", result5, err, true)
+	}
+
+	result6, err := msg.Int64At(5)
+	if err != nil || result6 != 2 {
+		t.Errorf("Got %v (%v), expected %v", result6, err, 2)
+	}
+
+	result7, err := msg.Float64At(6)
+	if err != nil || result7 != 0.25 {
+		t.Errorf("Got %v (%v), expected %v", result7, err, 0.25)
+	}
+
+	_, err = msg.Int32At(2)
+	expected8 := "Argument 2 of /ch/1/config is string, not int32"
+	if err == nil || err.Error() != expected8 {
+		t.Errorf("Got %v, expected %v", err, expected8)
+	}
+
+	_, err = msg.StringAt(7)
+	expected9 := "Argument 7 out of range (message has 7 arguments)"
+	if err == nil || err.Error() != expected9 {
+		t.Errorf("Got %v, expected %v", err, expected9)
+	}
+
+	var nilMsg *Message
+	if _, err := nilMsg.TimeTagAt(0); err == nil {
+		t.Error("Expected an error getting an argument of a nil message")
+	}
+}
@@ -0,0 +1,99 @@
+package codec
+
+import (
+	"fmt"
+)
+
+/*
+KeyValue is a named argument, in the convention of arguments alternating between string keys and values (e.g. the
+controls of a SuperCollider "/s_new" command: "freq", 440, "amp", 0.5).
+*/
+type KeyValue struct {
+	Key   string
+	Value interface{}
+}
+
+/*
+KeyValues is an ordered list of named arguments. Keys may be repeated.
+*/
+type KeyValues []KeyValue
+
+/*
+AddKeyValue appends a key and its value to the message's arguments.
+*/
+func (msg *Message) AddKeyValue(key string, value interface{}) error {
+	converted, err := convertArgument(value)
+	if err != nil {
+		return err
+	}
+
+	msg.Arguments = append(msg.Arguments, key, converted)
+
+	return nil
+}
+
+/*
+AddKeyValues appends each key and value in kvs to the message's arguments.
+*/
+func (msg *Message) AddKeyValues(kvs KeyValues) error {
+	for _, kv := range kvs {
+		err := msg.AddKeyValue(kv.Key, kv.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+KeyValues interprets the message's arguments from index start onwards as alternating keys and values. An error is
+returned if a key is not a string, or the last key has no value.
+*/
+func (msg *Message) KeyValues(start int) (KeyValues, error) {
+	if start < 0 || start > len(msg.Arguments) {
+		return nil, fmt.Errorf("Message %s has no argument %d", msg.Address, start)
+	}
+
+	args := msg.Arguments[start:]
+	if len(args)%2 != 0 {
+		return nil, fmt.Errorf("Key %v has no value", args[len(args)-1])
+	}
+
+	kvs := make(KeyValues, 0, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("Argument %d (%v) is not a string key", start+i, args[i])
+		}
+
+		kvs = append(kvs, KeyValue{Key: key, Value: args[i+1]})
+	}
+
+	return kvs, nil
+}
+
+/*
+Get returns the value of the last occurrence of key, and whether it was found.
+*/
+func (kvs KeyValues) Get(key string) (interface{}, bool) {
+	for i := len(kvs) - 1; i >= 0; i-- {
+		if kvs[i].Key == key {
+			return kvs[i].Value, true
+		}
+	}
+
+	return nil, false
+}
+
+/*
+Map returns the keys and values as a map. If a key is repeated, its last value is used.
+*/
+func (kvs KeyValues) Map() map[string]interface{} {
+	m := make(map[string]interface{}, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+
+	return m
+}
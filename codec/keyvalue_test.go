@@ -0,0 +1,45 @@
+package codec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyValues(t *testing.T) {
+	msg := NewMessage("/s_new")
+	msg.AddArgument("default")
+	msg.AddArgument(int32(1000))
+	if err := msg.AddKeyValues(KeyValues{{"freq", int32(440)}, {"amp", 0.5}}); err != nil {
+		t.Fatal(err)
+	}
+	msg.AddKeyValue("freq", float32(220))
+
+	test1, err1 := msg.KeyValues(2)
+	expected1 := map[string]interface{}{"freq": float32(220), "amp": float64(0.5)}
+	result1 := test1.Map()
+
+	if err1 != nil {
+		t.Error(err1)
+	} else if !reflect.DeepEqual(result1, expected1) {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	expected2 := int32(440)
+	result2, _ := test1.Get("freq")
+	if len(test1) != 3 || test1[0].Value != expected2 {
+		t.Errorf("Got %v, expected %v first", test1, expected2)
+	} else if result2 != float32(220) {
+		t.Errorf("Got %v, expected %v", result2, float32(220))
+	}
+
+	// Keys must be strings, and have values
+	if _, err := msg.KeyValues(1); err == nil {
+		t.Error("Expected an error for a missing value")
+	}
+	test3 := NewMessage("/n_set")
+	test3.AddArgument(int32(1000))
+	test3.AddArgument(int32(0))
+	if _, err := test3.KeyValues(0); err == nil {
+		t.Error("Expected an error for a non-string key")
+	}
+}
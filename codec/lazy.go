@@ -0,0 +1,121 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+)
+
+/*
+LazyMessage is an OSC message that decodes only its address and type tag string up front, and decodes its arguments
+on first access. It keeps the encoded message, which MarshalBinary returns verbatim, making it suitable for routers that
+forward most messages untouched. It is safe for concurrent use once decoded.
+*/
+type LazyMessage struct {
+	Address string
+
+	typeTagString string
+	data          []byte
+	argData       []byte
+
+	decodeOnce sync.Once
+	args       []interface{}
+	argsErr    error
+}
+
+// Compile-time check to ensure LazyMessage implements the Packet interface.
+var _ Packet = &LazyMessage{}
+
+/*
+NewLazyMessage decodes the address and type tag string of an encoded message. The message keeps a reference to data,
+which must not be modified afterwards.
+*/
+func NewLazyMessage(data []byte) (*LazyMessage, error) {
+	msg := &LazyMessage{}
+	err := msg.UnmarshalBinary(data)
+
+	return msg, err
+}
+
+/*
+UnmarshalBinary decodes the address and type tag string of an encoded message, deferring the decoding of arguments.
+*/
+func (msg *LazyMessage) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+
+	address, err := decodeString(buf)
+	if err != nil {
+		return err
+	}
+
+	typeTagString, err := decodeString(buf)
+	if err != nil {
+		return err
+	}
+
+	if len(typeTagString) == 0 || typeTagString[0] != ',' {
+		return errors.New("Malformed type tag string")
+	}
+
+	*msg = LazyMessage{
+		Address:       address,
+		typeTagString: typeTagString,
+		data:          data,
+		argData:       buf.Bytes(),
+	}
+
+	return nil
+}
+
+/*
+TypeTagString returns the type tag string of the message, as it was encoded.
+*/
+func (msg *LazyMessage) TypeTagString() string {
+	return msg.typeTagString
+}
+
+/*
+Arguments decodes (if not already decoded) and returns the arguments of the message.
+*/
+func (msg *LazyMessage) Arguments() ([]interface{}, error) {
+	msg.decodeOnce.Do(func() {
+		msg.args, msg.argsErr = readArguments(msg.typeTagString, bytes.NewBuffer(msg.argData))
+	})
+
+	return msg.args, msg.argsErr
+}
+
+/*
+Message returns the fully decoded Message.
+*/
+func (msg *LazyMessage) Message() (*Message, error) {
+	args, err := msg.Arguments()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{Address: msg.Address, Arguments: args}, nil
+}
+
+/*
+MarshalBinary returns the encoded message exactly as it was received, without re-encoding it.
+*/
+func (msg *LazyMessage) MarshalBinary() ([]byte, error) {
+	if msg == nil || msg.data == nil {
+		return nil, errors.New("Cannot encode an empty lazy message")
+	}
+
+	return msg.data, nil
+}
+
+/*
+String implements the fmt.Stringer interface, decoding the arguments if necessary.
+*/
+func (msg *LazyMessage) String() string {
+	m, err := msg.Message()
+	if err != nil {
+		return "Message: " + msg.Address + " (malformed arguments)"
+	}
+
+	return m.String()
+}
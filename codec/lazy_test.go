@@ -0,0 +1,47 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLazyMessage(t *testing.T) {
+	data := []byte{'/', 'f', 'o', 'o', '\x00', '\x00', '\x00', '\x00', ',', 'i', 's', '\x00', '\x00', '\x00', '\x03', '\xe8', '\x68', '\x65', '\x6c', '\x6c', '\x6f', '\x00', '\x00', '\x00'}
+
+	msg, err := NewLazyMessage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Address != "/foo" || msg.TypeTagString() != ",is" {
+		t.Errorf("Got address \"%s\" and type tags \"%s\", expected \"/foo\" and \",is\"", msg.Address, msg.TypeTagString())
+	}
+
+	expected1 := NewMessage("/foo")
+	expected1.AddArgument(int32(1000))
+	expected1.AddArgument("hello")
+	result1, err1 := msg.Message()
+
+	if err1 != nil {
+		t.Error(err1)
+	} else if !result1.Equals(expected1) {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	// The original encoding is returned verbatim
+	result2, err2 := msg.MarshalBinary()
+
+	if err2 != nil {
+		t.Error(err2)
+	} else if !bytes.Equal(result2, data) {
+		t.Errorf("Got %v, expected %v", result2, data)
+	}
+
+	// Malformed arguments are only detected when accessed
+	truncated, err3 := NewLazyMessage(data[:18])
+	if err3 != nil {
+		t.Error(err3)
+	} else if _, err := truncated.Arguments(); err == nil {
+		t.Error("Expected an error decoding truncated arguments")
+	}
+}
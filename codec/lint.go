@@ -0,0 +1,56 @@
+package codec
+
+import (
+	"fmt"
+)
+
+/*
+Lint checks the bundle, and any bundles nested inside it, for violations of the OSC specification which MarshalBinary
+does not reject, returning an error for each one found. Currently this reports nested bundles whose time tags are
+earlier than that of their enclosing bundle, which strict receivers may reject.
+*/
+func (bun *Bundle) Lint() []error {
+	if bun == nil {
+		return nil
+	}
+
+	return bun.lint(bun.TimeTag, "bundle")
+}
+
+func (bun *Bundle) lint(effective TimeTag, path string) []error {
+	var errs []error
+
+	for i, e := range bun.Elements {
+		inner, ok := e.(*Bundle)
+		if !ok || inner == nil {
+			continue
+		}
+
+		innerPath := fmt.Sprintf("%s element %d", path, i)
+
+		if err := checkNestedTimeTag(effective, inner.TimeTag); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", innerPath, err))
+		}
+
+		innerEffective := inner.TimeTag
+		if innerEffective.Immediate {
+			innerEffective = effective
+		}
+
+		errs = append(errs, inner.lint(innerEffective, innerPath)...)
+	}
+
+	return errs
+}
+
+/*
+MarshalStrict is like MarshalBinary, but first checks the bundle with Lint, returning the first violation found as an
+error instead of encoding a bundle that strict receivers may reject.
+*/
+func (bun *Bundle) MarshalStrict() ([]byte, error) {
+	if errs := bun.Lint(); len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	return bun.MarshalBinary()
+}
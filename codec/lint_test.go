@@ -0,0 +1,40 @@
+package codec
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBundleLint(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	early := &Bundle{TimeTag: NewTimeTag(start.Add(-time.Second))}
+	early.AddPacket(NewMessage("/early"))
+
+	valid := &Bundle{TimeTag: NewTimeTag(start.Add(time.Second))}
+	valid.AddPacket(early)
+
+	outer := &Bundle{TimeTag: NewTimeTag(start)}
+	outer.AddPacket(NewMessage("/first"))
+	outer.AddPacket(valid)
+	outer.AddPacket(early)
+
+	// The early bundle is reported both where it is nested directly, and inside the valid bundle
+	errs := outer.Lint()
+	if len(errs) != 2 {
+		t.Errorf("Got %v, expected 2 errors", errs)
+	}
+
+	if _, err := outer.MarshalStrict(); err == nil {
+		t.Error("Expected MarshalStrict to reject the bundle")
+	}
+
+	// MarshalBinary still encodes the bundle
+	if _, err := outer.MarshalBinary(); err != nil {
+		t.Error(err)
+	}
+
+	if errs := valid.Lint(); len(errs) != 1 {
+		t.Errorf("Got %v, expected 1 error", errs)
+	}
+}
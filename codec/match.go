@@ -0,0 +1,189 @@
+package codec
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Match returns true if the OSC address matches the OSC address pattern. The following pattern syntax is supported:
+
+	?        matches any single character except '/'
+	*        matches any sequence of zero or more characters except '/'
+	[chars]  matches any one of the characters, with '-' denoting a range (e.g. [a-z]) and a leading '!' negating it
+	{a,b}    matches any one of the comma-separated strings
+	//       matches any number of address parts (OSC 1.1 path traversal)
+
+Matching operates directly on the bytes of the two strings, and does not allocate.
+*/
+func Match(pattern, address string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			// Consecutive stars are equivalent to a single star
+			for len(pattern) > 0 && pattern[0] == '*' {
+				pattern = pattern[1:]
+			}
+
+			// Try every possible length of the starred section, up to the end of the current address part
+			for i := 0; ; i++ {
+				if Match(pattern, address[i:]) {
+					return true
+				}
+
+				if i == len(address) || address[i] == '/' {
+					return false
+				}
+			}
+		case '?':
+			if len(address) == 0 || address[0] == '/' {
+				return false
+			}
+
+			pattern, address = pattern[1:], address[1:]
+		case '[':
+			end := strings.IndexByte(pattern, ']')
+			if end < 0 || len(address) == 0 || address[0] == '/' {
+				return false
+			}
+
+			if !matchCharClass(pattern[1:end], address[0]) {
+				return false
+			}
+
+			pattern, address = pattern[end+1:], address[1:]
+		case '{':
+			end := strings.IndexByte(pattern, '}')
+			if end < 0 {
+				return false
+			}
+
+			alternatives, rest := pattern[1:end], pattern[end+1:]
+			for {
+				comma := strings.IndexByte(alternatives, ',')
+
+				alternative := alternatives
+				if comma >= 0 {
+					alternative = alternatives[:comma]
+				}
+
+				if strings.HasPrefix(address, alternative) && Match(rest, address[len(alternative):]) {
+					return true
+				}
+
+				if comma < 0 {
+					return false
+				}
+
+				alternatives = alternatives[comma+1:]
+			}
+		case '/':
+			if len(pattern) > 1 && pattern[1] == '/' {
+				// Path traversal: the remainder of the pattern (starting at its second slash) may match from the start
+				// of any later address part
+				rest := pattern[1:]
+				for i := 0; i < len(address); i++ {
+					if address[i] == '/' && Match(rest, address[i:]) {
+						return true
+					}
+				}
+
+				return false
+			}
+
+			if len(address) == 0 || address[0] != '/' {
+				return false
+			}
+
+			pattern, address = pattern[1:], address[1:]
+		default:
+			if len(address) == 0 || address[0] != pattern[0] {
+				return false
+			}
+
+			pattern, address = pattern[1:], address[1:]
+		}
+	}
+
+	return len(address) == 0
+}
+
+/*
+matchCharClass returns true if c is matched by the contents of a bracketed character class (excluding the brackets).
+*/
+func matchCharClass(class string, c byte) bool {
+	negate := false
+	if len(class) > 0 && class[0] == '!' {
+		negate = true
+		class = class[1:]
+	}
+
+	matched := false
+	for i := 0; i < len(class); i++ {
+		// A '-' between two characters denotes a range, otherwise it is literal
+		if i+2 < len(class) && class[i+1] == '-' {
+			lo, hi := class[i], class[i+2]
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+
+			if c >= lo && c <= hi {
+				matched = true
+			}
+
+			i += 2
+		} else if class[i] == c {
+			matched = true
+		}
+	}
+
+	return matched != negate
+}
+
+/*
+ValidatePattern returns an error if the OSC address pattern is malformed, e.g. if it contains unbalanced brackets.
+*/
+func ValidatePattern(pattern string) error {
+	if len(pattern) == 0 || pattern[0] != '/' {
+		return fmt.Errorf("Address pattern \"%s\" must start with '/'", pattern)
+	}
+
+	inClass, inGroup := false, false
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+
+		switch {
+		case inClass:
+			if c == ']' {
+				inClass = false
+			} else if c == '/' {
+				return fmt.Errorf("Address pattern \"%s\" has an unterminated '['", pattern)
+			}
+		case c == '[':
+			inClass = true
+		case c == ']':
+			return fmt.Errorf("Address pattern \"%s\" has an unexpected ']'", pattern)
+		case c == '{':
+			if inGroup {
+				return fmt.Errorf("Address pattern \"%s\" has nested '{'", pattern)
+			}
+			inGroup = true
+		case c == '}':
+			if !inGroup {
+				return fmt.Errorf("Address pattern \"%s\" has an unexpected '}'", pattern)
+			}
+			inGroup = false
+		case inGroup && c == '/':
+			return fmt.Errorf("Address pattern \"%s\" has an unterminated '{'", pattern)
+		}
+	}
+
+	if inClass {
+		return fmt.Errorf("Address pattern \"%s\" has an unterminated '['", pattern)
+	} else if inGroup {
+		return fmt.Errorf("Address pattern \"%s\" has an unterminated '{'", pattern)
+	}
+
+	return nil
+}
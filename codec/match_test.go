@@ -0,0 +1,78 @@
+package codec
+
+import (
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		address  string
+		expected bool
+	}{
+		{"/foo", "/foo", true},
+		{"/foo", "/foo/bar", false},
+		{"/foo/bar", "/foo", false},
+		{"/foo", "/x/foo", false},
+		{"/fo?", "/foo", true},
+		{"/fo?", "/fo/", false},
+		{"/f*", "/foo", true},
+		{"/f*", "/f", true},
+		{"/f*", "/foo/bar", false},
+		{"/*/bar", "/foo/bar", true},
+		{"/*o*r", "/foobar", true},
+		{"/oscillator/[1-4]/frequency", "/oscillator/3/frequency", true},
+		{"/oscillator/[1-4]/frequency", "/oscillator/5/frequency", false},
+		{"/oscillator/[!1-4]/frequency", "/oscillator/5/frequency", true},
+		{"/oscillator/[!1-4]/frequency", "/oscillator/2/frequency", false},
+		{"/[abc-]", "/-", true},
+		{"/{foo,bar}/x", "/bar/x", true},
+		{"/{foo,bar}/x", "/baz/x", false},
+		{"/{foo,fo}o", "/foo", true},
+		{"/a//c", "/a/c", true},
+		{"/a//c", "/a/b/c", true},
+		{"/a//c", "/a/b/d/c", true},
+		{"/a//c", "/a/b/d", false},
+		{"//c", "/x/y/c", true},
+	}
+
+	for _, test := range tests {
+		result := Match(test.pattern, test.address)
+
+		if result != test.expected {
+			t.Errorf("Match(\"%s\", \"%s\") is %v, expected %v", test.pattern, test.address, result, test.expected)
+		}
+	}
+}
+
+func TestMatchAllocations(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		Match("/mixer/{ch,bus}/[0-9]*/fader", "/mixer/bus/12/fader")
+	})
+
+	if allocs != 0 {
+		t.Errorf("Match allocated %v times, expected 0", allocs)
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	valid := []string{"/", "/foo", "/foo/[a-z]/{x,y}/*", "/a//b"}
+	for _, pattern := range valid {
+		if err := ValidatePattern(pattern); err != nil {
+			t.Errorf("Pattern \"%s\" should be valid: %v", pattern, err)
+		}
+	}
+
+	invalid := []string{"", "foo", "/foo/[a", "/foo]", "/{a,b", "/a}", "/{a,{b}}", "/[a/b]", "/{a/b}"}
+	for _, pattern := range invalid {
+		if err := ValidatePattern(pattern); err == nil {
+			t.Errorf("Pattern \"%s\" should be invalid", pattern)
+		}
+	}
+}
+
+func BenchmarkMatch(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Match("/mixer/{ch,bus}/[0-9]*/fader", "/mixer/bus/12/fader")
+	}
+}
@@ -0,0 +1,327 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/*
+Message represents a single OSC message with address pattern and arguments. Messages implement Packet by pointer, and
+should always be passed around as *Message.
+*/
+type Message struct {
+	Address   string
+	Arguments []interface{}
+}
+
+// Compile-time check to ensure Message implements the Packet interface.
+var _ Packet = &Message{}
+
+/*
+NewEmptyMessage returns an OSC message with default values.
+*/
+func NewEmptyMessage() *Message {
+	return NewMessage("/")
+}
+
+/*
+NewMessage creates a new OSC message with an address pattern, and empty arguments.
+*/
+func NewMessage(address string) *Message {
+	return &Message{Address: address}
+}
+
+/*
+NewMessageFromData is a convenience function to unmarshal a message from a byte slice.
+*/
+func NewMessageFromData(data []byte) (*Message, error) {
+	var msg = &Message{}
+	err := msg.UnmarshalBinary(data)
+
+	return msg, err
+}
+
+/*
+UnmarshalBinary attempts to create a new Message from an encoded byte slice. Any bytes following the arguments are
+ignored; use UnmarshalStrict to detect them.
+*/
+func (msg *Message) UnmarshalBinary(data []byte) error {
+	_, err := msg.unmarshal(data)
+
+	return err
+}
+
+/*
+UnmarshalStrict is like UnmarshalBinary, but returns a *TrailingDataError if there are bytes following the arguments,
+which usually indicates a truncated or corrupted packet (or a mismatch between the type tags and the data). In that case
+the message is still decoded. The error notes whether the trailing bytes match a payload wrongly encoded for each
+payload-less argument (see CheckPayloadless).
+*/
+func (msg *Message) UnmarshalStrict(data []byte) error {
+	n, err := msg.unmarshal(data)
+	if err == nil && n < len(data) {
+		trailing := &TrailingDataError{Address: msg.Address, Trailing: len(data) - n}
+
+		buf := bytes.NewBuffer(data)
+		decodeString(buf)
+		typeTagString, _ := decodeString(buf)
+		if count := countPayloadless(typeTagString); count > 0 && trailing.Trailing == 4*count {
+			trailing.Payloadless = true
+		}
+
+		err = trailing
+	}
+
+	return err
+}
+
+/*
+UnmarshalBinaryN decodes a message from the start of data, returning the number of bytes consumed. Unlike
+UnmarshalBinary, data may be followed by further packets, making it suitable for parsing concatenated messages.
+*/
+func (msg *Message) UnmarshalBinaryN(data []byte) (n int, err error) {
+	return msg.unmarshal(data)
+}
+
+/*
+unmarshal decodes a message from the start of data, returning the number of bytes consumed.
+*/
+func (msg *Message) unmarshal(data []byte) (int, error) {
+	buf := bytes.NewBuffer(data)
+
+	address, err := decodeString(buf)
+	if err != nil {
+		return 0, err
+	}
+
+	typeTagString, err := decodeString(buf)
+	if err != nil {
+		return 0, err
+	}
+
+	// Reuse the capacity of an empty argument slice, e.g. that of a message which has been Reset
+	var args []interface{}
+	if len(msg.Arguments) == 0 {
+		args = msg.Arguments
+	}
+
+	args, err = appendArguments(args, typeTagString, buf)
+	if err != nil {
+		return 0, err
+	}
+
+	msg.Address = address
+	msg.Arguments = args
+
+	return len(data) - buf.Len(), nil
+}
+
+/*
+TrailingDataError reports bytes left over after decoding the arguments of a message.
+*/
+type TrailingDataError struct {
+	Address  string
+	Trailing int
+	// Payloadless is true if there are 4 trailing bytes for each payload-less (T, F, N or I) argument, suggesting the
+	// sender wrongly encoded a payload for them
+	Payloadless bool
+}
+
+func (e *TrailingDataError) Error() string {
+	if e.Payloadless {
+		return fmt.Sprintf("Message %s has %d trailing bytes after its arguments, as if its sender encoded data for its "+
+			"payload-less arguments", e.Address, e.Trailing)
+	}
+
+	return fmt.Sprintf("Message %s has %d trailing bytes after its arguments", e.Address, e.Trailing)
+}
+
+/*
+AddArgument appends a value to the Message's Arguments. Values of types without a direct OSC equivalent are converted
+where possible: slices and arrays (other than []byte) become OSC arrays, and types with a converter added by
+RegisterConverter are converted by it. An error is returned for values that cannot be converted, such as maps.
+*/
+func (msg *Message) AddArgument(arg interface{}) error {
+	converted, err := convertArgument(arg)
+	if err != nil {
+		return err
+	}
+
+	msg.Arguments = append(msg.Arguments, converted)
+
+	return nil
+}
+
+/*
+TypeTagString generates the type tag string for the Message Arguments.
+*/
+func (msg *Message) TypeTagString() (string, error) {
+	typeTagString := ","
+
+	for _, arg := range msg.Arguments {
+		tag, err := typeTag(arg)
+
+		if err != nil {
+			return "", err
+		}
+
+		typeTagString += tag
+	}
+
+	return typeTagString, nil
+}
+
+/*
+AddressParts returns an ordered array of the individual address parts of the Address of msg.
+*/
+func (msg *Message) AddressParts() []string {
+	parts := strings.Split(msg.Address, "/")
+	return parts
+}
+
+/*
+String implements the fmt.Stringer interface.
+*/
+func (msg *Message) String() string {
+	if msg == nil {
+		return "Message: <nil>"
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString("Message: ")
+	buf.WriteString(msg.Address)
+
+	for _, arg := range msg.Arguments {
+		buf.WriteString(" (")
+		typeTag, _ := typeTag(arg)
+		buf.WriteString(typeTag)
+		buf.WriteString(")")
+		buf.WriteString(fmt.Sprintf("%v", arg))
+	}
+
+	return buf.String()
+
+	// return fmt.Sprintf("Message{Address: %s, Arguments: %v}", msg.Address, msg.Arguments)
+}
+
+/*
+MarshalBinary encodes the Message as per the OSC standard.
+*/
+func (msg *Message) MarshalBinary() (data []byte, err error) {
+	if msg == nil {
+		return nil, errors.New("Cannot encode a nil message")
+	}
+
+	return msg.appendBinary(make([]byte, 0, msg.encodedSizeHint()))
+}
+
+/*
+appendBinary appends the encoding of the Message to dst, returning the extended slice.
+*/
+func (msg *Message) appendBinary(dst []byte) ([]byte, error) {
+	dst = appendString(dst, msg.Address)
+
+	// Encode the type tag string in place, then pad it once its length is known
+	start := len(dst)
+	dst, err := appendTypeTags(append(dst, ','), msg.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	dst = append(dst, '\x00')
+	dst = appendPadding(dst, len(dst)-start)
+
+	for _, arg := range msg.Arguments {
+		dst, err = appendArgument(dst, arg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dst, nil
+}
+
+/*
+encodedSizeHint estimates the encoded size of the Message, so that it can usually be encoded without reallocating.
+*/
+func (msg *Message) encodedSizeHint() int {
+	size := len(msg.Address) + 8 + 2*len(msg.Arguments)
+
+	for _, arg := range msg.Arguments {
+		switch v := arg.(type) {
+		case string:
+			size += len(v) + 4
+		case []byte:
+			size += len(v) + 8
+		default:
+			size += 8
+		}
+	}
+
+	return size
+}
+
+/*
+Equals returns true if msg is equal to other, otherwise false.
+*/
+func (msg *Message) Equals(other *Message) bool {
+	if msg == other {
+		return true
+	} else if msg == nil || other == nil {
+		return false
+	}
+
+	addressEq := msg.Address == other.Address
+
+	argsEq := reflect.DeepEqual(msg.Arguments, other.Arguments)
+
+	return addressEq && argsEq
+}
+
+/*
+Clone returns a deep copy of the message, sharing no memory with the original: blobs and arrays among its arguments are
+copied too.
+*/
+func (msg *Message) Clone() *Message {
+	if msg == nil {
+		return nil
+	}
+
+	return &Message{Address: msg.Address, Arguments: cloneArguments(msg.Arguments)}
+}
+
+func cloneArguments(args []interface{}) []interface{} {
+	if args == nil {
+		return nil
+	}
+
+	cloned := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case []byte:
+			cloned[i] = append([]byte(nil), v...)
+		case []interface{}:
+			cloned[i] = cloneArguments(v)
+		default:
+			cloned[i] = arg
+		}
+	}
+
+	return cloned
+}
+
+/*
+Reset clears the message's address and arguments, keeping the capacity of its argument slice so that decoding another
+message into it with UnmarshalBinary does not allocate a new one.
+*/
+func (msg *Message) Reset() {
+	for i := range msg.Arguments {
+		msg.Arguments[i] = nil
+	}
+
+	msg.Address = ""
+	msg.Arguments = msg.Arguments[:0]
+}
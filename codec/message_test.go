@@ -1,7 +1,8 @@
-package osc
+package codec
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -154,3 +155,114 @@ func TestUnmarshalBinary(t *testing.T) {
 		t.Errorf("Got %v, expected %v", result4, expected4)
 	}
 }
+
+func TestNilMessage(t *testing.T) {
+	var msg *Message
+
+	_, err := msg.MarshalBinary()
+	if err == nil {
+		t.Error("Expected an error when encoding a nil message")
+	}
+
+	if msg.Equals(NewEmptyMessage()) {
+		t.Error("A nil message should not equal an empty message")
+	}
+
+	var p Packet = msg
+	if IsMessage(p) {
+		t.Error("A typed nil message should not be visited")
+	}
+}
+
+func TestMessageUnmarshalStrict(t *testing.T) {
+	msg := NewMessage("/fader")
+	msg.AddArgument(float32(0.5))
+	data, _ := msg.MarshalBinary()
+
+	var result1 Message
+	if err := result1.UnmarshalStrict(data); err != nil {
+		t.Error(err)
+	}
+
+	// Trailing bytes are reported, but the message is still decoded
+	var result2 Message
+	err := result2.UnmarshalStrict(append(data, 0, 0, 0, 0))
+	if trailing, ok := err.(*TrailingDataError); !ok || trailing.Trailing != 4 {
+		t.Errorf("Got %v, expected a TrailingDataError for 4 bytes", err)
+	}
+	if !result2.Equals(msg) {
+		t.Errorf("Got %v, expected %v", &result2, msg)
+	}
+
+	bundle := NewBundle()
+	bundle.AddPacket(msg)
+	bundleData, _ := bundle.MarshalBinary()
+	// Extend the element size and data of the message by 4 bytes
+	bundleData[19] += 4
+	bundleData = append(bundleData, 0, 0, 0, 0)
+
+	p, err := DecodePacketStrict(bundleData)
+	if _, ok := err.(*TrailingDataError); !ok || p == nil {
+		t.Errorf("Got %v, %v, expected a bundle and a TrailingDataError", p, err)
+	}
+
+	if _, err := DecodePacket(bundleData); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMessageUnmarshalBinaryN(t *testing.T) {
+	msg1 := NewMessage("/a")
+	msg1.AddArgument("first")
+	msg2 := NewMessage("/b")
+	msg2.AddArgument(int32(2))
+
+	data1, _ := msg1.MarshalBinary()
+	data2, _ := msg2.MarshalBinary()
+	data := append(append([]byte{}, data1...), data2...)
+
+	var results []*Message
+	for len(data) > 0 {
+		var m Message
+		n, err := m.UnmarshalBinaryN(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		results = append(results, &m)
+		data = data[n:]
+	}
+
+	if len(results) != 2 || !results[0].Equals(msg1) || !results[1].Equals(msg2) {
+		t.Errorf("Got %v, expected [%v %v]", results, msg1, msg2)
+	}
+}
+
+func TestMessageClone(t *testing.T) {
+	msg := NewMessage("/blob")
+	msg.AddArgument([]byte{1, 2, 3})
+	msg.AddArgument([]interface{}{int32(1), "a"})
+
+	clone := msg.Clone()
+	if !clone.Equals(msg) {
+		t.Errorf("Got %v, expected %v", clone, msg)
+	}
+
+	msg.Arguments[0].([]byte)[0] = 9
+	msg.Arguments[1].([]interface{})[0] = int32(2)
+
+	expected1 := []interface{}{[]byte{1, 2, 3}, []interface{}{int32(1), "a"}}
+	if !reflect.DeepEqual(clone.Arguments, expected1) {
+		t.Errorf("Got %v, expected %v", clone.Arguments, expected1)
+	}
+
+	// A message which has been reset reuses its argument slice when decoded into
+	data, _ := clone.MarshalBinary()
+	args := msg.Arguments
+	msg.Reset()
+
+	err := msg.UnmarshalBinary(data)
+	if err != nil || !msg.Equals(clone) || &msg.Arguments[0] != &args[0] {
+		t.Errorf("Got %v (%v), expected %v decoded into the same argument slice", msg, err, clone)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Package codec implements encoding and decoding of OSC messages and bundles. It has no dependency on package net, so it
+can be used on its own in embedded builds (e.g. with TinyGo) where the transports of package osc are unavailable.
+*/
+package codec
+
+import (
+	"encoding"
+	"fmt"
+)
+
+/*
+Packet represents and encodable OSC packet.
+*/
+type Packet interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	fmt.Stringer
+}
+
+/*
+Visit invokes onMessage if p is a Message, or onBundle if p is a Bundle. Either callback may be nil, and nil
+Packets (including typed nil pointers) are ignored.
+*/
+func Visit(p Packet, onMessage func(*Message), onBundle func(*Bundle)) {
+	switch e := p.(type) {
+	case *Message:
+		if e != nil && onMessage != nil {
+			onMessage(e)
+		}
+	case *Bundle:
+		if e != nil && onBundle != nil {
+			onBundle(e)
+		}
+	}
+}
+
+/*
+IsMessage returns true if p is a Message.
+*/
+func IsMessage(p Packet) bool {
+	isMessage := false
+	Visit(p, func(*Message) { isMessage = true }, nil)
+
+	return isMessage
+}
+
+/*
+IsBundle returns true if p is a Bundle.
+*/
+func IsBundle(p Packet) bool {
+	isBundle := false
+	Visit(p, nil, func(*Bundle) { isBundle = true })
+
+	return isBundle
+}
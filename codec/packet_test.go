@@ -0,0 +1,28 @@
+package codec
+
+import (
+	"testing"
+)
+
+func TestVisit(t *testing.T) {
+	var messages, bundles int
+	onMessage := func(*Message) { messages++ }
+	onBundle := func(*Bundle) { bundles++ }
+
+	Visit(NewMessage("/foo"), onMessage, onBundle)
+	Visit(NewBundle(), onMessage, onBundle)
+	Visit(nil, onMessage, onBundle)
+	Visit((*Message)(nil), onMessage, onBundle)
+
+	if messages != 1 || bundles != 1 {
+		t.Errorf("Got %d messages and %d bundles, expected 1 of each", messages, bundles)
+	}
+
+	if !IsMessage(NewMessage("/foo")) || IsMessage(NewBundle()) {
+		t.Error("IsMessage did not identify the message correctly")
+	}
+
+	if !IsBundle(NewBundle()) || IsBundle(NewMessage("/foo")) {
+		t.Error("IsBundle did not identify the bundle correctly")
+	}
+}
@@ -0,0 +1,67 @@
+package codec
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// payloadlessTypeTags are the type tags of arguments which carry no data bytes.
+const payloadlessTypeTags = "TFNI"
+
+/*
+CheckPayloadless inspects an encoded message for the interoperability problems caused by the T, F, N and I type tags,
+which (unlike every OSC 1.0 type) carry no data bytes. It returns a warning for each problem found:
+
+  - The message uses payload-less arguments, which receivers implementing only OSC 1.0 may not decode.
+  - The message has 4 trailing bytes for each payload-less argument, suggesting the sender wrongly encoded a payload
+    for them.
+
+If the data is not an encoded message, no warnings are returned.
+*/
+func CheckPayloadless(data []byte) []string {
+	msg, err := NewLazyMessage(data)
+	if err != nil {
+		return nil
+	}
+
+	var tags []string
+	for _, r := range msg.typeTagString[1:] {
+		if strings.ContainsRune(payloadlessTypeTags, r) && !strings.ContainsRune(strings.Join(tags, ""), r) {
+			tags = append(tags, string(r))
+		}
+	}
+
+	count := countPayloadless(msg.typeTagString)
+	if count == 0 {
+		return nil
+	}
+
+	warnings := []string{
+		fmt.Sprintf("Message %s uses payload-less arguments (%s), which OSC 1.0 receivers may not support",
+			msg.Address, strings.Join(tags, ", ")),
+	}
+
+	buf := bytes.NewBuffer(msg.argData)
+	_, err = readArguments(msg.typeTagString, buf)
+	if err == nil && buf.Len() == 4*count {
+		warnings = append(warnings, fmt.Sprintf("Message %s has %d trailing bytes, as if its sender encoded data for "+
+			"its payload-less arguments", msg.Address, buf.Len()))
+	}
+
+	return warnings
+}
+
+/*
+countPayloadless returns the number of payload-less arguments in a type tag string.
+*/
+func countPayloadless(typeTagString string) int {
+	count := 0
+	for _, r := range typeTagString {
+		if strings.ContainsRune(payloadlessTypeTags, r) {
+			count++
+		}
+	}
+
+	return count
+}
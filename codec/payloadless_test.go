@@ -0,0 +1,50 @@
+package codec
+
+import (
+	"testing"
+)
+
+func TestCheckPayloadless(t *testing.T) {
+	msg := NewMessage("/mute")
+	msg.AddArgument(int32(1))
+	msg.AddArgument(true)
+	data, _ := msg.MarshalBinary()
+
+	expected1 := 1
+	if result1 := CheckPayloadless(data); len(result1) != expected1 {
+		t.Errorf("Got %v, expected %d warning", result1, expected1)
+	}
+
+	// A sender that wrongly encodes a 4-byte payload for the T argument
+	test2 := append(data, 0, 0, 0, 1)
+	expected2 := 2
+	if result2 := CheckPayloadless(test2); len(result2) != expected2 {
+		t.Errorf("Got %v, expected %d warnings", result2, expected2)
+	}
+
+	msg = NewMessage("/fader")
+	msg.AddArgument(float32(0.5))
+	data, _ = msg.MarshalBinary()
+
+	if result3 := CheckPayloadless(data); len(result3) != 0 {
+		t.Errorf("Got %v, expected no warnings", result3)
+	}
+}
+
+func TestUnmarshalStrictPayloadless(t *testing.T) {
+	msg := NewMessage("/mute")
+	msg.AddArgument(true)
+	data, _ := msg.MarshalBinary()
+
+	// A sender that wrongly encodes a 4-byte payload for the T argument
+	_, err := DecodePacketStrict(append(data, 0, 0, 0, 1))
+	if trailing, ok := err.(*TrailingDataError); !ok || !trailing.Payloadless {
+		t.Errorf("Got %v, expected a TrailingDataError for payload-less arguments", err)
+	}
+
+	// Other trailing data is not attributed to the payload-less arguments
+	err = msg.UnmarshalStrict(append(data, 0, 0, 0, 1, 0, 0, 0, 2))
+	if trailing, ok := err.(*TrailingDataError); !ok || trailing.Payloadless {
+		t.Errorf("Got %v, expected a TrailingDataError for other data", err)
+	}
+}
@@ -0,0 +1,58 @@
+package codec
+
+import (
+	"bytes"
+)
+
+/*
+ArgumentTypeTag returns the OSC type tag (e.g. "i" for an int32) of a Go value, or an error if the type is not
+supported. Arrays return their full bracketed type tags (e.g. "[ff]").
+*/
+func ArgumentTypeTag(arg interface{}) (string, error) {
+	return typeTag(arg)
+}
+
+/*
+EncodeArgument encodes a single OSC argument, as it would appear in the argument data of a message. Arguments with no
+payload (e.g. true, false and nil) encode to an empty slice.
+*/
+func EncodeArgument(arg interface{}) ([]byte, error) {
+	return encodeArgument(arg)
+}
+
+/*
+DecodeArgument decodes a single OSC argument of the type given by typeTag from the start of data, returning the value
+and the number of bytes consumed. Arrays cannot be decoded by a single type tag; use Message.UnmarshalBinary instead.
+*/
+func DecodeArgument(typeTag TypeTag, data []byte) (interface{}, int, error) {
+	buf := bytes.NewBuffer(data)
+
+	val, err := decodeArgument(rune(typeTag), buf)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return val, len(data) - buf.Len(), nil
+}
+
+/*
+EncodeString encodes a Go string as a null-terminated OSC string, padded to a multiple of 4 bytes.
+*/
+func EncodeString(s string) []byte {
+	return encodeString(s)
+}
+
+/*
+DecodeString decodes a padded OSC string (such as the address or type tag string of a message) from the start of data,
+returning the string and the number of bytes consumed.
+*/
+func DecodeString(data []byte) (string, int, error) {
+	buf := bytes.NewBuffer(data)
+
+	str, err := decodeString(buf)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return str, len(data) - buf.Len(), nil
+}
@@ -0,0 +1,52 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeArgument(t *testing.T) {
+	// Scan the first argument of a message without decoding the rest
+	data := []byte{'/', 'f', 'o', 'o', '\x00', '\x00', '\x00', '\x00', ',', 'i', 'i', 's', 'f', 'f', '\x00', '\x00', '\x00', '\x00', '\x03', '\xe8', '\xff', '\xff', '\xff', '\xff', '\x68', '\x65', '\x6c', '\x6c', '\x6f', '\x00', '\x00', '\x00', '\x3f', '\x9d', '\xf3', '\xb6', '\x40', '\xb5', '\xb2', '\x2d'}
+
+	address, n1, err1 := DecodeString(data)
+	if err1 != nil {
+		t.Fatal(err1)
+	} else if address != "/foo" || n1 != 8 {
+		t.Errorf("Got \"%s\" (%d bytes), expected \"/foo\" (8 bytes)", address, n1)
+	}
+
+	typeTags, n2, err2 := DecodeString(data[n1:])
+	if err2 != nil {
+		t.Fatal(err2)
+	} else if typeTags != ",iisff" || n2 != 8 {
+		t.Errorf("Got \"%s\" (%d bytes), expected \",iisff\" (8 bytes)", typeTags, n2)
+	}
+
+	value, n3, err3 := DecodeArgument(TypeTag(typeTags[1]), data[n1+n2:])
+	if err3 != nil {
+		t.Error(err3)
+	} else if value != int32(1000) || n3 != 4 {
+		t.Errorf("Got %v (%d bytes), expected 1000 (4 bytes)", value, n3)
+	}
+}
+
+func TestEncodeArgument(t *testing.T) {
+	expected1 := []byte{'\x43', '\xdc', '\x00', '\x00'}
+	result1, err1 := EncodeArgument(float32(440))
+
+	if err1 != nil {
+		t.Error(err1)
+	} else if !bytes.Equal(result1, expected1) {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	expected2 := "[if]"
+	result2, err2 := ArgumentTypeTag([]interface{}{int32(1), float32(2)})
+
+	if err2 != nil {
+		t.Error(err2)
+	} else if result2 != expected2 {
+		t.Errorf("Got \"%s\", expected \"%s\"", result2, expected2)
+	}
+}
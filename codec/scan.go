@@ -0,0 +1,121 @@
+package codec
+
+import (
+	"fmt"
+	"reflect"
+)
+
+/*
+Scan copies the arguments of the message into the exported fields of the struct pointed to by dst. Each field takes
+the argument at the position given by its "osc" struct tag (e.g. `osc:"2"`), or if it has none, the argument following
+that of the previous field (starting from the first argument). Fields of embedded structs are scanned as if they were
+fields of dst, and fields tagged `osc:"-"` are skipped. See NewMessageFromStruct for the other parts of the tag.
+
+An argument can be stored in a field of its own type, or of any numeric type if the argument is numeric (e.g. an int32
+in an int, or a float32 in a float64), so long as the value fits. An error is returned if an argument for a field
+which is not optional is missing, or an argument cannot be stored in its field; extra arguments are ignored.
+*/
+func (msg *Message) Scan(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Cannot scan into %T, expected a pointer to a struct", dst)
+	}
+
+	v = v.Elem()
+
+	fields, err := structFields(v.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		if f.optional && f.position >= msg.NumArguments() {
+			continue
+		}
+
+		arg, err := msg.argumentAt(f.position)
+		if err != nil {
+			return fmt.Errorf("Field %s: %v", f.name, err)
+		}
+
+		err = setArgument(v.FieldByIndex(f.index), arg)
+		if err != nil {
+			return fmt.Errorf("Field %s: argument %d of %s %v", f.name, f.position, msg.Address, err)
+		}
+	}
+
+	return nil
+}
+
+/*
+setArgument stores an argument in a field, converting it if necessary.
+*/
+func setArgument(field reflect.Value, arg interface{}) error {
+	if arg == nil {
+		return fmt.Errorf("is nil, cannot be stored in %s", field.Type())
+	}
+
+	v := reflect.ValueOf(arg)
+	if v.Type().AssignableTo(field.Type()) {
+		field.Set(v)
+		return nil
+	}
+
+	if v.Kind() == field.Kind() && (v.Kind() == reflect.String || v.Kind() == reflect.Bool) {
+		// e.g. a string in a field of a named string type
+		field.Set(v.Convert(field.Type()))
+		return nil
+	}
+
+	if !isNumeric(v.Kind()) || !isNumeric(field.Kind()) {
+		return fmt.Errorf("is %T, cannot be stored in %s", arg, field.Type())
+	}
+
+	var overflows bool
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			f := v.Float()
+			overflows = f != float64(int64(f)) || field.OverflowInt(int64(f))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			overflows = v.Uint() > 1<<63-1 || field.OverflowInt(int64(v.Uint()))
+		default:
+			overflows = field.OverflowInt(v.Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			f := v.Float()
+			overflows = f < 0 || f != float64(uint64(f)) || field.OverflowUint(uint64(f))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			overflows = field.OverflowUint(v.Uint())
+		default:
+			overflows = v.Int() < 0 || field.OverflowUint(uint64(v.Int()))
+		}
+	case reflect.Float32:
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			overflows = field.OverflowFloat(v.Float())
+		}
+	}
+
+	if overflows {
+		return fmt.Errorf("(%v) does not fit in %s", arg, field.Type())
+	}
+
+	field.Set(v.Convert(field.Type()))
+
+	return nil
+}
+
+func isNumeric(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
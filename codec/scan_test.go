@@ -0,0 +1,64 @@
+package codec
+
+import (
+	"testing"
+)
+
+func TestMessageScan(t *testing.T) {
+	msg := NewMessage("/ch/1/config")
+	msg.AddArgument(int32(3))
+	msg.AddArgument(float32(0.5))
+	msg.AddArgument("Vocals")
+	msg.AddArgument(true)
+
+	var result1 struct {
+		Channel int
+		Level   float64
+		Name    string
+		Muted   bool   `osc:"3"`
+		Colour  string `osc:"-"`
+		ignored int
+	}
+	err := msg.Scan(&result1)
+	if err != nil {
+		t.Fatal(err)
+	} else if result1.Channel != 3 || result1.Level != 0.5 || result1.Name != "Vocals" || !result1.Muted {
+		t.Errorf("Got %+v, expected {Channel:3 Level:0.5 Name:Vocals Muted:true}", result1)
+	}
+
+	var result2 struct {
+		Name  string `osc:"2"`
+		Muted bool
+	}
+	err = msg.Scan(&result2)
+	if err != nil || result2.Name != "Vocals" || !result2.Muted {
+		t.Errorf("Got %+v (%v), expected {Name:Vocals Muted:true}", result2, err)
+	}
+
+	var result3 struct {
+		Channel string
+	}
+	if err = msg.Scan(&result3); err == nil {
+		t.Errorf("Got %v, expected an error", err)
+	}
+
+	var result4 struct {
+		Channel int
+		Extra   int `osc:"4"`
+	}
+	if err = msg.Scan(&result4); err == nil {
+		t.Errorf("Got %v, expected an error", err)
+	}
+
+	var result5 struct {
+		Channel int8
+		Level   int
+	}
+	if err = msg.Scan(&result5); err == nil {
+		t.Errorf("Got %v, expected an error", err)
+	}
+
+	if err = msg.Scan(result1); err == nil {
+		t.Errorf("Got %v, expected an error", err)
+	}
+}
@@ -0,0 +1,95 @@
+package codec
+
+import (
+	"fmt"
+	"sort"
+)
+
+/*
+ScheduledMessage is a message from a bundle, with the time at which it should be processed.
+*/
+type ScheduledMessage struct {
+	// Time is the effective time tag of the innermost bundle containing the message
+	Time    TimeTag
+	Message *Message
+}
+
+/*
+Schedule flattens the bundle, and any bundles nested inside it, into a list of messages and the times at which they
+should be processed, sorted by time (with immediate messages first). Messages with equal times keep their order in the
+bundle. A nested bundle with an immediate time tag takes the time of its enclosing bundle.
+
+The OSC specification requires the time tag of a nested bundle to be no earlier than that of its enclosing bundle. If
+the bundle violates this, an error is returned describing the first violation.
+*/
+func (bun *Bundle) Schedule() ([]ScheduledMessage, error) {
+	var schedule []ScheduledMessage
+
+	err := bun.schedule(bun.TimeTag, &schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(schedule, func(i, j int) bool {
+		a, b := schedule[i].Time, schedule[j].Time
+		if a.Immediate || b.Immediate {
+			return a.Immediate && !b.Immediate
+		}
+		return a.time.Before(b.time)
+	})
+
+	return schedule, nil
+}
+
+/*
+schedule appends the messages of the bundle to schedule, given the effective time of the bundle.
+*/
+func (bun *Bundle) schedule(effective TimeTag, schedule *[]ScheduledMessage) error {
+	if bun == nil {
+		return nil
+	}
+
+	for _, e := range bun.Elements {
+		var err error
+
+		switch p := e.(type) {
+		case *Message:
+			if p != nil {
+				*schedule = append(*schedule, ScheduledMessage{Time: effective, Message: p})
+			}
+		case *LazyMessage:
+			var m *Message
+			m, err = p.Message()
+			if err == nil {
+				*schedule = append(*schedule, ScheduledMessage{Time: effective, Message: m})
+			}
+		case *Bundle:
+			err = checkNestedTimeTag(effective, p.TimeTag)
+			if err == nil {
+				inner := p.TimeTag
+				if inner.Immediate {
+					inner = effective
+				}
+				err = p.schedule(inner, schedule)
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+checkNestedTimeTag returns an error if the time tag of a nested bundle is earlier than the effective time tag of its
+enclosing bundle.
+*/
+func checkNestedTimeTag(outer, inner TimeTag) error {
+	if outer.Immediate || inner.Immediate || !inner.time.Before(outer.time) {
+		return nil
+	}
+
+	return fmt.Errorf("Nested bundle time tag %v is earlier than enclosing bundle time tag %v", inner, outer)
+}
@@ -0,0 +1,51 @@
+package codec
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBundleSchedule(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	inner := &Bundle{TimeTag: NewTimeTag(start.Add(time.Second))}
+	inner.AddPacket(NewMessage("/later"))
+	immediate := NewBundle()
+	immediate.AddPacket(NewMessage("/inherited"))
+
+	outer := &Bundle{TimeTag: NewTimeTag(start)}
+	outer.AddPacket(inner)
+	outer.AddPacket(NewMessage("/first"))
+	outer.AddPacket(immediate)
+
+	schedule, err := outer.Schedule()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		address string
+		time    time.Time
+	}{
+		{"/first", start},
+		{"/inherited", start},
+		{"/later", start.Add(time.Second)},
+	}
+
+	if len(schedule) != len(expected) {
+		t.Fatalf("Got %d scheduled messages, expected %d", len(schedule), len(expected))
+	}
+
+	for i, e := range expected {
+		if schedule[i].Message.Address != e.address || !schedule[i].Time.Time().Equal(e.time) {
+			t.Errorf("Got %s at %v, expected %s at %v", schedule[i].Message.Address, schedule[i].Time, e.address,
+				e.time)
+		}
+	}
+
+	// A nested bundle may not be earlier than its enclosing bundle
+	inner.TimeTag = NewTimeTag(start.Add(-time.Second))
+	if _, err := outer.Schedule(); err == nil {
+		t.Error("Expected an error for a nested bundle earlier than its enclosing bundle")
+	}
+}
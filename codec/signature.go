@@ -0,0 +1,96 @@
+package codec
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Signature describes the arguments an address takes, as a sequence of type tags (e.g. "ifs" for an int32, a float32 and
+a string). In a Signature, TypeTrue and TypeFalse both stand for a boolean argument of either value.
+*/
+type Signature []TypeTag
+
+/*
+ParseSignature parses a signature from a type tag string, with or without its leading comma.
+*/
+func ParseSignature(s string) (Signature, error) {
+	tags, err := ParseTypeTags(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return Signature(tags), nil
+}
+
+/*
+String returns the signature as a type tag string, without the leading comma.
+*/
+func (sig Signature) String() string {
+	var b strings.Builder
+	for _, tag := range sig {
+		b.WriteRune(rune(tag))
+	}
+
+	return b.String()
+}
+
+/*
+Validate returns an error describing the first of args that does not match the signature, or nil if they all match.
+*/
+func (sig Signature) Validate(args []interface{}) error {
+	expected := splitTypeTags(sig.String())
+
+	if len(args) != len(expected) {
+		return fmt.Errorf("Expected %d arguments (%s), got %d", len(expected), sig, len(args))
+	}
+
+	for i, arg := range args {
+		tag, err := typeTag(arg)
+		if err != nil {
+			return err
+		}
+
+		if normaliseBooleans(tag) != normaliseBooleans(expected[i]) {
+			return fmt.Errorf("Argument %d has type tag %s, expected %s", i, tag, expected[i])
+		}
+	}
+
+	return nil
+}
+
+/*
+Matches returns true if the arguments of msg match the signature.
+*/
+func (sig Signature) Matches(msg *Message) bool {
+	return msg != nil && sig.Validate(msg.Arguments) == nil
+}
+
+/*
+splitTypeTags splits a type tag string into the type tags of each top-level argument, so that an array is a single
+element (e.g. "i[ff]s" becomes "i", "[ff]", "s"). The brackets must be balanced.
+*/
+func splitTypeTags(s string) []string {
+	var elements []string
+	depth, start := 0, 0
+
+	for i, r := range s {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+
+		if depth == 0 {
+			elements = append(elements, s[start:i+1])
+			start = i + 1
+		}
+	}
+
+	return elements
+}
+
+func normaliseBooleans(tag string) string {
+	return strings.Replace(tag, "F", "T", -1)
+}
@@ -0,0 +1,37 @@
+package codec
+
+import (
+	"testing"
+)
+
+func TestSignature(t *testing.T) {
+	sig, err := ParseSignature(",i[ff]T")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected1 := "i[ff]T"
+	if result1 := sig.String(); result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	msg := NewMessage("/eq")
+	msg.AddArgument(int32(1))
+	msg.AddArgument([]interface{}{float32(100), float32(0.5)})
+	msg.AddArgument(false)
+
+	if !sig.Matches(msg) {
+		t.Errorf("Expected %v to match %s", msg, sig)
+	}
+
+	test2 := [][]interface{}{
+		{int32(1), []interface{}{float32(100)}, true},
+		{int32(1), []interface{}{float32(100), float32(0.5)}},
+		{"1", []interface{}{float32(100), float32(0.5)}, true},
+	}
+	for _, args := range test2 {
+		if err := sig.Validate(args); err == nil {
+			t.Errorf("Expected an error validating %v against %s", args, sig)
+		}
+	}
+}
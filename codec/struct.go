@@ -0,0 +1,218 @@
+package codec
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+structField is an exported field of a struct which holds a message argument, as described by its "osc" struct tag.
+*/
+type structField struct {
+	name     string
+	index    []int
+	position int
+	typeTag  TypeTag
+	optional bool
+}
+
+// The Go types of the arguments which a field can be encoded as, by type tag.
+var typeTagTypes = map[TypeTag]reflect.Type{
+	TypeInt32:   reflect.TypeOf(int32(0)),
+	TypeInt64:   reflect.TypeOf(int64(0)),
+	TypeFloat32: reflect.TypeOf(float32(0)),
+	TypeFloat64: reflect.TypeOf(float64(0)),
+	TypeString:  reflect.TypeOf(""),
+	TypeBlob:    reflect.TypeOf([]byte(nil)),
+	TypeChar:    reflect.TypeOf(Char(0)),
+	TypeTimeTag: reflect.TypeOf(TimeTag{}),
+	TypeRGBA:    reflect.TypeOf(RGBA{}),
+	TypeMIDI:    reflect.TypeOf(MIDIMessage{}),
+}
+
+/*
+structFields returns the fields of struct type t which hold message arguments, in declaration order. The fields of
+embedded structs without an "osc" tag are included in place of the embedded struct.
+
+The "osc" tag of a field has the form "position,type,omitempty", where each part is optional (e.g. `osc:"2"`, `osc:",f"`
+or `osc:"3,i,omitempty"`):
+
+	position   the index of the field's argument, which otherwise follows that of the previous field
+	type       the type tag of the argument the field is encoded as, one of "ihfdsbctrm"
+	omitempty  the field is optional: it is not encoded if it has its zero value, and is left unchanged by Scan if the
+	           message has no argument for it
+
+Fields tagged `osc:"-"` are skipped.
+*/
+func structFields(t reflect.Type) ([]structField, error) {
+	var fields []structField
+	next := 0
+
+	var walk func(t reflect.Type, index []int) error
+	walk = func(t reflect.Type, index []int) error {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			tag, tagged := field.Tag.Lookup("osc")
+			if tag == "-" {
+				continue
+			}
+
+			fieldIndex := append(append([]int(nil), index...), i)
+
+			if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct {
+				// The exported fields of embedded structs can be set, even if the struct type is unexported
+				err := walk(field.Type, fieldIndex)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			if field.PkgPath != "" {
+				// Unexported fields cannot be set
+				continue
+			}
+
+			f, err := parseStructTag(field.Name, tag)
+			if err != nil {
+				return err
+			}
+
+			if f.position < 0 {
+				f.position = next
+			}
+			next = f.position + 1
+
+			f.index = fieldIndex
+			fields = append(fields, f)
+		}
+
+		return nil
+	}
+
+	err := walk(t, nil)
+
+	return fields, err
+}
+
+/*
+parseStructTag parses the "osc" tag of a field. The position of the returned field is -1 if the tag does not give one.
+*/
+func parseStructTag(name string, tag string) (structField, error) {
+	f := structField{name: name, position: -1}
+
+	parts := strings.Split(tag, ",")
+	if parts[0] != "" {
+		position, err := strconv.Atoi(parts[0])
+		if err != nil || position < 0 {
+			return f, fmt.Errorf("Invalid osc tag \"%s\" on field %s", tag, name)
+		}
+		f.position = position
+	}
+
+	for _, option := range parts[1:] {
+		switch {
+		case option == "omitempty":
+			f.optional = true
+		case len(option) == 1 && typeTagTypes[TypeTag(option[0])] != nil:
+			f.typeTag = TypeTag(option[0])
+		default:
+			return f, fmt.Errorf("Invalid option \"%s\" in osc tag of field %s", option, name)
+		}
+	}
+
+	return f, nil
+}
+
+/*
+NewMessageFromStruct creates a message with the given address, whose arguments are the exported fields of the struct
+src (or a pointer to it), in the order given by their "osc" struct tags as described for Scan. The type part of a tag
+sets the type of the field's argument, e.g. an int field tagged `osc:",h"` is sent as an int64; otherwise integer fields
+are sent as int32s, and other fields as their own type, or converted as by AddArgument. Optional fields with their zero
+value are left out, without leaving a gap in the arguments.
+
+An error is returned if two fields have the same position, or a field cannot be converted to its argument type.
+*/
+func NewMessageFromStruct(address string, src interface{}) (*Message, error) {
+	v := reflect.ValueOf(src)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Cannot create a message from %T, expected a struct", src)
+	}
+
+	fields, err := structFields(v.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].position < fields[j].position })
+
+	msg := NewMessage(address)
+	for i, f := range fields {
+		if i > 0 && fields[i-1].position == f.position {
+			return nil, fmt.Errorf("Fields %s and %s both have argument %d", fields[i-1].name, f.name, f.position)
+		}
+
+		field := v.FieldByIndex(f.index)
+		if f.optional && reflect.DeepEqual(field.Interface(), reflect.Zero(field.Type()).Interface()) {
+			continue
+		}
+
+		arg, err := structArgument(field, f.typeTag)
+		if err != nil {
+			return nil, fmt.Errorf("Field %s %v", f.name, err)
+		}
+
+		err = msg.AddArgument(arg)
+		if err != nil {
+			return nil, fmt.Errorf("Field %s: %v", f.name, err)
+		}
+	}
+
+	return msg, nil
+}
+
+/*
+structArgument returns the value of a field as an argument, converted to the type of tag if it is not zero.
+*/
+func structArgument(field reflect.Value, tag TypeTag) (interface{}, error) {
+	value := field.Interface()
+
+	if tag == 0 {
+		if _, err := typeTag(value); err == nil {
+			return value, nil
+		}
+
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			tag = TypeInt32
+		case reflect.Float32:
+			tag = TypeFloat32
+		case reflect.Float64:
+			tag = TypeFloat64
+		case reflect.String:
+			tag = TypeString
+		case reflect.Bool:
+			return field.Bool(), nil
+		default:
+			// Leave other types to AddArgument, which may have a converter for them
+			return value, nil
+		}
+	}
+
+	arg := reflect.New(typeTagTypes[tag]).Elem()
+	err := setArgument(arg, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return arg.Interface(), nil
+}
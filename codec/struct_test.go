@@ -0,0 +1,80 @@
+package codec
+
+import (
+	"testing"
+)
+
+type channelName string
+
+type channelInfo struct {
+	Name  channelName
+	Color RGBA `osc:",r,omitempty"`
+}
+
+func TestNewMessageFromStruct(t *testing.T) {
+	config := struct {
+		Channel int
+		Level   float64 `osc:",f"`
+		channelInfo
+		Muted  bool   `osc:"5"`
+		Frames int64  `osc:"4,i"`
+		Notes  string `osc:"-"`
+	}{3, 0.5, channelInfo{Name: "Vocals"}, true, 250, "unused"}
+
+	msg, err := NewMessageFromStruct("/ch/1/config", &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result1, _ := msg.TypeTagString()
+	expected1 := ",ifsiT"
+	if result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	// Optional fields are included when set
+	config.Color = RGBA{R: 255, A: 255}
+	msg, _ = NewMessageFromStruct("/ch/1/config", config)
+	result2, _ := msg.TypeTagString()
+	expected2 := ",ifsriT"
+	if result2 != expected2 {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+
+	// Scanning the message back gives the same struct
+	var result3 struct {
+		Channel int
+		Level   float64
+		channelInfo
+		Frames int64 `osc:"4"`
+		Muted  bool
+	}
+	err = msg.Scan(&result3)
+	if err != nil {
+		t.Fatal(err)
+	} else if result3.Name != "Vocals" || result3.Color != config.Color || result3.Frames != 250 || !result3.Muted {
+		t.Errorf("Got %+v, expected %+v", result3, config)
+	}
+
+	_, err = NewMessageFromStruct("/overflow", struct {
+		Frames int64 `osc:",i"`
+	}{1 << 40})
+	if err == nil {
+		t.Errorf("Got %v, expected an error", err)
+	}
+
+	_, err = NewMessageFromStruct("/duplicate", struct {
+		A int `osc:"0"`
+		B int `osc:"0"`
+	}{})
+	if err == nil {
+		t.Errorf("Got %v, expected an error", err)
+	}
+
+	_, err = NewMessageFromStruct("/invalid", struct {
+		A int `osc:",x"`
+	}{})
+	if err == nil {
+		t.Errorf("Got %v, expected an error", err)
+	}
+}
@@ -0,0 +1,105 @@
+package codec
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+TypeTag is the type tag of a single OSC argument, as it appears in the type tag string of a message.
+*/
+type TypeTag rune
+
+// Type tags of the OSC argument types supported by this package.
+const (
+	TypeInt32      TypeTag = 'i'
+	TypeFloat32    TypeTag = 'f'
+	TypeString     TypeTag = 's'
+	TypeBlob       TypeTag = 'b'
+	TypeInt64      TypeTag = 'h'
+	TypeTimeTag    TypeTag = 't'
+	TypeFloat64    TypeTag = 'd'
+	TypeTrue       TypeTag = 'T'
+	TypeFalse      TypeTag = 'F'
+	TypeNil        TypeTag = 'N'
+	TypeChar       TypeTag = 'c'
+	TypeRGBA       TypeTag = 'r'
+	TypeMIDI       TypeTag = 'm'
+	TypeInfinitum  TypeTag = 'I'
+	TypeArrayStart TypeTag = '['
+	TypeArrayEnd   TypeTag = ']'
+)
+
+var typeTagNames = map[TypeTag]string{
+	TypeInt32:      "int32",
+	TypeFloat32:    "float32",
+	TypeString:     "string",
+	TypeBlob:       "blob",
+	TypeInt64:      "int64",
+	TypeTimeTag:    "timetag",
+	TypeFloat64:    "float64",
+	TypeTrue:       "true",
+	TypeFalse:      "false",
+	TypeNil:        "nil",
+	TypeChar:       "char",
+	TypeRGBA:       "rgba",
+	TypeMIDI:       "midi",
+	TypeInfinitum:  "infinitum",
+	TypeArrayStart: "array start",
+	TypeArrayEnd:   "array end",
+}
+
+/*
+IsValid returns true if the TypeTag is one supported by this package.
+*/
+func (t TypeTag) IsValid() bool {
+	_, ok := typeTagNames[t]
+	return ok
+}
+
+/*
+String returns the name of the type (e.g. "int32"), or the type tag itself if it is not valid.
+*/
+func (t TypeTag) String() string {
+	if name, ok := typeTagNames[t]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown type '%c'", rune(t))
+}
+
+/*
+ParseTypeTags parses a type tag string, with or without its leading comma, into its type tags. An error is returned if
+the string contains an unsupported type tag, or unbalanced array brackets.
+*/
+func ParseTypeTags(s string) ([]TypeTag, error) {
+	s = strings.TrimPrefix(s, ",")
+
+	tags := make([]TypeTag, 0, len(s))
+	depth := 0
+
+	for _, r := range s {
+		tag := TypeTag(r)
+		if !tag.IsValid() {
+			return nil, fmt.Errorf("Invalid type tag '%c' in \"%s\"", r, s)
+		}
+
+		switch tag {
+		case TypeArrayStart:
+			depth++
+		case TypeArrayEnd:
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("Unbalanced array brackets in \"%s\"", s)
+			}
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if depth != 0 {
+		return nil, fmt.Errorf("Unbalanced array brackets in \"%s\"", s)
+	}
+
+	return tags, nil
+}
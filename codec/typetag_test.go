@@ -0,0 +1,41 @@
+package codec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTypeTags(t *testing.T) {
+	test1 := ",if[sT]"
+	expected1 := []TypeTag{TypeInt32, TypeFloat32, TypeArrayStart, TypeString, TypeTrue, TypeArrayEnd}
+	result1, err := ParseTypeTags(test1)
+
+	if err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(result1, expected1) {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	// The leading comma is optional
+	result2, err := ParseTypeTags("hd")
+	if err != nil || !reflect.DeepEqual(result2, []TypeTag{TypeInt64, TypeFloat64}) {
+		t.Errorf("Got %v (%v), expected [int64 float64]", result2, err)
+	}
+
+	for _, invalid := range []string{",iq", ",[i", ",i]"} {
+		if _, err := ParseTypeTags(invalid); err == nil {
+			t.Errorf("Expected an error parsing %s", invalid)
+		}
+	}
+}
+
+func TestTypeTagName(t *testing.T) {
+	expected1 := "float32"
+	if result1 := TypeFloat32.String(); result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	if TypeTag('q').IsValid() {
+		t.Error("Expected 'q' to be invalid")
+	}
+}
@@ -0,0 +1,113 @@
+package osc
+
+import (
+	"fmt"
+	"math"
+)
+
+/*
+compatOptions holds the compatibility conversions applied to packets sent by a client.
+*/
+type compatOptions struct {
+	downConvert bool
+}
+
+/*
+SetDownConvert enables or disables conversion of 64-bit arguments to their 32-bit equivalents ('h' to 'i', and 'd' to
+'f') before packets are sent, for receivers which only implement the OSC 1.0 core types and silently drop messages with
+other types. A packet with an integer outside the range of int32, or a finite float outside the range of float32, is
+not sent, and an error is returned.
+*/
+func (o *compatOptions) SetDownConvert(enable bool) {
+	o.downConvert = enable
+}
+
+/*
+marshal encodes a packet to be sent, applying the configured conversions.
+*/
+func (o *compatOptions) marshal(p Packet) ([]byte, error) {
+	if o.downConvert {
+		var err error
+		p, err = DownConvert(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p.MarshalBinary()
+}
+
+/*
+DownConvert returns a copy of a packet with its int64 arguments converted to int32, and its float64 arguments converted
+to float32, including those in arrays and nested bundles. An error is returned if an integer is outside the range of
+int32, or a finite float is outside the range of float32.
+*/
+func DownConvert(p Packet) (Packet, error) {
+	switch v := p.(type) {
+	case *Message:
+		args, err := downConvertArguments(v.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", v.Address, err)
+		}
+
+		converted := *v
+		converted.Arguments = args
+
+		return &converted, nil
+
+	case *rawBundle:
+		return DownConvert(v.Bundle)
+
+	case *Bundle:
+		converted := *v
+		converted.Elements = make([]Packet, len(v.Elements))
+
+		for i, e := range v.Elements {
+			var err error
+			converted.Elements[i], err = DownConvert(e)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return &converted, nil
+	}
+
+	return p, nil
+}
+
+func downConvertArguments(args []interface{}) ([]interface{}, error) {
+	if args == nil {
+		return nil, nil
+	}
+
+	converted := make([]interface{}, len(args))
+
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case int64:
+			if v < math.MinInt32 || v > math.MaxInt32 {
+				return nil, fmt.Errorf("Argument %d (%d) is out of range of int32", i, v)
+			}
+			converted[i] = int32(v)
+
+		case float64:
+			if math.Abs(v) > math.MaxFloat32 && !math.IsInf(v, 0) {
+				return nil, fmt.Errorf("Argument %d (%g) is out of range of float32", i, v)
+			}
+			converted[i] = float32(v)
+
+		case []interface{}:
+			elements, err := downConvertArguments(v)
+			if err != nil {
+				return nil, err
+			}
+			converted[i] = elements
+
+		default:
+			converted[i] = arg
+		}
+	}
+
+	return converted, nil
+}
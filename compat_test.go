@@ -0,0 +1,53 @@
+package osc
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDownConvert(t *testing.T) {
+	msg := NewMessage("/level")
+	msg.AddArgument(int64(42))
+	msg.AddArgument(0.5)
+	msg.AddArgument("name")
+
+	bundle := NewBundle()
+	bundle.AddPacket(msg)
+
+	result1, err := DownConvert(bundle)
+	expected1 := []interface{}{int32(42), float32(0.5), "name"}
+
+	if err != nil || !reflect.DeepEqual(result1.(*Bundle).Elements[0].(*Message).Arguments, expected1) {
+		t.Errorf("Got %v (%v), expected arguments %v", result1, err, expected1)
+	}
+
+	// The original packet is unchanged
+	if _, ok := msg.Arguments[0].(int64); !ok {
+		t.Errorf("Got %v, expected the original message to be unchanged", msg)
+	}
+
+	outOfRange := []interface{}{int64(math.MaxInt32 + 1), math.MaxFloat64}
+	for _, arg := range outOfRange {
+		m := NewMessage("/level")
+		m.AddArgument(arg)
+
+		if _, err := DownConvert(m); err == nil {
+			t.Errorf("Expected an error converting %v", arg)
+		}
+	}
+
+	var o compatOptions
+	o.SetDownConvert(true)
+
+	data, err := o.marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result2, _ := NewMessageFromData(data)
+	expected2 := ",ifs"
+	if typeTags, _ := result2.TypeTagString(); typeTags != expected2 {
+		t.Errorf("Got %v, expected %v", typeTags, expected2)
+	}
+}
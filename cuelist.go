@@ -0,0 +1,256 @@
+package osc
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+CueAction is a packet sent by a cue, after a delay from when the cue is fired.
+*/
+type CueAction struct {
+	Delay  time.Duration
+	Packet Packet
+}
+
+/*
+Cue is a named step of a CueList, sending packets when it is fired.
+*/
+type Cue struct {
+	Name    string
+	Actions []CueAction
+}
+
+/*
+CueList is a simple cue engine: an ordered list of cues with a playhead, in the manner of a lighting or sound console.
+Go fires the cue at the playhead and advances it, Jump moves it, and Pause and Resume hold and release the delayed
+actions of fired cues. Packets are sent with a Client. It is safe for concurrent use.
+*/
+type CueList struct {
+	client Client
+
+	mu       sync.Mutex
+	cues     []Cue
+	playhead int
+	clock    Clock
+	logger   Logger
+	pending  map[*pendingCueAction]struct{}
+	paused   bool
+}
+
+/*
+pendingCueAction is a delayed action of a fired cue, which has not been sent yet.
+*/
+type pendingCueAction struct {
+	packet    Packet
+	due       time.Time
+	remaining time.Duration
+	timer     Timer
+}
+
+/*
+NewCueList creates a CueList of cues sending with client, with the playhead at the first cue.
+*/
+func NewCueList(client Client, cues ...Cue) *CueList {
+	return &CueList{client: client, cues: cues, pending: make(map[*pendingCueAction]struct{})}
+}
+
+/*
+SetClock sets the Clock used to time delayed actions, e.g. a SimulatedClock in tests. By default, the SystemClock is
+used.
+*/
+func (l *CueList) SetClock(clock Clock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.clock = clock
+}
+
+/*
+SetLogger sets the Logger used to report errors sending delayed actions. By default, messages are logged to standard
+error.
+*/
+func (l *CueList) SetLogger(logger Logger) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.logger = logger
+}
+
+func (l *CueList) getClock() Clock {
+	if l.clock == nil {
+		return SystemClock
+	}
+
+	return l.clock
+}
+
+/*
+Go fires the cue at the playhead, and advances the playhead to the next cue. Actions without a delay are sent before
+Go returns, and the first error sending them is returned; the others are sent from another goroutine when they are
+due, and errors sending them are logged (see SetLogger). If the list is paused, it is resumed first. An error is returned if the
+playhead is past the last cue.
+*/
+func (l *CueList) Go() error {
+	l.mu.Lock()
+	if l.playhead >= len(l.cues) {
+		l.mu.Unlock()
+		return errors.New("No cue at the playhead")
+	}
+
+	if l.paused {
+		l.resume()
+	}
+
+	cue := l.cues[l.playhead]
+	l.playhead++
+
+	var immediate []Packet
+	for _, action := range cue.Actions {
+		if action.Delay <= 0 {
+			immediate = append(immediate, action.Packet)
+		} else {
+			l.schedule(&pendingCueAction{packet: action.Packet, remaining: action.Delay})
+		}
+	}
+	l.mu.Unlock()
+
+	var firstErr error
+	for _, p := range immediate {
+		err := l.client.Send(p)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+/*
+schedule starts the timer of a pending action to send it once its remaining delay has elapsed. The lock must be held.
+*/
+func (l *CueList) schedule(action *pendingCueAction) {
+	clock := l.getClock()
+
+	action.due = clock.Now().Add(action.remaining)
+	action.timer = clock.AfterFunc(action.remaining, func() {
+		l.mu.Lock()
+		_, pending := l.pending[action]
+		delete(l.pending, action)
+		logger := l.logger
+		l.mu.Unlock()
+
+		if !pending {
+			return
+		}
+
+		if logger == nil {
+			logger = defaultLogger
+		}
+
+		err := l.client.Send(action.packet)
+		if err != nil {
+			logger.Printf("Cannot send cue action: %v", err)
+		}
+	})
+
+	l.pending[action] = struct{}{}
+}
+
+/*
+Pause holds the delayed actions of the cues which have been fired, until Resume or Go is called.
+*/
+func (l *CueList) Pause() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.paused {
+		return
+	}
+
+	now := l.getClock().Now()
+	for action := range l.pending {
+		action.timer.Stop()
+		action.remaining = action.due.Sub(now)
+	}
+
+	l.paused = true
+}
+
+/*
+Resume releases the actions held by Pause, each to be sent after the delay it had remaining when paused.
+*/
+func (l *CueList) Resume() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.paused {
+		l.resume()
+	}
+}
+
+func (l *CueList) resume() {
+	held := l.pending
+	l.pending = make(map[*pendingCueAction]struct{}, len(held))
+
+	for action := range held {
+		l.schedule(action)
+	}
+
+	l.paused = false
+}
+
+/*
+Paused returns true if the list is paused.
+*/
+func (l *CueList) Paused() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.paused
+}
+
+/*
+Stop cancels the delayed actions of the cues which have been fired, without sending them. The playhead is unchanged.
+*/
+func (l *CueList) Stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for action := range l.pending {
+		action.timer.Stop()
+	}
+
+	l.pending = make(map[*pendingCueAction]struct{})
+	l.paused = false
+}
+
+/*
+Jump moves the playhead to the first cue with the given name, without firing it. An error is returned if there is no
+such cue.
+*/
+func (l *CueList) Jump(name string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, cue := range l.cues {
+		if cue.Name == name {
+			l.playhead = i
+			return nil
+		}
+	}
+
+	return fmt.Errorf("No cue named \"%s\"", name)
+}
+
+/*
+Playhead returns the index of the cue at the playhead, which is the number of cues if it is past the last cue.
+*/
+func (l *CueList) Playhead() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.playhead
+}
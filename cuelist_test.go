@@ -0,0 +1,108 @@
+package osc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCueList(t *testing.T) {
+	clock := NewSimulatedClock(time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC))
+	destination := &testClient{}
+
+	list := NewCueList(destination,
+		Cue{Name: "1", Actions: []CueAction{
+			{Packet: NewMessage("/lights/preset/1")},
+			{Delay: 2 * time.Second, Packet: NewMessage("/sound/play")},
+		}},
+		Cue{Name: "2", Actions: []CueAction{{Packet: NewMessage("/lights/preset/2")}}},
+		Cue{Name: "3", Actions: []CueAction{{Packet: NewMessage("/lights/blackout")}}},
+	)
+	list.SetClock(clock)
+
+	sent := func() []string {
+		var addresses []string
+		for _, p := range destination.sent {
+			addresses = append(addresses, p.(*Message).Address)
+		}
+		return addresses
+	}
+
+	err := list.Go()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Delayed actions are held while paused, and sent after the delay remaining when resumed
+	clock.Advance(time.Second)
+	list.Pause()
+	clock.Advance(5 * time.Second)
+
+	expected1 := []string{"/lights/preset/1"}
+	if result1 := sent(); !reflect.DeepEqual(result1, expected1) {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	list.Resume()
+	clock.Advance(time.Second)
+
+	expected2 := []string{"/lights/preset/1", "/sound/play"}
+	if result2 := sent(); !reflect.DeepEqual(result2, expected2) {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+
+	// Jumping moves the playhead without firing
+	err = list.Jump("3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list.Go()
+
+	expected3 := []string{"/lights/preset/1", "/sound/play", "/lights/blackout"}
+	if result3 := sent(); !reflect.DeepEqual(result3, expected3) {
+		t.Errorf("Got %v, expected %v", result3, expected3)
+	}
+
+	if err := list.Go(); err == nil {
+		t.Error("Expected an error firing past the last cue")
+	}
+
+	if err := list.Jump("4"); err == nil {
+		t.Error("Expected an error jumping to a missing cue")
+	}
+
+	// Stopping cancels delayed actions
+	list.Jump("1")
+	list.Go()
+	list.Stop()
+	clock.Advance(5 * time.Second)
+
+	if result4 := len(destination.sent); result4 != 4 {
+		t.Errorf("Got %d packets sent, expected 4", result4)
+	}
+}
+
+func TestCueListLogger(t *testing.T) {
+	clock := NewSimulatedClock(time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC))
+	logger := &testLogger{}
+
+	// The client is never connected, so sending fails
+	list := NewCueList(&UDPClient{}, Cue{Name: "1", Actions: []CueAction{
+		{Delay: time.Second, Packet: NewMessage("/sound/play")},
+	}})
+	list.SetClock(clock)
+	list.SetLogger(logger)
+
+	err := list.Go()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(time.Second)
+
+	result1 := len(logger.lines)
+	expected1 := 1
+	if result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+}
@@ -0,0 +1,131 @@
+package osc
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+/*
+Curve maps a control value from one range to another, e.g. a 0–1 fader position to a device's range. Curves can be used
+directly in application code, or applied to message arguments in Router transforms, with MapArgument or the "scale",
+"exp", "todb" and "togain" statements of ParseTransform.
+*/
+type Curve func(v float64) float64
+
+/*
+Linear returns a Curve mapping inMin–inMax linearly onto outMin–outMax. Values outside the input range are extrapolated;
+use Clamp to limit them.
+*/
+func Linear(inMin, inMax, outMin, outMax float64) Curve {
+	return func(v float64) float64 {
+		return outMin + (v-inMin)*(outMax-outMin)/(inMax-inMin)
+	}
+}
+
+/*
+Exponential returns a Curve mapping inMin–inMax onto outMin–outMax, with the normalised input raised to exponent. An
+exponent greater than 1 gives finer control at the bottom of the range (e.g. for frequencies or speeds).
+*/
+func Exponential(inMin, inMax, outMin, outMax, exponent float64) Curve {
+	return func(v float64) float64 {
+		normalised := (v - inMin) / (inMax - inMin)
+		if normalised < 0 {
+			normalised = 0
+		}
+
+		return outMin + math.Pow(normalised, exponent)*(outMax-outMin)
+	}
+}
+
+/*
+GainToDecibels returns a Curve converting a linear amplitude gain to decibels. Gains of 0 or less map to floor.
+*/
+func GainToDecibels(floor float64) Curve {
+	return func(v float64) float64 {
+		if v <= 0 {
+			return floor
+		}
+
+		return math.Max(20*math.Log10(v), floor)
+	}
+}
+
+/*
+DecibelsToGain returns a Curve converting decibels to a linear amplitude gain. Values at or below floor map to a gain of
+0.
+*/
+func DecibelsToGain(floor float64) Curve {
+	return func(v float64) float64 {
+		if v <= floor {
+			return 0
+		}
+
+		return math.Pow(10, v/20)
+	}
+}
+
+/*
+Table returns a Curve interpolating linearly between points given as pairs of input and output values, as in a lookup
+table describing a device's response. Inputs outside the table take the output of the nearest point. An error is
+returned if there are no points.
+*/
+func Table(points [][2]float64) (Curve, error) {
+	if len(points) == 0 {
+		return nil, errors.New("Table has no points")
+	}
+
+	sorted := append([][2]float64(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+
+	return func(v float64) float64 {
+		i := sort.Search(len(sorted), func(i int) bool { return sorted[i][0] >= v })
+		if i == 0 {
+			return sorted[0][1]
+		} else if i == len(sorted) {
+			return sorted[len(sorted)-1][1]
+		}
+
+		lo, hi := sorted[i-1], sorted[i]
+		return lo[1] + (v-lo[0])*(hi[1]-lo[1])/(hi[0]-lo[0])
+	}, nil
+}
+
+/*
+Clamp returns a Curve limiting the output of c to min–max.
+*/
+func (c Curve) Clamp(min, max float64) Curve {
+	return func(v float64) float64 {
+		return math.Min(math.Max(c(v), min), max)
+	}
+}
+
+/*
+Then returns a Curve applying c, followed by next.
+*/
+func (c Curve) Then(next Curve) Curve {
+	return func(v float64) float64 {
+		return next(c(v))
+	}
+}
+
+/*
+MapArgument returns a TransformFunc applying c to numeric argument i of a message. A float argument keeps its type; an
+integer argument is replaced by a float32, so that mapping e.g. a MIDI controller value onto a fader level is not
+truncated.
+*/
+func MapArgument(i int, c Curve) TransformFunc {
+	return func(m *Message) (*Message, error) {
+		if err := checkArgumentIndex(m, i); err != nil {
+			return nil, err
+		}
+
+		mapped, err := mapNumber(m.Arguments[i], c)
+		if err != nil {
+			return nil, err
+		}
+
+		m.Arguments[i] = mapped
+		return m, nil
+	}
+}
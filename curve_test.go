@@ -0,0 +1,71 @@
+package osc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCurves(t *testing.T) {
+	table, err := Table([][2]float64{{1, -10}, {0, -90}, {0.5, -30}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		curve    Curve
+		in       float64
+		expected float64
+	}{
+		{Linear(0, 127, 0, 1), 63.5, 0.5},
+		{Linear(0, 1, 0, 100).Clamp(0, 100), 1.5, 100},
+		{Exponential(0, 1, 20, 20020, 2), 0.5, 5020},
+		{GainToDecibels(-90), 0.1, -20},
+		{GainToDecibels(-90), 0, -90},
+		{DecibelsToGain(-90), 6.0206, 2},
+		{table, 0.75, -20},
+		{table, 2, -10},
+		{Linear(0, 1, 0, 2).Then(Linear(0, 2, 0, 10)), 0.5, 5},
+	}
+
+	for i, test := range tests {
+		if result := test.curve(test.in); math.Abs(result-test.expected) > 1e-4 {
+			t.Errorf("Curve %d: got %v, expected %v", i, result, test.expected)
+		}
+	}
+
+	if _, err := Table(nil); err == nil {
+		t.Error("Expected an error for an empty table")
+	}
+}
+
+func TestMapArgument(t *testing.T) {
+	msg := NewMessage("/gain")
+	msg.AddArgument(float32(1))
+
+	transform, _ := ParseTransform("todb 0 -90")
+	result, err := transform(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := NewMessage("/gain")
+	expected.AddArgument(float32(0))
+	if !result.Equals(expected) {
+		t.Errorf("Got %v, expected %v", result, expected)
+	}
+
+	// Integers are mapped without being truncated
+	msg = NewMessage("/cc/7")
+	msg.AddArgument(int32(63))
+
+	result, err = MapArgument(0, Linear(0, 127, 0, 1))(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = NewMessage("/cc/7")
+	expected.AddArgument(float32(63.0 / 127))
+	if !result.Equals(expected) {
+		t.Errorf("Got %v, expected %v", result, expected)
+	}
+}
@@ -0,0 +1,264 @@
+package osc
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// The number of recent messages shown by a Dashboard
+const dashboardRecentMessages = 50
+
+/*
+Dashboard is an http.Handler showing live statistics of an AddressSpace and its clients for debugging, in the manner of
+net/http/pprof: the packets and messages received, the registered methods, the most recently received messages, and
+the last value received at each address. It is intended to let operators inspect a bridge during rehearsals, e.g.
+
+	http.Handle("/debug/osc/", osc.NewDashboard(&server.AddressSpace))
+	go http.ListenAndServe("localhost:6060", nil)
+
+The page is served as HTML, or as JSON if the request has the query parameter format=json.
+*/
+type Dashboard struct {
+	space *AddressSpace
+
+	mu       sync.Mutex
+	started  time.Time
+	packets  uint64
+	bytes    uint64
+	messages uint64
+	recent   []DashboardMessage
+	next     int
+	values   map[string]DashboardMessage
+	clients  map[string]Client
+}
+
+/*
+DashboardMessage is a message received by the AddressSpace of a Dashboard.
+*/
+type DashboardMessage struct {
+	Time      time.Time     `json:"time"`
+	Peer      string        `json:"peer"`
+	Address   string        `json:"address"`
+	Arguments []interface{} `json:"arguments"`
+}
+
+/*
+DashboardClientStats are the statistics of a client added to a Dashboard.
+*/
+type DashboardClientStats struct {
+	Name        string    `json:"name"`
+	Healthy     bool      `json:"healthy"`
+	PacketsSent uint64    `json:"packetsSent"`
+	BytesSent   uint64    `json:"bytesSent"`
+	Errors      uint64    `json:"errors"`
+	LastError   string    `json:"lastError,omitempty"`
+	LastSend    time.Time `json:"lastSend"`
+}
+
+/*
+DashboardState is a snapshot of the information shown by a Dashboard.
+*/
+type DashboardState struct {
+	Started          time.Time              `json:"started"`
+	PacketsReceived  uint64                 `json:"packetsReceived"`
+	BytesReceived    uint64                 `json:"bytesReceived"`
+	MessagesReceived uint64                 `json:"messagesReceived"`
+	Unhandled        uint64                 `json:"unhandled"`
+	Methods          []string               `json:"methods"`
+	Clients          []DashboardClientStats `json:"clients"`
+	Recent           []DashboardMessage     `json:"recent"`
+	Values           []DashboardMessage     `json:"values"`
+}
+
+/*
+NewDashboard creates a Dashboard for an AddressSpace. It adds a packet hook to the AddressSpace to record the packets
+received by it, so only packets received after the Dashboard is created are shown.
+*/
+func NewDashboard(a *AddressSpace) *Dashboard {
+	d := &Dashboard{
+		space:   a,
+		started: time.Now(),
+		values:  make(map[string]DashboardMessage),
+		clients: make(map[string]Client),
+	}
+
+	a.AddPacketHook(d.record)
+
+	return d
+}
+
+/*
+AddClient adds a client to be shown by the Dashboard with its send statistics, under a name.
+*/
+func (d *Dashboard) AddClient(name string, c Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.clients[name] = c
+}
+
+/*
+record is the packet hook recording each packet received by the AddressSpace. It never consumes the packet.
+*/
+func (d *Dashboard) record(data []byte, peer *Peer) bool {
+	received := time.Now()
+	if peer != nil {
+		received = peer.ReceivedAt
+	}
+
+	var messages []*Message
+	p, err := decodeLenient(data)
+	if err == nil {
+		var find func(p Packet)
+		find = func(p Packet) {
+			Visit(p, func(m *Message) {
+				messages = append(messages, m)
+			}, func(b *Bundle) {
+				for _, e := range b.Elements {
+					find(e)
+				}
+			})
+		}
+		find(p)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.packets++
+	d.bytes += uint64(len(data))
+
+	for _, m := range messages {
+		dm := DashboardMessage{Time: received, Peer: peer.String(), Address: m.Address, Arguments: m.Arguments}
+
+		d.messages++
+		d.values[m.Address] = dm
+
+		if len(d.recent) < dashboardRecentMessages {
+			d.recent = append(d.recent, dm)
+		} else {
+			d.recent[d.next] = dm
+		}
+		d.next = (d.next + 1) % dashboardRecentMessages
+	}
+
+	return true
+}
+
+/*
+State returns a snapshot of the information shown by the Dashboard. Recent messages are ordered newest first, and last
+values by address.
+*/
+func (d *Dashboard) State() DashboardState {
+	d.mu.Lock()
+	s := DashboardState{
+		Started:          d.started,
+		PacketsReceived:  d.packets,
+		BytesReceived:    d.bytes,
+		MessagesReceived: d.messages,
+	}
+
+	for i := range d.recent {
+		s.Recent = append(s.Recent, d.recent[(d.next-1-i+2*len(d.recent))%len(d.recent)])
+	}
+
+	for _, v := range d.values {
+		s.Values = append(s.Values, v)
+	}
+
+	clients := make(map[string]Client, len(d.clients))
+	for name, c := range d.clients {
+		clients[name] = c
+	}
+	d.mu.Unlock()
+
+	sort.Slice(s.Values, func(i, j int) bool { return s.Values[i].Address < s.Values[j].Address })
+
+	s.Unhandled = d.space.UnhandledCount()
+
+	for _, m := range d.space.Methods() {
+		s.Methods = append(s.Methods, m.AddressPattern)
+	}
+
+	for name, c := range clients {
+		stats := c.Stats()
+		cs := DashboardClientStats{
+			Name:        name,
+			Healthy:     c.Healthy(),
+			PacketsSent: stats.PacketsSent,
+			BytesSent:   stats.BytesSent,
+			Errors:      stats.Errors,
+			LastSend:    stats.LastSend,
+		}
+		if stats.LastError != nil {
+			cs.LastError = stats.LastError.Error()
+		}
+
+		s.Clients = append(s.Clients, cs)
+	}
+
+	sort.Slice(s.Clients, func(i, j int) bool { return s.Clients[i].Name < s.Clients[j].Name })
+
+	return s
+}
+
+/*
+ServeHTTP implements the http.Handler interface.
+*/
+func (d *Dashboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s := d.State()
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(s)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	dashboardTemplate.Execute(w, s)
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta http-equiv="refresh" content="2">
+<title>OSC dashboard</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+td, th { border: 1px solid #ccc; padding: 2px 6px; text-align: left; font-family: monospace; }
+</style>
+</head>
+<body>
+<h1>OSC dashboard</h1>
+<p>Since {{.Started.Format "2006-01-02 15:04:05"}}: {{.PacketsReceived}} packets ({{.BytesReceived}} bytes) and {{.MessagesReceived}} messages received, of which {{.Unhandled}} unhandled.</p>
+
+<h2>Clients</h2>
+<table>
+<tr><th>Name</th><th>Healthy</th><th>Packets sent</th><th>Bytes sent</th><th>Errors</th><th>Last error</th></tr>
+{{range .Clients}}<tr><td>{{.Name}}</td><td>{{.Healthy}}</td><td>{{.PacketsSent}}</td><td>{{.BytesSent}}</td><td>{{.Errors}}</td><td>{{.LastError}}</td></tr>
+{{end}}</table>
+
+<h2>Last values</h2>
+<table>
+<tr><th>Address</th><th>Arguments</th><th>Peer</th><th>Time</th></tr>
+{{range .Values}}<tr><td>{{.Address}}</td><td>{{.Arguments}}</td><td>{{.Peer}}</td><td>{{.Time.Format "15:04:05.000"}}</td></tr>
+{{end}}</table>
+
+<h2>Recent messages</h2>
+<table>
+<tr><th>Time</th><th>Peer</th><th>Address</th><th>Arguments</th></tr>
+{{range .Recent}}<tr><td>{{.Time.Format "15:04:05.000"}}</td><td>{{.Peer}}</td><td>{{.Address}}</td><td>{{.Arguments}}</td></tr>
+{{end}}</table>
+
+<h2>Methods</h2>
+<table>
+{{range .Methods}}<tr><td>{{.}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,69 @@
+package osc
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDashboard(t *testing.T) {
+	var a AddressSpace
+	a.Handle("/fader/*", func(m *Message) {})
+
+	d := NewDashboard(&a)
+	d.AddClient("desk", &testClient{})
+
+	for _, level := range []float32{0.25, 0.5} {
+		msg := NewMessage("/fader/1")
+		msg.AddArgument(level)
+		data, _ := msg.MarshalBinary()
+		a.dispatchData(data, nil)
+	}
+
+	s := d.State()
+
+	test1 := []uint64{s.PacketsReceived, s.MessagesReceived}
+	expected1 := []uint64{2, 2}
+	if !reflect.DeepEqual(test1, expected1) {
+		t.Errorf("Got %v, expected %v", test1, expected1)
+	}
+
+	result2 := s.Recent[0].Arguments
+	expected2 := []interface{}{float32(0.5)}
+	if len(s.Recent) != 2 || !reflect.DeepEqual(result2, expected2) {
+		t.Errorf("Got %v, expected %v", s.Recent, expected2)
+	}
+
+	result3 := s.Values
+	if len(result3) != 1 || !reflect.DeepEqual(result3[0].Arguments, expected2) {
+		t.Errorf("Got %v, expected one value %v", result3, expected2)
+	}
+
+	result4 := s.Methods
+	expected4 := []string{"/fader/*"}
+	if !reflect.DeepEqual(result4, expected4) {
+		t.Errorf("Got %v, expected %v", result4, expected4)
+	}
+
+	if len(s.Clients) != 1 || s.Clients[0].Name != "desk" || !s.Clients[0].Healthy {
+		t.Errorf("Got %v, expected a healthy client named desk", s.Clients)
+	}
+
+	rec := httptest.NewRecorder()
+	d.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/osc/?format=json", nil))
+
+	var decoded DashboardState
+	err := json.Unmarshal(rec.Body.Bytes(), &decoded)
+	if err != nil || decoded.MessagesReceived != 2 {
+		t.Errorf("Got %v (%v), expected JSON state with 2 messages", rec.Body.String(), err)
+	}
+
+	rec = httptest.NewRecorder()
+	d.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/osc/", nil))
+
+	if !strings.Contains(rec.Body.String(), "/fader/1") {
+		t.Errorf("Got %v, expected the HTML page to show /fader/1", rec.Body.String())
+	}
+}
@@ -0,0 +1,91 @@
+package osc
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+Deduplicator drops byte-identical packets received from the same peer within a time window, such as the duplicate
+messages some controllers send when a button is released. It is attached to a server with
+AddPacketHook(dedup.PacketHook), and is safe for concurrent use.
+*/
+type Deduplicator struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	dropped   map[string]int
+	lastSweep time.Time
+}
+
+/*
+NewDeduplicator creates a Deduplicator which drops packets identical to one received from the same peer within window.
+*/
+func NewDeduplicator(window time.Duration) *Deduplicator {
+	return &Deduplicator{
+		window:  window,
+		seen:    make(map[string]time.Time),
+		dropped: make(map[string]int),
+	}
+}
+
+/*
+PacketHook consumes a received packet if it is a duplicate, and otherwise allows it to be dispatched. It can be passed
+to AddPacketHook.
+*/
+func (d *Deduplicator) PacketHook(data []byte, peer *Peer) bool {
+	now := time.Now()
+	key := peer.String() + "\x00" + string(data)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sweep(now)
+
+	if last, ok := d.seen[key]; ok && now.Sub(last) < d.window {
+		address := "#bundle"
+		if msg, err := NewLazyMessage(data); err == nil {
+			address = msg.Address
+		}
+		d.dropped[address]++
+
+		return false
+	}
+
+	d.seen[key] = now
+
+	return true
+}
+
+/*
+sweep forgets packets received longer ago than the window, at most once per window.
+*/
+func (d *Deduplicator) sweep(now time.Time) {
+	if now.Sub(d.lastSweep) < d.window {
+		return
+	}
+
+	for key, last := range d.seen {
+		if now.Sub(last) >= d.window {
+			delete(d.seen, key)
+		}
+	}
+
+	d.lastSweep = now
+}
+
+/*
+Dropped returns the number of duplicates dropped for each address. Dropped bundles are counted under "#bundle".
+*/
+func (d *Deduplicator) Dropped() map[string]int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dropped := make(map[string]int, len(d.dropped))
+	for address, n := range d.dropped {
+		dropped[address] = n
+	}
+
+	return dropped
+}
@@ -0,0 +1,42 @@
+package osc
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestDeduplicator(t *testing.T) {
+	dedup := NewDeduplicator(20 * time.Millisecond)
+
+	peer1 := newPeer(&net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 9000}, nil)
+	peer2 := newPeer(&net.UDPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 9000}, nil)
+
+	msg := NewMessage("/button/1")
+	msg.AddArgument(int32(1))
+	data, _ := msg.MarshalBinary()
+
+	expected := []bool{true, false, true}
+	result := []bool{
+		dedup.PacketHook(data, peer1),
+		dedup.PacketHook(data, peer1),
+		// The same message from another peer is not a duplicate
+		dedup.PacketHook(data, peer2),
+	}
+
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("Packet %d: got %v, expected %v", i, result[i], expected[i])
+		}
+	}
+
+	// Once the window has passed, the message is accepted again
+	time.Sleep(25 * time.Millisecond)
+	if !dedup.PacketHook(data, peer1) {
+		t.Error("Expected the message to be accepted after the window")
+	}
+
+	if dropped := dedup.Dropped()["/button/1"]; dropped != 1 {
+		t.Errorf("Got %d dropped, expected 1", dropped)
+	}
+}
@@ -0,0 +1,132 @@
+package osc
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+/*
+dialOptions holds the socket options applied to a client's connection.
+*/
+type dialOptions struct {
+	tos    int
+	tosSet bool
+}
+
+/*
+SetDSCP sets the Differentiated Services Code Point (0-63) carried in the IP header of outgoing packets, allowing
+managed switches to prioritise OSC control traffic (e.g. 46 for Expedited Forwarding). If the client is already
+connected, the new value takes effect on the next call to Connect.
+*/
+func (o *dialOptions) SetDSCP(dscp int) error {
+	if dscp < 0 || dscp > 63 {
+		return fmt.Errorf("DSCP value %d out of range (0-63)", dscp)
+	}
+
+	return o.SetTOS(dscp << 2)
+}
+
+/*
+SetTOS sets the raw IPv4 type of service byte (or IPv6 traffic class) of outgoing packets. Most users should prefer
+SetDSCP. If the client is already connected, the new value takes effect on the next call to Connect.
+*/
+func (o *dialOptions) SetTOS(tos int) error {
+	if tos < 0 || tos > 255 {
+		return fmt.Errorf("TOS value %d out of range (0-255)", tos)
+	}
+
+	o.tos = tos
+	o.tosSet = true
+
+	return nil
+}
+
+/*
+applyDialOptions sets the configured socket options on a newly connected socket.
+*/
+func (o *dialOptions) applyDialOptions(conn syscall.Conn, remote net.IP) error {
+	if !o.tosSet {
+		return nil
+	}
+
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	return setTrafficClass(rawConn, isIPv6(remote), o.tos)
+}
+
+/*
+isIPv6 returns true if ip is an IPv6 (and not IPv4-mapped) address.
+*/
+func isIPv6(ip net.IP) bool {
+	return ip != nil && ip.To4() == nil
+}
+
+/*
+multicastOptions holds the socket options applied when a UDP client sends to a multicast group.
+*/
+type multicastOptions struct {
+	ttl     int
+	ttlSet  bool
+	loop    bool
+	loopSet bool
+}
+
+/*
+SetMulticastTTL sets the time-to-live (IPv6 hop limit) of packets sent to a multicast group. A TTL of 1 keeps traffic
+on the local subnet, while larger values allow it to traverse routers. It takes effect on the next call to Connect, and
+is ignored for unicast destinations.
+*/
+func (o *multicastOptions) SetMulticastTTL(ttl int) error {
+	if ttl < 0 || ttl > 255 {
+		return fmt.Errorf("Multicast TTL %d out of range (0-255)", ttl)
+	}
+
+	o.ttl = ttl
+	o.ttlSet = true
+
+	return nil
+}
+
+/*
+SetMulticastLoopback sets whether packets sent to a multicast group are looped back to listeners on the sending host.
+It takes effect on the next call to Connect, and is ignored for unicast destinations.
+*/
+func (o *multicastOptions) SetMulticastLoopback(loop bool) {
+	o.loop = loop
+	o.loopSet = true
+}
+
+/*
+applyMulticastOptions sets the configured multicast options on a newly connected socket, if the remote address is a
+multicast group.
+*/
+func (o *multicastOptions) applyMulticastOptions(conn syscall.Conn, remote net.IP) error {
+	if !remote.IsMulticast() || (!o.ttlSet && !o.loopSet) {
+		return nil
+	}
+
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	if o.ttlSet {
+		err = setMulticastTTL(rawConn, isIPv6(remote), o.ttl)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.loopSet {
+		err = setMulticastLoopback(rawConn, isIPv6(remote), o.loop)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package osc
+
+import (
+	"testing"
+)
+
+func TestDSCPRange(t *testing.T) {
+	var o dialOptions
+
+	if err := o.SetDSCP(64); err == nil {
+		t.Error("Expected an error for a DSCP value out of range")
+	}
+
+	if err := o.SetTOS(-1); err == nil {
+		t.Error("Expected an error for a TOS value out of range")
+	}
+
+	err := o.SetDSCP(46)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result1 := o.tos
+	expected1 := 46 << 2
+	if result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+}
+
+func TestMulticastTTLRange(t *testing.T) {
+	var o multicastOptions
+
+	if err := o.SetMulticastTTL(256); err == nil {
+		t.Error("Expected an error for a multicast TTL out of range")
+	}
+}
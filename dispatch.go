@@ -0,0 +1,296 @@
+package osc
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+/*
+scheduler runs functions at later times, in order of their due time, and allows them to be cancelled together. Pending
+functions are kept in a heap, with a single timer for the earliest of them, so that scheduling many bundles does not
+create a timer for each of their messages.
+*/
+type scheduler struct {
+	mu      sync.Mutex
+	pending scheduledCalls
+	clock   Clock
+	timer   Timer
+	seq     uint64
+}
+
+/*
+scheduledCall is a function pending in a scheduler. Calls due at the same time are run in the order they were
+scheduled.
+*/
+type scheduledCall struct {
+	due time.Time
+	seq uint64
+	fn  func()
+}
+
+/*
+scheduledCalls is a min-heap of scheduled calls, implementing heap.Interface.
+*/
+type scheduledCalls []scheduledCall
+
+func (h scheduledCalls) Len() int {
+	return len(h)
+}
+
+func (h scheduledCalls) Less(i, j int) bool {
+	if h[i].due.Equal(h[j].due) {
+		return h[i].seq < h[j].seq
+	}
+
+	return h[i].due.Before(h[j].due)
+}
+
+func (h scheduledCalls) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *scheduledCalls) Push(x interface{}) {
+	*h = append(*h, x.(scheduledCall))
+}
+
+func (h *scheduledCalls) Pop() interface{} {
+	old := *h
+	call := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return call
+}
+
+/*
+after runs fn once d has elapsed according to clock, unless cancelled first.
+*/
+func (s *scheduler) after(clock Clock, d time.Duration, fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	call := scheduledCall{due: clock.Now().Add(d), seq: s.seq, fn: fn}
+	heap.Push(&s.pending, call)
+
+	// The timer only needs to be reset if the call is now the earliest, or the clock has changed
+	if s.timer == nil || s.pending[0].seq == call.seq || s.clock != clock {
+		s.clock = clock
+		s.arm()
+	}
+}
+
+/*
+arm sets the timer for the earliest pending call, replacing any current timer. The lock must be held.
+*/
+func (s *scheduler) arm() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+
+	if len(s.pending) == 0 {
+		return
+	}
+
+	s.timer = s.clock.AfterFunc(s.pending[0].due.Sub(s.clock.Now()), s.run)
+}
+
+/*
+run calls the pending functions which are due, then sets the timer for the next.
+*/
+func (s *scheduler) run() {
+	s.mu.Lock()
+	s.timer = nil
+
+	var due []scheduledCall
+	if s.clock != nil {
+		now := s.clock.Now()
+		for len(s.pending) > 0 && !s.pending[0].due.After(now) {
+			due = append(due, heap.Pop(&s.pending).(scheduledCall))
+		}
+	}
+	s.mu.Unlock()
+
+	for _, call := range due {
+		call.fn()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timer == nil {
+		s.arm()
+	}
+}
+
+/*
+cancel stops all functions which have not yet run.
+*/
+func (s *scheduler) cancel() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.pending = nil
+}
+
+/*
+DispatchData decodes an encoded packet and dispatches it with DispatchPacket, for programs which receive OSC data by
+means other than this package's servers (e.g. from files or message buses). An error is returned if the data cannot be
+decoded.
+*/
+func (a *AddressSpace) DispatchData(data []byte) error {
+	p, err := decodeLenient(data)
+	if err != nil {
+		return err
+	}
+
+	return a.DispatchPacket(p)
+}
+
+/*
+DispatchPacket dispatches a message, or the messages of a bundle (including those of nested bundles). The messages of
+a bundle are dispatched at the time given by its time tag: immediately if it is immediate or has passed, before
+DispatchPacket returns, or otherwise from another goroutine at that time. If the bundle is invalid (see
+Bundle.Schedule), none of its messages are dispatched, and an error is returned. Messages of a bundle which has expired
+(see NewExpiringBundle) are dropped.
+*/
+func (a *AddressSpace) DispatchPacket(p Packet) error {
+	return a.DispatchPacketFrom(p, nil)
+}
+
+/*
+DispatchPacketFrom is like DispatchPacket, but also records the peer that sent the packet, so that methods can reply to
+it. The peer may be nil if it is unknown.
+*/
+func (a *AddressSpace) DispatchPacketFrom(p Packet, peer *Peer) error {
+	var err error
+
+	Visit(p, func(m *Message) {
+		a.DispatchFrom(m, peer)
+	}, func(b *Bundle) {
+		err = a.dispatchBundle(b, peer)
+	})
+
+	return err
+}
+
+/*
+dispatchBundle dispatches the messages of a bundle at their scheduled times.
+*/
+func (a *AddressSpace) dispatchBundle(b *Bundle, peer *Peer) error {
+	if !a.runBundleHooks(b, peer) {
+		return nil
+	}
+
+	schedule, err := b.Schedule()
+	if err != nil {
+		return err
+	}
+
+	clock := a.getClock()
+
+	a.mu.RLock()
+	estimator := a.skew
+	a.mu.RUnlock()
+
+	var skew time.Duration
+	if estimator != nil {
+		skew = estimator.Skew(peer)
+	}
+
+	expires, expiring := bundleExpiry(schedule)
+
+	for _, sm := range schedule {
+		m := sm.Message
+		if isExpiryMarker(m) {
+			continue
+		}
+
+		dispatch := func() {
+			// Stale messages are dropped, comparing the expiry time with the peer's clock
+			if expiring && clock.Now().Add(skew).After(expires) {
+				return
+			}
+
+			a.DispatchFrom(m, peer)
+		}
+
+		if sm.Time.Immediate {
+			dispatch()
+		} else if wait := sm.Time.Time().Add(-skew).Sub(clock.Now()); wait <= 0 {
+			dispatch()
+		} else {
+			a.scheduled.after(clock, wait, dispatch)
+		}
+	}
+
+	return nil
+}
+
+/*
+SetClock sets the Clock used to schedule the messages of bundles, e.g. a SimulatedClock in tests. By default, the
+SystemClock is used.
+*/
+func (a *AddressSpace) SetClock(clock Clock) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.clock = clock
+}
+
+/*
+SkewEstimator estimates how far the clocks of peers are ahead of the local clock, so that bundles scheduled by a peer
+whose clock differs are executed at the intended local time.
+*/
+type SkewEstimator interface {
+	// Skew returns how far the peer's clock is ahead of the local clock (negative if it is behind). The peer is nil
+	// for bundles passed to DispatchPacket.
+	Skew(peer *Peer) time.Duration
+}
+
+/*
+SkewFunc is an adapter allowing an ordinary function to be used as a SkewEstimator.
+*/
+type SkewFunc func(peer *Peer) time.Duration
+
+/*
+Skew implements the SkewEstimator interface.
+*/
+func (f SkewFunc) Skew(peer *Peer) time.Duration {
+	return f(peer)
+}
+
+/*
+SetSkewEstimator sets the SkewEstimator used to compensate for the clock skew of the peers sending bundles: a bundle
+with a time tag of T from a peer whose clock is ahead by skew is executed at T - skew according to the local clock. If
+estimator is nil (the default), time tags are taken to be in local time.
+*/
+func (a *AddressSpace) SetSkewEstimator(estimator SkewEstimator) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.skew = estimator
+}
+
+func (a *AddressSpace) getClock() Clock {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.clock == nil {
+		return SystemClock
+	}
+
+	return a.clock
+}
+
+/*
+CancelScheduled cancels the dispatch of all bundled messages scheduled for a later time.
+*/
+func (a *AddressSpace) CancelScheduled() {
+	a.scheduled.cancel()
+}
@@ -0,0 +1,203 @@
+package osc
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDispatchPacket(t *testing.T) {
+	var a AddressSpace
+
+	var mu sync.Mutex
+	var received []string
+	done := make(chan struct{})
+
+	a.Handle("/*", func(m *Message) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		received = append(received, m.Address)
+		if m.Address == "/later" {
+			close(done)
+		}
+	})
+
+	later := &Bundle{TimeTag: NewTimeTag(time.Now().Add(20 * time.Millisecond))}
+	later.AddPacket(NewMessage("/later"))
+
+	bundle := NewBundle()
+	bundle.AddPacket(NewMessage("/now"))
+	bundle.AddPacket(later)
+	data, _ := bundle.MarshalBinary()
+
+	err := a.DispatchData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	if len(received) != 1 || received[0] != "/now" {
+		t.Errorf("Got %v before the scheduled time, expected [/now]", received)
+	}
+	mu.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Scheduled message was not dispatched")
+	}
+
+	// Cancelled messages are not dispatched
+	cancelled := &Bundle{TimeTag: NewTimeTag(time.Now().Add(10 * time.Millisecond))}
+	cancelled.AddPacket(NewMessage("/cancelled"))
+	a.DispatchPacket(cancelled)
+	a.CancelScheduled()
+	time.Sleep(30 * time.Millisecond)
+
+	mu.Lock()
+	if len(received) != 2 {
+		t.Errorf("Got %v, expected [/now /later]", received)
+	}
+	mu.Unlock()
+}
+
+func TestSimulatedClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewSimulatedClock(start)
+
+	var a AddressSpace
+	var received []string
+	a.SetClock(clock)
+	a.Handle("/*", func(m *Message) {
+		received = append(received, m.Address)
+	})
+
+	later := NewBundle()
+	later.TimeTag = NewTimeTag(start.Add(2 * time.Second))
+	later.AddPacket(NewMessage("/later"))
+
+	sooner := NewBundle()
+	sooner.TimeTag = NewTimeTag(start.Add(time.Second))
+	sooner.AddPacket(NewMessage("/sooner"))
+
+	a.DispatchPacket(later)
+	a.DispatchPacket(sooner)
+
+	if len(received) != 0 {
+		t.Fatalf("Got %v, expected no messages before the clock is advanced", received)
+	}
+
+	clock.Advance(1500 * time.Millisecond)
+	if len(received) != 1 || received[0] != "/sooner" {
+		t.Errorf("Got %v, expected [/sooner]", received)
+	}
+
+	clock.Advance(time.Second)
+	if len(received) != 2 || received[1] != "/later" {
+		t.Errorf("Got %v, expected [/sooner /later]", received)
+	}
+
+	if now := clock.Now(); !now.Equal(start.Add(2500 * time.Millisecond)) {
+		t.Errorf("Got %v, expected %v", now, start.Add(2500*time.Millisecond))
+	}
+}
+
+func TestScheduledOrder(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewSimulatedClock(start)
+
+	var a AddressSpace
+	var received []string
+	a.SetClock(clock)
+	a.Handle("/*", func(m *Message) {
+		received = append(received, m.Address)
+	})
+
+	// Bundles are dispatched in order of their time tags, whatever the order they arrive in
+	for i, address := range []string{"/3", "/1", "/4", "/2"} {
+		b := NewBundle()
+		b.TimeTag = NewTimeTag(start.Add(time.Duration(address[1]-'0') * time.Second))
+		b.AddPacket(NewMessage(address))
+		if i == 3 {
+			b.AddPacket(NewMessage("/2b"))
+		}
+		a.DispatchPacket(b)
+	}
+
+	clock.Advance(3 * time.Second)
+
+	expected1 := []string{"/1", "/2", "/2b", "/3"}
+	if !reflect.DeepEqual(received, expected1) {
+		t.Errorf("Got %v, expected %v", received, expected1)
+	}
+
+	clock.Advance(time.Second)
+
+	expected2 := append(expected1, "/4")
+	if !reflect.DeepEqual(received, expected2) {
+		t.Errorf("Got %v, expected %v", received, expected2)
+	}
+}
+
+func TestOnBundle(t *testing.T) {
+	var a AddressSpace
+	var received []string
+
+	a.Handle("/*", func(m *Message) {
+		received = append(received, m.Address)
+	})
+	a.OnBundle(func(b *Bundle, peer *Peer) bool {
+		received = append(received, "bundle")
+		return len(b.Elements) < 2
+	})
+
+	single := NewBundle()
+	single.AddPacket(NewMessage("/a"))
+
+	grouped := NewBundle()
+	grouped.AddPacket(NewMessage("/b"))
+	grouped.AddPacket(NewMessage("/c"))
+
+	a.DispatchPacket(single)
+	a.DispatchPacket(grouped)
+
+	expected := []string{"bundle", "/a", "bundle"}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("Got %v, expected %v", received, expected)
+	}
+}
+
+func TestSkewEstimator(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewSimulatedClock(start)
+
+	var a AddressSpace
+	var received []string
+	a.SetClock(clock)
+	a.Handle("/*", func(m *Message) {
+		received = append(received, m.Address)
+	})
+
+	// The peer's clock is 5 seconds ahead, so its "play in 1 second" is 6 seconds ahead of the local clock
+	a.SetSkewEstimator(SkewFunc(func(peer *Peer) time.Duration {
+		return 5 * time.Second
+	}))
+
+	bundle := NewBundle()
+	bundle.TimeTag = NewTimeTag(start.Add(6 * time.Second))
+	bundle.AddPacket(NewMessage("/play"))
+	a.DispatchPacket(bundle)
+
+	if len(received) != 0 {
+		t.Fatalf("Got %v, expected no messages before the clock is advanced", received)
+	}
+
+	clock.Advance(time.Second)
+
+	expected1 := []string{"/play"}
+	if !reflect.DeepEqual(received, expected1) {
+		t.Errorf("Got %v, expected %v", received, expected1)
+	}
+}
@@ -0,0 +1,25 @@
+package osc
+
+import (
+	"strings"
+)
+
+// EchoPrefix is the conventional address prefix of echoed messages.
+const EchoPrefix = "/echo"
+
+/*
+HandleEcho adds an OSC method to the AddressSpace which sends every message it receives back to its sender, as a
+diagnostic for verifying bidirectional connectivity (e.g. from a control surface). The echoed message has the address
+of the received message with prefix prepended, which may be empty to echo messages unchanged, or EchoPrefix. Messages
+whose address already starts with a non-empty prefix are not echoed, so that two echoing endpoints do not loop.
+*/
+func (a *AddressSpace) HandleEcho(prefix string) error {
+	return a.handlePeer("//*", func(m *Message, peer *Peer) {
+		if prefix != "" && (m.Address == prefix || strings.HasPrefix(m.Address, prefix+"/")) {
+			return
+		}
+
+		echo := &Message{Address: prefix + m.Address, Arguments: m.Arguments}
+		peer.Reply(echo)
+	})
+}
@@ -0,0 +1,282 @@
+package osc
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+/*
+Endpoint describes how to reach an OSC peer, or where to receive packets, independently of the transport, so that
+configuration-driven tools can construct the right client or server from a single setting, e.g.
+
+	endpoint, err := osc.ParseEndpointURL("tcp+slip://192.168.1.50:53000")
+	if err != nil {
+		return err
+	}
+
+	client, err := endpoint.Client()
+
+Endpoints implement encoding.TextMarshaler and encoding.TextUnmarshaler using their URL, so they can be used directly
+in JSON configuration and with flag.TextVar.
+
+Only UDP and TCP are supported: the package has no WebSocket transport, so "ws://" URLs are rejected, and the transport
+is always given by the scheme rather than detected from the peer.
+*/
+type Endpoint struct {
+	// Network is the transport, "udp" or "tcp"
+	Network string
+	// Host is the IP address or host name, which may be empty for a server listening on all interfaces
+	Host string
+	// Port is the port number
+	Port int
+	// Framing is the framing used over TCP, or nil for the default (LengthPrefix)
+	Framing Framing
+	// TTL is the time-to-live of packets sent by a UDP client to a multicast group, or 0 for the default
+	TTL int
+	// DSCP is the differentiated services code point of packets sent by a client, or 0 for the default
+	DSCP int
+	// ReusePort is true if a server allows other sockets to bind to the same port (see SetReusePort)
+	ReusePort bool
+}
+
+/*
+ParseEndpointURL parses the URL of an Endpoint. The scheme is the transport optionally followed by "+" and a framing:
+"udp", "tcp", "tcp+length" or "tcp+slip". Options may be given as query parameters:
+
+	framing    the framing, "length" or "slip", as an alternative to giving it in the scheme
+	ttl        the multicast TTL (see UDPClient.SetMulticastTTL)
+	dscp       the DSCP of outgoing packets (see UDPClient.SetDSCP)
+	reuseport  "true" to allow other sockets to bind to the port of a server (see UDPServer.SetReusePort)
+
+For example, "udp://0.0.0.0:8000", "tcp+slip://192.168.1.50:53000" or "udp://239.0.0.1:9000?ttl=4".
+*/
+func ParseEndpointURL(s string) (*Endpoint, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.User != nil || (u.Path != "" && u.Path != "/") || u.Fragment != "" {
+		return nil, fmt.Errorf("Endpoint URL \"%s\" may only have a scheme, host, port and options", s)
+	}
+
+	network, framingName := u.Scheme, ""
+	if i := strings.Index(network, "+"); i >= 0 {
+		network, framingName = network[:i], network[i+1:]
+	}
+
+	e := &Endpoint{Network: network, Host: u.Hostname()}
+
+	if network == "ws" || network == "wss" {
+		return nil, fmt.Errorf("WebSocket endpoints are not supported")
+	} else if network != "udp" && network != "tcp" {
+		return nil, fmt.Errorf("Unsupported endpoint scheme \"%s\"", u.Scheme)
+	}
+
+	e.Port, err = strconv.Atoi(u.Port())
+	if err != nil || e.Port < 0 || e.Port > 65535 {
+		return nil, fmt.Errorf("Invalid endpoint port \"%s\"", u.Port())
+	}
+
+	for key, values := range u.Query() {
+		value := values[len(values)-1]
+
+		switch key {
+		case "framing":
+			if framingName != "" && framingName != value {
+				return nil, fmt.Errorf("Endpoint URL \"%s\" has conflicting framings", s)
+			}
+			framingName = value
+		case "ttl":
+			e.TTL, err = strconv.Atoi(value)
+			if err != nil || e.TTL < 0 || e.TTL > 255 {
+				return nil, fmt.Errorf("Invalid endpoint TTL \"%s\"", value)
+			}
+		case "dscp":
+			e.DSCP, err = strconv.Atoi(value)
+			if err != nil || e.DSCP < 0 || e.DSCP > 63 {
+				return nil, fmt.Errorf("Invalid endpoint DSCP \"%s\"", value)
+			}
+		case "reuseport":
+			e.ReusePort, err = strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid endpoint reuseport \"%s\"", value)
+			}
+		default:
+			return nil, fmt.Errorf("Unknown endpoint option \"%s\"", key)
+		}
+	}
+
+	if network == "udp" && framingName != "" {
+		return nil, fmt.Errorf("Framing \"%s\" cannot be used over UDP", framingName)
+	}
+
+	e.Framing, err = framingByName(framingName)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+/*
+String returns the canonical URL of the endpoint, which ParseEndpointURL parses back into an equal Endpoint. The
+framing is given in the scheme, and options which are not their defaults as query parameters in alphabetical order. A
+framing other than LengthPrefix or SLIP cannot be represented, and is left out.
+*/
+func (e Endpoint) String() string {
+	scheme := e.Network
+	if name := framingName(e.Framing); name != "" {
+		scheme += "+" + name
+	}
+
+	query := url.Values{}
+	if e.TTL != 0 {
+		query.Set("ttl", strconv.Itoa(e.TTL))
+	}
+	if e.DSCP != 0 {
+		query.Set("dscp", strconv.Itoa(e.DSCP))
+	}
+	if e.ReusePort {
+		query.Set("reuseport", "true")
+	}
+
+	u := url.URL{Scheme: scheme, Host: net.JoinHostPort(e.Host, strconv.Itoa(e.Port)), RawQuery: query.Encode()}
+
+	return u.String()
+}
+
+/*
+MarshalText implements the encoding.TextMarshaler interface.
+*/
+func (e Endpoint) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+/*
+UnmarshalText implements the encoding.TextUnmarshaler interface.
+*/
+func (e *Endpoint) UnmarshalText(text []byte) error {
+	parsed, err := ParseEndpointURL(string(text))
+	if err != nil {
+		return err
+	}
+
+	*e = *parsed
+
+	return nil
+}
+
+/*
+framingByName returns the framing with the given name, "length" or "slip", or nil for an empty name.
+*/
+func framingByName(name string) (Framing, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "length":
+		return LengthPrefix, nil
+	case "slip":
+		return SLIP, nil
+	}
+
+	return nil, fmt.Errorf("Unknown framing \"%s\"", name)
+}
+
+/*
+framingName returns the name of a framing for framingByName, or an empty string if it has none.
+*/
+func framingName(framing Framing) string {
+	switch framing {
+	case LengthPrefix:
+		return "length"
+	case SLIP:
+		return "slip"
+	}
+
+	return ""
+}
+
+/*
+Client creates a client sending to the endpoint. It is not connected. An error is returned if the endpoint has options
+which do not apply to its network, e.g. a TTL for TCP.
+*/
+func (e *Endpoint) Client() (Client, error) {
+	if e.Host == "" {
+		return nil, fmt.Errorf("Endpoint %s has no host to send to", e)
+	}
+
+	switch e.Network {
+	case "udp":
+		client, err := NewUDPClient(e.Host, e.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		udp := client.(*UDPClient)
+		if e.TTL != 0 {
+			err = udp.SetMulticastTTL(e.TTL)
+		}
+		if err == nil && e.DSCP != 0 {
+			err = udp.SetDSCP(e.DSCP)
+		}
+
+		return client, err
+	case "tcp":
+		if e.TTL != 0 {
+			return nil, fmt.Errorf("Endpoint %s has a multicast TTL, which cannot be used over TCP", e)
+		}
+
+		client, err := NewTCPClient(e.Host, e.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		tcp := client.(*TCPClient)
+		if e.Framing != nil {
+			tcp.SetFraming(e.Framing)
+		}
+		if e.DSCP != 0 {
+			err = tcp.SetDSCP(e.DSCP)
+		}
+
+		return client, err
+	}
+
+	return nil, fmt.Errorf("Unsupported endpoint network \"%s\"", e.Network)
+}
+
+/*
+Server creates a server receiving at the endpoint. It is not listening.
+*/
+func (e *Endpoint) Server() (Server, error) {
+	switch e.Network {
+	case "udp":
+		server, err := NewUDPServer(e.Host, e.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		server.(*UDPServer).SetReusePort(e.ReusePort)
+
+		return server, nil
+	case "tcp":
+		server, err := NewTCPServer(e.Host, e.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		tcp := server.(*TCPServer)
+		if e.Framing != nil {
+			tcp.SetFraming(e.Framing)
+		}
+		tcp.SetReusePort(e.ReusePort)
+
+		return server, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported endpoint network \"%s\"", e.Network)
+}
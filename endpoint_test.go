@@ -0,0 +1,128 @@
+package osc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseEndpointURL(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected Endpoint
+	}{
+		{"udp://0.0.0.0:8000", Endpoint{Network: "udp", Host: "0.0.0.0", Port: 8000}},
+		{"udp://:9000", Endpoint{Network: "udp", Port: 9000}},
+		{"tcp://10.0.0.2:3032", Endpoint{Network: "tcp", Host: "10.0.0.2", Port: 3032}},
+		{"tcp+slip://[::1]:53000", Endpoint{Network: "tcp", Host: "::1", Port: 53000, Framing: SLIP}},
+		{"tcp+length://console:10023", Endpoint{Network: "tcp", Host: "console", Port: 10023, Framing: LengthPrefix}},
+		{"tcp://console:10023?framing=slip&dscp=46", Endpoint{Network: "tcp", Host: "console", Port: 10023, Framing: SLIP, DSCP: 46}},
+		{"udp://239.0.0.1:9000?ttl=4&reuseport=true", Endpoint{Network: "udp", Host: "239.0.0.1", Port: 9000, TTL: 4, ReusePort: true}},
+	}
+
+	for _, test := range tests {
+		result, err := ParseEndpointURL(test.address)
+		if err != nil {
+			t.Errorf("%s: %v", test.address, err)
+		} else if *result != test.expected {
+			t.Errorf("Got %+v, expected %+v", *result, test.expected)
+		}
+	}
+
+	for _, address := range []string{"ws://localhost:8080", "udp+slip://localhost:8000", "tcp+cobs://localhost:8000", "udp://localhost", "udp://localhost:70000",
+		"udp://localhost:8000?framing=slip", "tcp+slip://localhost:8000?framing=length", "udp://localhost:8000?ttl=300",
+		"udp://localhost:8000?colour=blue", "udp://localhost:8000/path"} {
+		if _, err := ParseEndpointURL(address); err == nil {
+			t.Errorf("%s: expected an error", address)
+		}
+	}
+}
+
+func TestEndpointClientServer(t *testing.T) {
+	endpoint, err := ParseEndpointURL("tcp+slip://127.0.0.1:53000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := endpoint.Client()
+	if err != nil {
+		t.Fatal(err)
+	} else if tcp, ok := client.(*TCPClient); !ok || tcp.framingOrDefault(nil) != SLIP {
+		t.Errorf("Got %#v, expected a TCP client with SLIP framing", client)
+	}
+
+	server, err := endpoint.Server()
+	if err != nil {
+		t.Fatal(err)
+	} else if tcp, ok := server.(*TCPServer); !ok || tcp.framingOrDefault(nil) != SLIP {
+		t.Errorf("Got %#v, expected a TCP server with SLIP framing", server)
+	}
+
+	// A server may listen on all interfaces, but a client needs a host
+	endpoint, err = ParseEndpointURL("udp://:8000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := endpoint.Client(); err == nil {
+		t.Error("Expected an error creating a client without a host")
+	}
+
+	if server, err := endpoint.Server(); err != nil {
+		t.Fatal(err)
+	} else if _, ok := server.(*UDPServer); !ok {
+		t.Errorf("Got %#v, expected a UDP server", server)
+	}
+
+	// A multicast TTL cannot be applied to a TCP client
+	endpoint, err = ParseEndpointURL("tcp://127.0.0.1:53000?ttl=4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := endpoint.Client(); err == nil {
+		t.Error("Expected an error creating a TCP client with a TTL")
+	}
+}
+
+func TestEndpointString(t *testing.T) {
+	tests := []struct {
+		endpoint Endpoint
+		expected string
+	}{
+		{Endpoint{Network: "udp", Port: 8000}, "udp://:8000"},
+		{Endpoint{Network: "tcp", Host: "::1", Port: 53000, Framing: SLIP}, "tcp+slip://[::1]:53000"},
+		{Endpoint{Network: "udp", Host: "239.0.0.1", Port: 9000, TTL: 4, DSCP: 46}, "udp://239.0.0.1:9000?dscp=46&ttl=4"},
+	}
+
+	for _, test := range tests {
+		result := test.endpoint.String()
+		if result != test.expected {
+			t.Errorf("Got %v, expected %v", result, test.expected)
+		}
+
+		parsed, err := ParseEndpointURL(result)
+		if err != nil {
+			t.Error(err)
+		} else if *parsed != test.endpoint {
+			t.Errorf("Got %+v, expected %+v", *parsed, test.endpoint)
+		}
+	}
+
+	// Endpoints are stored in JSON as their URL
+	var config struct {
+		Console Endpoint `json:"console"`
+	}
+
+	err := json.Unmarshal([]byte(`{"console":"tcp://10.0.0.2:3032?framing=slip"}`), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(config)
+	expected1 := `{"console":"tcp+slip://10.0.0.2:3032"}`
+	if err != nil {
+		t.Fatal(err)
+	} else if string(data) != expected1 {
+		t.Errorf("Got %v, expected %v", string(data), expected1)
+	}
+}
@@ -0,0 +1,80 @@
+package osc
+
+import (
+	"time"
+)
+
+// ExpiresAddress is the address of the message in a bundle giving the time after which the bundle is stale.
+const ExpiresAddress = "/expires"
+
+/*
+NewExpiringBundle returns a bundle with an immediate time tag containing packets, which receivers drop if it arrives
+after expires, so that stale values (e.g. fader positions queued while a connection was down) are not applied. The
+expiry is sent by convention as a message to ExpiresAddress with a time tag argument, ahead of the packets; receivers
+which do not know the convention see it as an ordinary message.
+
+A receiving AddressSpace compares the expiry with its clock, corrected by its SkewEstimator, when each message of the
+bundle is due to be dispatched, and never dispatches the expiry message itself.
+*/
+func NewExpiringBundle(expires time.Time, packets ...Packet) *Bundle {
+	b := NewBundle()
+
+	expiry := NewMessage(ExpiresAddress)
+	expiry.AddArgument(NewTimeTag(expires))
+	b.AddPacket(expiry)
+
+	for _, p := range packets {
+		b.AddPacket(p)
+	}
+
+	return b
+}
+
+/*
+SendExpiring sends p in a bundle made by NewExpiringBundle, which is valid for the given duration from now. The expiry
+time is adjusted by the client's time tag offset, if it has one (see UDPClient.SetTimeTagOffset).
+*/
+func SendExpiring(client Client, p Packet, validity time.Duration) error {
+	return client.Send(NewExpiringBundle(offsetTime(client, time.Now().Add(validity)), p))
+}
+
+/*
+isExpiryMarker returns true if m gives the expiry of its bundle: a message to ExpiresAddress whose first argument is a
+time tag. Other messages to ExpiresAddress are dispatched normally.
+*/
+func isExpiryMarker(m *Message) bool {
+	if m.Address != ExpiresAddress || len(m.Arguments) == 0 {
+		return false
+	}
+
+	_, ok := m.Arguments[0].(TimeTag)
+
+	return ok
+}
+
+/*
+bundleExpiry returns the earliest expiry time given by the expiry messages in the schedule of a bundle, or false if it
+has none.
+*/
+func bundleExpiry(schedule []ScheduledMessage) (time.Time, bool) {
+	var expires time.Time
+	var expiring bool
+
+	for _, sm := range schedule {
+		if !isExpiryMarker(sm.Message) {
+			continue
+		}
+
+		tt := sm.Message.Arguments[0].(TimeTag)
+		if tt.Immediate {
+			continue
+		}
+
+		if t := tt.Time(); !expiring || t.Before(expires) {
+			expires = t
+			expiring = true
+		}
+	}
+
+	return expires, expiring
+}
@@ -0,0 +1,57 @@
+package osc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExpiringBundle(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewSimulatedClock(start)
+
+	var a AddressSpace
+	var received []string
+	a.SetClock(clock)
+	a.Handle("/*", func(m *Message) {
+		received = append(received, m.Address)
+	})
+
+	a.DispatchPacket(NewExpiringBundle(start.Add(time.Second), NewMessage("/fresh")))
+	a.DispatchPacket(NewExpiringBundle(start.Add(-time.Second), NewMessage("/stale")))
+
+	// A bundle scheduled for after its expiry is dropped when it is due
+	late := NewExpiringBundle(start.Add(time.Second), NewMessage("/late"))
+	late.TimeTag = NewTimeTag(start.Add(2 * time.Second))
+	a.DispatchPacket(late)
+	clock.Advance(2 * time.Second)
+
+	expected1 := []string{"/fresh"}
+	if !reflect.DeepEqual(received, expected1) {
+		t.Errorf("Got %v, expected %v", received, expected1)
+	}
+
+	// The expiry is compared with the peer's clock
+	a.SetSkewEstimator(SkewFunc(func(peer *Peer) time.Duration {
+		return -5 * time.Second
+	}))
+	received = nil
+
+	a.DispatchPacket(NewExpiringBundle(start, NewMessage("/behind")))
+
+	expected2 := []string{"/behind"}
+	if !reflect.DeepEqual(received, expected2) {
+		t.Errorf("Got %v, expected %v", received, expected2)
+	}
+
+	// Messages to the expiry address without a time tag are dispatched normally
+	received = nil
+	expires := NewMessage(ExpiresAddress)
+	expires.AddArgument("soon")
+	a.DispatchPacket(&Bundle{TimeTag: NewImmediateTimeTag(), Elements: []Packet{expires}})
+
+	expected3 := []string{ExpiresAddress}
+	if !reflect.DeepEqual(received, expected3) {
+		t.Errorf("Got %v, expected %v", received, expected3)
+	}
+}
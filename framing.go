@@ -0,0 +1,207 @@
+package osc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+/*
+Framing delimits OSC packets sent over a stream transport, such as TCP or a serial line.
+*/
+type Framing interface {
+	// ReadPacket reads the next packet from r.
+	ReadPacket(r io.Reader) ([]byte, error)
+	// WritePacket writes a packet to w.
+	WritePacket(w io.Writer, data []byte) error
+}
+
+var (
+	// LengthPrefix frames packets with their length, as specified by OSC 1.0. It is the default for TCP transports.
+	LengthPrefix Framing = LengthPrefixFraming{}
+	// SLIP frames packets with double-ended SLIP, as specified by OSC 1.1. It is the default for serial transports.
+	SLIP Framing = SLIPFraming{}
+)
+
+/*
+LengthPrefixFraming frames each packet with its length, as a 32-bit big-endian integer (OSC 1.0).
+*/
+type LengthPrefixFraming struct {
+	// MaxSize is the size in bytes of the largest packet accepted by ReadPacket, or 0 for a default of 1 MiB
+	MaxSize int
+}
+
+/*
+ReadPacket reads the next packet from r. An error is returned if the packet is larger than the maximum size.
+*/
+func (f LengthPrefixFraming) ReadPacket(r io.Reader) ([]byte, error) {
+	maxSize := f.MaxSize
+	if maxSize <= 0 {
+		maxSize = maxStreamPacketSize
+	}
+
+	return readLengthPrefixed(r, maxSize)
+}
+
+/*
+WritePacket writes a packet to w, prefixed with its length.
+*/
+func (f LengthPrefixFraming) WritePacket(w io.Writer, data []byte) error {
+	_, err := w.Write(encodeLengthPrefixed(data))
+
+	return err
+}
+
+/*
+SLIPFraming frames each packet with double-ended SLIP encoding (OSC 1.1).
+*/
+type SLIPFraming struct{}
+
+/*
+ReadPacket reads the next packet from r. For efficiency, r should implement io.ByteReader (as bufio.Reader does);
+otherwise it is read one byte at a time.
+*/
+func (f SLIPFraming) ReadPacket(r io.Reader) ([]byte, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = &singleByteReader{r: r}
+	}
+
+	return readSLIP(br)
+}
+
+/*
+WritePacket writes a SLIP-encoded packet to w.
+*/
+func (f SLIPFraming) WritePacket(w io.Writer, data []byte) error {
+	_, err := w.Write(encodeSLIP(data))
+
+	return err
+}
+
+/*
+singleByteReader implements io.ByteReader for a reader without buffering, so that no data beyond a packet is consumed.
+*/
+type singleByteReader struct {
+	r   io.Reader
+	buf [1]byte
+}
+
+func (s *singleByteReader) ReadByte() (byte, error) {
+	_, err := io.ReadFull(s.r, s.buf[:])
+
+	return s.buf[0], err
+}
+
+/*
+limitedFraming wraps a Framing, refusing packets larger than limit bytes whatever the framing, so that a peer cannot
+exhaust memory by sending an endless packet.
+*/
+type limitedFraming struct {
+	Framing
+
+	limit int
+}
+
+/*
+ReadPacket reads the next packet from r, returning an error if it is larger than the limit.
+*/
+func (f limitedFraming) ReadPacket(r io.Reader) ([]byte, error) {
+	if lp, ok := f.Framing.(LengthPrefixFraming); ok && lp.MaxSize <= 0 {
+		// The length prefix is checked before the packet is read
+		lp.MaxSize = f.limit
+		return lp.ReadPacket(r)
+	}
+
+	// Allow for framing overhead, and for SLIP escaping doubling the size of a packet
+	data, err := f.Framing.ReadPacket(&limitedReader{r: r, remaining: 2*f.limit + 8})
+	if err == errReadLimit || (err == nil && len(data) > f.limit) {
+		return nil, fmt.Errorf("Packet exceeds limit of %d bytes", f.limit)
+	}
+
+	return data, err
+}
+
+var errReadLimit = errors.New("Read limit exceeded")
+
+/*
+limitedReader reads at most remaining bytes from a reader, reading single bytes efficiently if the reader implements
+io.ByteReader.
+*/
+type limitedReader struct {
+	r         io.Reader
+	remaining int
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		return 0, errReadLimit
+	}
+
+	if len(p) > l.remaining {
+		p = p[:l.remaining]
+	}
+
+	n, err := l.r.Read(p)
+	l.remaining -= n
+
+	return n, err
+}
+
+func (l *limitedReader) ReadByte() (byte, error) {
+	if l.remaining <= 0 {
+		return 0, errReadLimit
+	}
+
+	br, ok := l.r.(io.ByteReader)
+	if !ok {
+		br = &singleByteReader{r: l.r}
+	}
+
+	b, err := br.ReadByte()
+	if err == nil {
+		l.remaining--
+	}
+
+	return b, err
+}
+
+/*
+countingWriter counts the bytes written to an io.Writer, so that statistics include the framing.
+*/
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+
+	return n, err
+}
+
+/*
+framingOptions holds the framing used by a stream transport.
+*/
+type framingOptions struct {
+	framing Framing
+}
+
+/*
+SetFraming sets the framing used to delimit packets on the stream. It must be set before connecting or listening.
+*/
+func (o *framingOptions) SetFraming(framing Framing) {
+	o.framing = framing
+}
+
+/*
+framingOrDefault returns the framing that has been set, or def if there is none.
+*/
+func (o *framingOptions) framingOrDefault(def Framing) Framing {
+	if o.framing == nil {
+		return def
+	}
+
+	return o.framing
+}
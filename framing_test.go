@@ -0,0 +1,77 @@
+package osc
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestFraming(t *testing.T) {
+	packet := []byte{'/', 'a', '\x00', '\x00', '\xC0', '\xDB', '\x00', '\x00'}
+
+	for _, framing := range []Framing{LengthPrefix, SLIP} {
+		var buf bytes.Buffer
+		if err := framing.WritePacket(&buf, packet); err != nil {
+			t.Fatal(err)
+		}
+		if err := framing.WritePacket(&buf, packet); err != nil {
+			t.Fatal(err)
+		}
+
+		// Readers without buffering must not consume the following packet
+		reader := struct{ *bytes.Buffer }{&buf}
+		for i := 0; i < 2; i++ {
+			result, err := framing.ReadPacket(reader)
+			if err != nil {
+				t.Error(err)
+			} else if !bytes.Equal(result, packet) {
+				t.Errorf("Got %v, expected %v", result, packet)
+			}
+		}
+	}
+
+	// Packets over the maximum size are rejected
+	var buf bytes.Buffer
+	LengthPrefix.WritePacket(&buf, packet)
+	if _, err := (LengthPrefixFraming{MaxSize: 4}).ReadPacket(&buf); err == nil {
+		t.Error("Expected an error for an oversized packet")
+	}
+}
+
+func TestTCPFraming(t *testing.T) {
+	server := &TCPServer{}
+	server.SetLocalAddr("127.0.0.1", 0)
+	server.SetFraming(SLIP)
+
+	received := make(chan string, 1)
+	server.Handle("/framed", func(m *Message) {
+		received <- m.Address
+	})
+
+	if err := server.StartListening(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopListening()
+
+	addr := server.LocalAddr().(*net.TCPAddr)
+	client := &TCPClient{}
+	client.SetAddr("127.0.0.1", addr.Port)
+	client.SetFraming(SLIP)
+
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	client.Send(NewMessage("/framed"))
+
+	select {
+	case address := <-received:
+		if address != "/framed" {
+			t.Errorf("Got %s, expected /framed", address)
+		}
+	case <-time.After(time.Second):
+		t.Error("Message was not received")
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/dougfinl/go-osc
+
+go 1.20
@@ -0,0 +1,96 @@
+package osc
+
+import (
+	"sort"
+)
+
+/*
+HandleInGroup adds an OSC method to the AddressSpace as a member of a named handler group, which can be disabled and
+enabled as a whole at runtime, e.g. to ignore "editing" handlers while a show is running. Groups are enabled until
+disabled with DisableGroup. If the AddressPattern is of invalid format, an error is returned.
+*/
+func (a *AddressSpace) HandleInGroup(group string, addressPattern string, fn MessageHandleFunc, filters ...MessageFilter) error {
+	method := Method{
+		AddressPattern: addressPattern,
+		Function:       fn,
+		Filters:        filters,
+		Group:          group,
+	}
+
+	return a.addMethod(method)
+}
+
+/*
+DisableGroup disables the methods of a handler group: messages are dispatched as if they were not in the AddressSpace,
+until the group is enabled again. Messages already being handled are not affected.
+*/
+func (a *AddressSpace) DisableGroup(group string) {
+	a.setGroupEnabled(group, false)
+}
+
+/*
+EnableGroup enables the methods of a handler group disabled by DisableGroup.
+*/
+func (a *AddressSpace) EnableGroup(group string) {
+	a.setGroupEnabled(group, true)
+}
+
+func (a *AddressSpace) setGroupEnabled(group string, enabled bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.disabled[group] == !enabled {
+		return
+	}
+
+	// Copy the set, as messages being dispatched may hold the current one
+	disabled := make(map[string]bool, len(a.disabled)+1)
+	for g := range a.disabled {
+		disabled[g] = true
+	}
+
+	if enabled {
+		delete(disabled, group)
+	} else {
+		disabled[group] = true
+	}
+
+	a.disabled = disabled
+}
+
+/*
+GroupEnabled returns true if a handler group is enabled.
+*/
+func (a *AddressSpace) GroupEnabled(group string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return !a.disabled[group]
+}
+
+/*
+Groups returns the names of the handler groups with methods in the AddressSpace, and of any disabled groups, sorted by
+name.
+*/
+func (a *AddressSpace) Groups() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	names := make(map[string]bool)
+	for _, m := range a.methods {
+		if m.Group != "" {
+			names[m.Group] = true
+		}
+	}
+	for g := range a.disabled {
+		names[g] = true
+	}
+
+	groups := make([]string, 0, len(names))
+	for g := range names {
+		groups = append(groups, g)
+	}
+	sort.Strings(groups)
+
+	return groups
+}
@@ -0,0 +1,49 @@
+package osc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHandlerGroups(t *testing.T) {
+	var a AddressSpace
+	var received []string
+	record := func(m *Message) {
+		received = append(received, m.Address)
+	}
+
+	a.Handle("/go", record)
+	a.HandleInGroup("editing", "/cue/*/name", record)
+	a.HandleInGroup("editing", "/cue/*/delete", record)
+	a.HandleInGroup("playback", "/cue/*/start", record)
+
+	a.DisableGroup("editing")
+
+	a.Dispatch(NewMessage("/go"))
+	a.Dispatch(NewMessage("/cue/1/name"))
+	a.Dispatch(NewMessage("/cue/1/delete"))
+	a.Dispatch(NewMessage("/cue/1/start"))
+
+	expected1 := []string{"/go", "/cue/1/start"}
+	if !reflect.DeepEqual(received, expected1) {
+		t.Errorf("Got %v, expected %v", received, expected1)
+	}
+
+	if a.GroupEnabled("editing") || !a.GroupEnabled("playback") {
+		t.Errorf("Got editing %v and playback %v, expected only playback enabled", a.GroupEnabled("editing"), a.GroupEnabled("playback"))
+	}
+
+	a.EnableGroup("editing")
+	received = nil
+	a.Dispatch(NewMessage("/cue/1/name"))
+
+	expected2 := []string{"/cue/1/name"}
+	if !reflect.DeepEqual(received, expected2) {
+		t.Errorf("Got %v, expected %v", received, expected2)
+	}
+
+	expected3 := []string{"editing", "playback"}
+	if result3 := a.Groups(); !reflect.DeepEqual(result3, expected3) {
+		t.Errorf("Got %v, expected %v", result3, expected3)
+	}
+}
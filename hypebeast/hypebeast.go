@@ -0,0 +1,410 @@
+/*
+Package hypebeast provides adapter types mirroring the API of github.com/hypebeast/go-osc, implemented on top of this
+package. Existing projects can migrate by changing only their import:
+
+	import osc "github.com/dougfinl/go-osc/hypebeast"
+
+New code should use package osc directly.
+*/
+package hypebeast
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/dougfinl/go-osc"
+	"github.com/dougfinl/go-osc/codec"
+)
+
+/*
+Packet is an OSC packet: a *Message or a *Bundle.
+*/
+type Packet interface {
+	encoding.BinaryMarshaler
+}
+
+/*
+Message is an OSC message.
+*/
+type Message struct {
+	osc.Message
+}
+
+/*
+NewMessage returns a new Message with the given address and arguments.
+*/
+func NewMessage(addr string, args ...interface{}) *Message {
+	msg := &Message{Message: osc.Message{Address: addr}}
+	msg.Append(args...)
+
+	return msg
+}
+
+/*
+Append appends the given arguments to the arguments list.
+*/
+func (msg *Message) Append(args ...interface{}) {
+	for _, arg := range args {
+		msg.AddArgument(arg)
+	}
+}
+
+/*
+Equals returns true if the given OSC Message is equal to the current OSC Message.
+*/
+func (msg *Message) Equals(that *Message) bool {
+	return msg.Message.Equals(&that.Message)
+}
+
+/*
+Clear clears the OSC address and all arguments.
+*/
+func (msg *Message) Clear() {
+	msg.Address = ""
+	msg.ClearData()
+}
+
+/*
+ClearData removes all arguments from the OSC Message.
+*/
+func (msg *Message) ClearData() {
+	msg.Arguments = msg.Arguments[:0]
+}
+
+/*
+Match returns true if the address of the message, taken as a pattern, matches addr.
+*/
+func (msg *Message) Match(addr string) bool {
+	return codec.Match(msg.Address, addr)
+}
+
+/*
+TypeTags returns the type tag string of the message.
+*/
+func (msg *Message) TypeTags() (string, error) {
+	return msg.TypeTagString()
+}
+
+/*
+CountArguments returns the number of arguments.
+*/
+func (msg *Message) CountArguments() int {
+	return len(msg.Arguments)
+}
+
+/*
+Bundle is an OSC bundle, holding messages and further bundles to be processed at the time in its time tag.
+*/
+type Bundle struct {
+	Timetag  osc.TimeTag
+	Messages []*Message
+	Bundles  []*Bundle
+}
+
+/*
+NewBundle returns an OSC Bundle to be processed at time.
+*/
+func NewBundle(time time.Time) *Bundle {
+	return &Bundle{Timetag: osc.NewTimeTag(time)}
+}
+
+/*
+Append appends an OSC bundle or OSC message to the bundle.
+*/
+func (b *Bundle) Append(pck Packet) error {
+	switch p := pck.(type) {
+	case *Message:
+		b.Messages = append(b.Messages, p)
+	case *Bundle:
+		b.Bundles = append(b.Bundles, p)
+	default:
+		return fmt.Errorf("Unsupported OSC packet type: only Bundle and Message are supported")
+	}
+
+	return nil
+}
+
+/*
+MarshalBinary serializes the OSC bundle to a byte array.
+*/
+func (b *Bundle) MarshalBinary() ([]byte, error) {
+	return b.bundle().MarshalBinary()
+}
+
+/*
+bundle converts b to the equivalent osc.Bundle.
+*/
+func (b *Bundle) bundle() *osc.Bundle {
+	bundle := &osc.Bundle{TimeTag: b.Timetag}
+	for _, m := range b.Messages {
+		bundle.AddPacket(&m.Message)
+	}
+	for _, inner := range b.Bundles {
+		bundle.AddPacket(inner.bundle())
+	}
+
+	return bundle
+}
+
+/*
+fromPacket converts a decoded osc.Packet to the equivalent Packet.
+*/
+func fromPacket(p osc.Packet) Packet {
+	var result Packet
+
+	osc.Visit(p, func(m *osc.Message) {
+		result = &Message{Message: *m}
+	}, func(b *osc.Bundle) {
+		bundle := &Bundle{Timetag: b.TimeTag}
+		for _, e := range b.Elements {
+			switch inner := fromPacket(e).(type) {
+			case *Message:
+				bundle.Messages = append(bundle.Messages, inner)
+			case *Bundle:
+				bundle.Bundles = append(bundle.Bundles, inner)
+			}
+		}
+		result = bundle
+	})
+
+	return result
+}
+
+/*
+Handler handles OSC messages.
+*/
+type Handler interface {
+	HandleMessage(msg *Message)
+}
+
+/*
+HandlerFunc is an adapter allowing an ordinary function to be used as a Handler.
+*/
+type HandlerFunc func(msg *Message)
+
+/*
+HandleMessage calls f(msg).
+*/
+func (f HandlerFunc) HandleMessage(msg *Message) {
+	f(msg)
+}
+
+/*
+Dispatcher dispatches received OSC packets.
+*/
+type Dispatcher interface {
+	Dispatch(packet Packet)
+}
+
+/*
+StandardDispatcher dispatches messages to handlers by address, using an osc.AddressSpace. A handler added with the
+address "*" receives every message.
+*/
+type StandardDispatcher struct {
+	space osc.AddressSpace
+
+	mu       sync.RWMutex
+	catchAll []HandlerFunc
+}
+
+/*
+NewStandardDispatcher returns a StandardDispatcher.
+*/
+func NewStandardDispatcher() *StandardDispatcher {
+	return &StandardDispatcher{}
+}
+
+/*
+AddMsgHandler adds a handler for an address.
+*/
+func (s *StandardDispatcher) AddMsgHandler(addr string, handler HandlerFunc) error {
+	if addr == "*" {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.catchAll = append(s.catchAll, handler)
+		return nil
+	}
+
+	return s.space.Handle(addr, func(m *osc.Message) {
+		handler(&Message{Message: *m})
+	})
+}
+
+/*
+Dispatch dispatches a packet to the matching handlers. The messages of a bundle are dispatched when its time tag is
+reached.
+*/
+func (s *StandardDispatcher) Dispatch(packet Packet) {
+	switch p := packet.(type) {
+	case *Message:
+		s.space.Dispatch(&p.Message)
+
+		s.mu.RLock()
+		defaults := s.catchAll
+		s.mu.RUnlock()
+
+		for _, handler := range defaults {
+			handler(p)
+		}
+
+	case *Bundle:
+		go func() {
+			if !p.Timetag.Immediate {
+				time.Sleep(time.Until(p.Timetag.Time()))
+			}
+
+			for _, m := range p.Messages {
+				s.Dispatch(m)
+			}
+			for _, b := range p.Bundles {
+				s.Dispatch(b)
+			}
+		}()
+	}
+}
+
+/*
+Server receives OSC packets over UDP.
+*/
+type Server struct {
+	Addr        string
+	Dispatcher  Dispatcher
+	ReadTimeout time.Duration
+}
+
+/*
+ListenAndServe listens on the UDP address s.Addr and serves packets received on it.
+*/
+func (s *Server) ListenAndServe() error {
+	if s.Dispatcher == nil {
+		s.Dispatcher = NewStandardDispatcher()
+	}
+
+	ln, err := net.ListenPacket("udp", s.Addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(ln)
+}
+
+/*
+Serve reads packets from c and dispatches them, until a read fails.
+*/
+func (s *Server) Serve(c net.PacketConn) error {
+	if s.Dispatcher == nil {
+		return errors.New("No dispatcher defined")
+	}
+
+	for {
+		p, err := s.ReceivePacket(c)
+		if err != nil {
+			return err
+		}
+
+		if p != nil {
+			go s.Dispatcher.Dispatch(p)
+		}
+	}
+}
+
+/*
+ReceivePacket reads a single packet from c. If the data read is not a valid OSC packet, a nil Packet is returned.
+*/
+func (s *Server) ReceivePacket(c net.PacketConn) (Packet, error) {
+	if s.ReadTimeout != 0 {
+		err := c.SetReadDeadline(time.Now().Add(s.ReadTimeout))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data := make([]byte, 65535)
+	n, _, err := c.ReadFrom(data)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := codec.DecodePacket(data[:n])
+	if err != nil {
+		return nil, nil
+	}
+
+	return fromPacket(p), nil
+}
+
+/*
+Client sends OSC packets over UDP.
+*/
+type Client struct {
+	ip     string
+	port   int
+	client osc.UDPClient
+}
+
+/*
+NewClient returns a Client sending to the given host and port.
+*/
+func NewClient(ip string, port int) *Client {
+	return &Client{ip: ip, port: port}
+}
+
+/*
+IP returns the IP address that packets are sent to.
+*/
+func (c *Client) IP() string { return c.ip }
+
+/*
+SetIP sets the IP address that packets are sent to.
+*/
+func (c *Client) SetIP(ip string) { c.ip = ip }
+
+/*
+Port returns the port that packets are sent to.
+*/
+func (c *Client) Port() int { return c.port }
+
+/*
+SetPort sets the port that packets are sent to.
+*/
+func (c *Client) SetPort(port int) { c.port = port }
+
+/*
+SetLocalAddr sets the local address that packets are sent from.
+*/
+func (c *Client) SetLocalAddr(ip string, port int) error {
+	return c.client.SetLocalAddr(ip, port)
+}
+
+/*
+Send sends an OSC Bundle or an OSC Message.
+*/
+func (c *Client) Send(packet Packet) error {
+	var p osc.Packet
+	switch pck := packet.(type) {
+	case *Message:
+		p = &pck.Message
+	case *Bundle:
+		p = pck.bundle()
+	default:
+		return fmt.Errorf("Unsupported OSC packet type: only Bundle and Message are supported")
+	}
+
+	err := c.client.SetAddr(c.ip, c.port)
+	if err != nil {
+		return err
+	}
+
+	err = c.client.Connect()
+	if err != nil {
+		return err
+	}
+	defer c.client.Disconnect()
+
+	return c.client.Send(p)
+}
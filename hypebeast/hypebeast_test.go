@@ -0,0 +1,66 @@
+package hypebeast
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestMessage(t *testing.T) {
+	msg := NewMessage("/osc/address", int32(111), true, "hello")
+	msg.Append(float32(0.5))
+
+	expected1 := ",iTsf"
+	if result1, _ := msg.TypeTags(); result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	expected2 := 4
+	if result2 := msg.CountArguments(); result2 != expected2 {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+
+	msg.ClearData()
+	if msg.CountArguments() != 0 {
+		t.Errorf("Got %d arguments after ClearData, expected 0", msg.CountArguments())
+	}
+}
+
+func TestServerDispatch(t *testing.T) {
+	received := make(chan *Message, 2)
+
+	d := NewStandardDispatcher()
+	d.AddMsgHandler("/message/address", func(msg *Message) {
+		received <- msg
+	})
+	d.AddMsgHandler("*", func(msg *Message) {
+		received <- msg
+	})
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	server := &Server{Dispatcher: d}
+	go server.Serve(conn)
+
+	client := NewClient("127.0.0.1", conn.LocalAddr().(*net.UDPAddr).Port)
+	err = client.Send(NewMessage("/message/address", int32(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := NewMessage("/message/address", int32(42))
+	for i := 0; i < 2; i++ {
+		select {
+		case result := <-received:
+			if !result.Equals(expected) {
+				t.Errorf("Got %v, expected %v", result, expected)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Message was not dispatched")
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+)
+
+// LibraryVersion is the version of this library, reported by the method added by HandleIdentify.
+const LibraryVersion = "1.0.0"
+
+const (
+	// VersionAddress is the address of requests for the library version of a remote endpoint.
+	VersionAddress = "/version"
+	// IdentifyAddress is the address of requests for the identity of a remote endpoint.
+	IdentifyAddress = "/identify"
+
+	// replySuffix is appended to the address of a request to form the address of its reply.
+	replySuffix = "/reply"
+)
+
+/*
+Identity describes an application, as reported by the methods added by HandleIdentify.
+*/
+type Identity struct {
+	// Name is the name of the application
+	Name string
+	// Version is the version of this library used by the application
+	Version string
+	// Capabilities lists features supported by the application, in a form of its choosing (e.g. "ping", "tcp")
+	Capabilities []string
+}
+
+/*
+HandleIdentify adds OSC methods to the AddressSpace which answer the requests sent by QueryVersion and Identify, to
+help debug rigs made up of equipment from several vendors. A "/version" request is answered with LibraryVersion, and
+an "/identify" request with the application's name, LibraryVersion, and its capabilities. Replies are sent back to
+the sender, to the address of the request followed by "/reply".
+*/
+func (a *AddressSpace) HandleIdentify(name string, capabilities ...string) error {
+	err := a.handlePeer(VersionAddress, func(m *Message, peer *Peer) {
+		if len(m.Arguments) == 0 {
+			return
+		}
+
+		reply := NewMessage(VersionAddress + replySuffix)
+		reply.AddArgument(m.Arguments[0])
+		reply.AddArgument(LibraryVersion)
+
+		peer.Reply(reply)
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.handlePeer(IdentifyAddress, func(m *Message, peer *Peer) {
+		if len(m.Arguments) == 0 {
+			return
+		}
+
+		reply := NewMessage(IdentifyAddress + replySuffix)
+		reply.AddArgument(m.Arguments[0])
+		reply.AddArgument(name)
+		reply.AddArgument(LibraryVersion)
+		for _, capability := range capabilities {
+			reply.AddArgument(capability)
+		}
+
+		peer.Reply(reply)
+	})
+}
+
+/*
+QueryVersion requests the library version of a remote endpoint which answers version requests (see HandleIdentify).
+The request is sent using client, and the reply is received by the client's AddressSpace, as for Ping. QueryVersion
+returns when the reply is received, or with an error when ctx is done.
+*/
+func QueryVersion(ctx context.Context, client Client) (string, error) {
+	reply, err := query(ctx, client, VersionAddress)
+	if err != nil {
+		return "", err
+	}
+
+	version, ok := reply.Arguments[1].(string)
+	if !ok {
+		return "", fmt.Errorf("Malformed version reply")
+	}
+
+	return version, nil
+}
+
+/*
+Identify requests the identity of a remote endpoint which answers identity requests (see HandleIdentify). The request
+is sent using client, and the reply is received by the client's AddressSpace, as for Ping. Identify returns when the
+reply is received, or with an error when ctx is done.
+*/
+func Identify(ctx context.Context, client Client) (Identity, error) {
+	reply, err := query(ctx, client, IdentifyAddress)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	var strs []string
+	for _, arg := range reply.Arguments[1:] {
+		s, ok := arg.(string)
+		if !ok {
+			return Identity{}, fmt.Errorf("Malformed identity reply")
+		}
+		strs = append(strs, s)
+	}
+
+	if len(strs) < 2 {
+		return Identity{}, fmt.Errorf("Malformed identity reply")
+	}
+
+	return Identity{Name: strs[0], Version: strs[1], Capabilities: strs[2:]}, nil
+}
+
+/*
+query sends a request to address, followed by args, and waits for the reply with the same token at the address
+followed by "/reply". The reply has at least two arguments.
+*/
+func query(ctx context.Context, client Client, address string, args ...interface{}) (*Message, error) {
+	return request(ctx, client, address, address+replySuffix, 2, args...)
+}
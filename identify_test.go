@@ -0,0 +1,44 @@
+package osc
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIdentify(t *testing.T) {
+	responder, _ := NewUDPServer("127.0.0.1", 0)
+	responder.(*UDPServer).HandleIdentify("Lighting Bridge", "ping", "tcp")
+	err := responder.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer responder.StopListening()
+
+	client, _ := NewUDPClient("127.0.0.1", responder.LocalAddr().(*net.UDPAddr).Port)
+	err = client.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	version, err := QueryVersion(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	} else if version != LibraryVersion {
+		t.Errorf("Got %v, expected %v", version, LibraryVersion)
+	}
+
+	identity, err := Identify(ctx, client)
+	expected := Identity{Name: "Lighting Bridge", Version: LibraryVersion, Capabilities: []string{"ping", "tcp"}}
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(identity, expected) {
+		t.Errorf("Got %+v, expected %+v", identity, expected)
+	}
+}
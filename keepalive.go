@@ -0,0 +1,106 @@
+package osc
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+KeepAlive periodically sends a packet through a client while it is enabled, for protocols requiring subscriptions to be
+renewed. For example, a Behringer X32 only sends updates to clients that have sent /xremote within the last 10 seconds:
+
+	keepAlive := osc.NewKeepAlive(client, osc.NewMessage("/xremote"), 9*time.Second)
+	keepAlive.Start()
+
+Similarly, QLab needs /alwaysReply to be renewed to keep replying to a UDP client. Failed sends are recorded in the
+client's statistics, and do not stop the KeepAlive.
+*/
+type KeepAlive struct {
+	client   Client
+	packet   Packet
+	interval time.Duration
+	clock    Clock
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+/*
+NewKeepAlive creates a KeepAlive sending p through client every interval. It is initially disabled.
+*/
+func NewKeepAlive(client Client, p Packet, interval time.Duration) *KeepAlive {
+	return &KeepAlive{client: client, packet: p, interval: interval, clock: SystemClock}
+}
+
+/*
+SetClock sets the Clock used to time the sends, e.g. a SimulatedClock in tests. It must be called before Start.
+*/
+func (k *KeepAlive) SetClock(clock Clock) {
+	k.clock = clock
+}
+
+/*
+Start enables the KeepAlive, sending the packet immediately and then every interval. Starting a KeepAlive that is
+already enabled has no effect.
+*/
+func (k *KeepAlive) Start() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.stop != nil {
+		return
+	}
+
+	k.stop = make(chan struct{})
+	k.done = make(chan struct{})
+
+	go k.run(k.stop, k.done)
+}
+
+/*
+Stop disables the KeepAlive, waiting for any send in progress to complete.
+*/
+func (k *KeepAlive) Stop() {
+	k.mu.Lock()
+	stop, done := k.stop, k.done
+	k.stop, k.done = nil, nil
+	k.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+
+	close(stop)
+	<-done
+}
+
+/*
+Enabled returns true if the KeepAlive has been started and not stopped.
+*/
+func (k *KeepAlive) Enabled() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return k.stop != nil
+}
+
+func (k *KeepAlive) run(stop, done chan struct{}) {
+	defer close(done)
+
+	for {
+		k.client.Send(k.packet)
+
+		due := make(chan struct{})
+		timer := k.clock.AfterFunc(k.interval, func() {
+			close(due)
+		})
+
+		select {
+		case <-due:
+		case <-stop:
+			timer.Stop()
+			return
+		}
+	}
+}
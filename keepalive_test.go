@@ -0,0 +1,34 @@
+package osc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeepAlive(t *testing.T) {
+	client := &testClient{}
+	keepAlive := NewKeepAlive(client, NewMessage("/xremote"), 10*time.Millisecond)
+
+	keepAlive.Start()
+	keepAlive.Start()
+	if !keepAlive.Enabled() {
+		t.Error("Expected the keep-alive to be enabled")
+	}
+
+	time.Sleep(35 * time.Millisecond)
+	keepAlive.Stop()
+
+	if keepAlive.Enabled() {
+		t.Error("Expected the keep-alive to be disabled")
+	}
+
+	sent := len(client.sent)
+	if sent < 2 || sent > 5 {
+		t.Errorf("Got %d packets sent, expected about 4", sent)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if len(client.sent) != sent {
+		t.Error("Packets were sent after the keep-alive was stopped")
+	}
+}
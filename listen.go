@@ -0,0 +1,234 @@
+package osc
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"syscall"
+)
+
+/*
+IPFamily selects which IP versions a server listens on.
+*/
+type IPFamily int
+
+const (
+	// DualStack listens on both IPv4 and IPv6 where the operating system supports it
+	DualStack IPFamily = iota
+	// IPv4Only listens on IPv4 only
+	IPv4Only
+	// IPv6Only listens on IPv6 only
+	IPv6Only
+)
+
+/*
+listenState records when a server has started listening, and the address it is actually bound to.
+*/
+type listenState struct {
+	mu        sync.Mutex
+	ready     chan struct{}
+	boundAddr net.Addr
+}
+
+/*
+readyChan returns the channel that is closed once the server is listening, creating it if necessary.
+*/
+func (l *listenState) readyChan() chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.ready == nil {
+		l.ready = make(chan struct{})
+	}
+
+	return l.ready
+}
+
+/*
+setListening records the bound address and signals readiness to anyone waiting on the ready channel.
+*/
+func (l *listenState) setListening(addr net.Addr) {
+	ready := l.readyChan()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.boundAddr = addr
+
+	select {
+	case <-ready:
+		// Already signalled by a previous call to StartListening
+	default:
+		close(ready)
+	}
+}
+
+/*
+addr returns the bound address, or nil if the server is not yet listening.
+*/
+func (l *listenState) addr() net.Addr {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.boundAddr
+}
+
+/*
+listenOptions holds the socket options applied when a server starts listening.
+*/
+type listenOptions struct {
+	reuseAddr bool
+	reusePort bool
+	config    *net.ListenConfig
+	family    IPFamily
+	iface     string
+}
+
+/*
+SetReuseAddr enables the SO_REUSEADDR socket option, allowing a restarted server to bind to an address that is still
+in use by lingering sockets. It must be called before StartListening.
+*/
+func (o *listenOptions) SetReuseAddr(reuse bool) {
+	o.reuseAddr = reuse
+}
+
+/*
+SetReusePort enables the SO_REUSEPORT socket option, allowing multiple processes to listen on the same OSC port. It is
+only supported on Linux and the BSDs, and must be called before StartListening.
+*/
+func (o *listenOptions) SetReusePort(reuse bool) {
+	o.reusePort = reuse
+}
+
+/*
+SetListenConfig sets the net.ListenConfig used to create the server's socket. Any Control function it carries is run
+before the reuse options are applied. It must be called before StartListening.
+*/
+func (o *listenOptions) SetListenConfig(lc *net.ListenConfig) {
+	o.config = lc
+}
+
+/*
+SetIPFamily selects whether the server listens on IPv4, IPv6, or both (the default). It must be called before
+StartListening.
+*/
+func (o *listenOptions) SetIPFamily(family IPFamily) {
+	o.family = family
+}
+
+/*
+SetInterface binds the server to the address of the named network interface (e.g. "eth1"), keeping it off all other
+interfaces. The IP of the local address is ignored, but its port is kept. If the family is DualStack, the interface's
+IPv4 address is preferred. An empty name listens on the configured local address. It must be called before
+StartListening.
+*/
+func (o *listenOptions) SetInterface(name string) error {
+	if name != "" {
+		_, err := net.InterfaceByName(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	o.iface = name
+
+	return nil
+}
+
+/*
+network returns the network name (e.g. "udp4") for the base protocol ("udp" or "tcp") and the selected IP family.
+*/
+func (o *listenOptions) network(protocol string) string {
+	switch o.family {
+	case IPv4Only:
+		return protocol + "4"
+	case IPv6Only:
+		return protocol + "6"
+	default:
+		return protocol
+	}
+}
+
+/*
+bindAddress returns the address to listen on, substituting the IP of the selected interface if one is set.
+*/
+func (o *listenOptions) bindAddress(addr string) (string, error) {
+	if o.iface == "" {
+		return addr, nil
+	}
+
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+
+	iface, err := net.InterfaceByName(o.iface)
+	if err != nil {
+		return "", err
+	}
+
+	ifaceAddrs, err := iface.Addrs()
+	if err != nil {
+		return "", err
+	}
+
+	var ip4, ip6 net.IP
+	for _, a := range ifaceAddrs {
+		ipNet, ok := a.(*net.IPNet)
+		if !ok {
+			continue
+		}
+
+		if ipNet.IP.To4() != nil {
+			if ip4 == nil {
+				ip4 = ipNet.IP
+			}
+		} else if ip6 == nil {
+			ip6 = ipNet.IP
+		}
+	}
+
+	var host string
+	switch {
+	case ip4 != nil && o.family != IPv6Only:
+		host = ip4.String()
+	case ip6 != nil && o.family != IPv4Only:
+		host = ip6.String()
+		if ip6.IsLinkLocalUnicast() {
+			host += "%" + iface.Name
+		}
+	default:
+		return "", fmt.Errorf("Interface %s has no suitable address", o.iface)
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+/*
+listenConfig builds the net.ListenConfig used to bind the server's socket, combining any user-supplied config with the
+reuse options.
+*/
+func (o *listenOptions) listenConfig() *net.ListenConfig {
+	var lc net.ListenConfig
+	if o.config != nil {
+		lc = *o.config
+	}
+
+	if !o.reuseAddr && !o.reusePort {
+		return &lc
+	}
+
+	userControl := lc.Control
+	lc.Control = func(network, address string, c syscall.RawConn) error {
+		if userControl != nil {
+			err := userControl(network, address, c)
+			if err != nil {
+				return err
+			}
+		}
+
+		return setReuseOptions(c, o.reuseAddr, o.reusePort)
+	}
+
+	return &lc
+}
@@ -0,0 +1,129 @@
+package osc
+
+import (
+	"net"
+	"runtime"
+	"syscall"
+	"testing"
+)
+
+func TestListenConfig(t *testing.T) {
+	controlled := 0
+
+	server := &UDPServer{}
+	server.SetLocalAddr("127.0.0.1", 0)
+	server.SetReuseAddr(true)
+	server.SetListenConfig(&net.ListenConfig{
+		Control: func(network, address string, c syscall.RawConn) error {
+			controlled++
+			return nil
+		},
+	})
+
+	// The user's Control function is run alongside the reuse options
+	err := server.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopListening()
+
+	result1 := controlled
+	expected1 := 1
+	if result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+}
+
+func TestReusePort(t *testing.T) {
+	switch runtime.GOOS {
+	case "linux", "darwin", "dragonfly", "freebsd", "netbsd", "openbsd":
+	default:
+		t.Skip("SO_REUSEPORT is not supported on " + runtime.GOOS)
+	}
+
+	server1 := &UDPServer{}
+	server1.SetLocalAddr("127.0.0.1", 0)
+	server1.SetReusePort(true)
+	err := server1.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server1.StopListening()
+
+	// A second server may listen on the same port
+	server2 := &UDPServer{}
+	server2.SetLocalAddr("127.0.0.1", server1.LocalAddr().(*net.UDPAddr).Port)
+	server2.SetReusePort(true)
+	err = server2.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server2.StopListening()
+
+	// But not without the option
+	server3 := &UDPServer{}
+	server3.SetLocalAddr("127.0.0.1", server1.LocalAddr().(*net.UDPAddr).Port)
+	err = server3.StartListening()
+	if err == nil {
+		server3.StopListening()
+		t.Error("Expected an error listening on a port in use")
+	}
+}
+
+func TestIPFamily(t *testing.T) {
+	server := &UDPServer{}
+	server.SetLocalAddr("", 0)
+	server.SetIPFamily(IPv4Only)
+
+	err := server.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopListening()
+
+	if result1 := server.LocalAddr().(*net.UDPAddr).IP; result1.To4() == nil {
+		t.Errorf("Got %v, expected an IPv4 address", result1)
+	}
+}
+
+func TestInterface(t *testing.T) {
+	server := &TCPServer{}
+	server.SetLocalAddr("", 0)
+
+	if err := server.SetInterface("no-such-interface"); err == nil {
+		t.Error("Expected an error for an unknown interface")
+	}
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name := ""
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagLoopback != 0 && iface.Flags&net.FlagUp != 0 {
+			name = iface.Name
+			break
+		}
+	}
+	if name == "" {
+		t.Skip("No loopback interface")
+	}
+
+	// The server is bound to the interface's address, rather than all addresses
+	server.SetIPFamily(IPv4Only)
+	err = server.SetInterface(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = server.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopListening()
+
+	if result1 := server.LocalAddr().(*net.TCPAddr).IP; !result1.IsLoopback() {
+		t.Errorf("Got %v, expected a loopback address", result1)
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Package loadgen generates configurable OSC traffic, for benchmarking and soak-testing OSC receivers.
+
+	client, _ := osc.NewUDPClient("192.168.1.10", 8000)
+	client.Connect()
+
+	result, err := loadgen.Run(ctx, client, loadgen.Config{
+		Rate:      1000,
+		Duration:  time.Minute,
+		Addresses: loadgen.Addresses("/ch/%d/fader", 32),
+		Pattern:   loadgen.Zipf,
+	})
+*/
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/dougfinl/go-osc"
+)
+
+/*
+Pattern selects how addresses are chosen for generated messages.
+*/
+type Pattern int
+
+const (
+	// Uniform chooses each address with equal probability.
+	Uniform Pattern = iota
+	// Sequential cycles through the addresses in order.
+	Sequential
+	// Zipf chooses addresses with a long-tailed distribution, the first being the most frequent, like the few faders
+	// that are moved constantly during a show.
+	Zipf
+)
+
+/*
+Config describes the traffic to generate.
+*/
+type Config struct {
+	// Rate is the number of messages sent per second, or 0 to send as fast as possible
+	Rate float64
+	// Duration stops the run after the given time, or is 0 for no limit
+	Duration time.Duration
+	// Count stops the run after the given number of messages, or is 0 for no limit
+	Count int
+	// Addresses are the addresses that messages are sent to; "/loadgen" is used if there are none
+	Addresses []string
+	// Pattern selects how addresses are chosen
+	Pattern Pattern
+	// TypeTags are the type tags of the arguments of each message (e.g. "fi"), from i, h, f, d, s, b, T and F
+	TypeTags string
+	// PayloadSize is the length of string and blob arguments
+	PayloadSize int
+	// Seed seeds the random choice of addresses and argument values, so that runs can be repeated
+	Seed int64
+}
+
+/*
+Result summarises a run.
+*/
+type Result struct {
+	Sent    int
+	Errors  int
+	Bytes   int
+	Elapsed time.Duration
+}
+
+/*
+Rate returns the achieved number of messages sent per second.
+*/
+func (r Result) Rate() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+
+	return float64(r.Sent) / r.Elapsed.Seconds()
+}
+
+/*
+Addresses returns n addresses made by formatting format with the numbers 1 to n.
+*/
+func Addresses(format string, n int) []string {
+	addresses := make([]string, n)
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf(format, i+1)
+	}
+
+	return addresses
+}
+
+/*
+Run sends messages using client as described by cfg, until the configured duration or count is reached, or ctx is done.
+Send errors are counted in the result rather than stopping the run. An error is returned if cfg is invalid, or neither
+a duration, count nor cancellable context limits the run.
+*/
+func Run(ctx context.Context, client osc.Client, cfg Config) (Result, error) {
+	if cfg.Duration == 0 && cfg.Count == 0 && ctx.Done() == nil {
+		return Result{}, errors.New("Run has no duration, count or context to stop it")
+	}
+
+	gen, err := newGenerator(cfg)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	var interval time.Duration
+	if cfg.Rate > 0 {
+		interval = time.Duration(float64(time.Second) / cfg.Rate)
+	}
+
+	var result Result
+	start := time.Now()
+
+	for cfg.Count == 0 || result.Sent+result.Errors < cfg.Count {
+		if interval > 0 {
+			// Pace against the start time, so that slow sends do not reduce the overall rate
+			due := start.Add(time.Duration(result.Sent+result.Errors) * interval)
+			if wait := time.Until(due); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+				}
+			}
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		msg := gen.next()
+		data, _ := msg.MarshalBinary()
+
+		if err := client.Send(msg); err != nil {
+			result.Errors++
+		} else {
+			result.Sent++
+			result.Bytes += len(data)
+		}
+	}
+
+	result.Elapsed = time.Since(start)
+
+	return result, nil
+}
+
+/*
+generator creates the messages of a run.
+*/
+type generator struct {
+	cfg       Config
+	addresses []string
+	rand      *rand.Rand
+	zipf      *rand.Zipf
+	count     int
+	payload   string
+}
+
+func newGenerator(cfg Config) (*generator, error) {
+	for _, tag := range cfg.TypeTags {
+		if !strings.ContainsRune("ihfdsbTF", tag) {
+			return nil, fmt.Errorf("Unsupported type tag '%c'", tag)
+		}
+	}
+
+	addresses := cfg.Addresses
+	if len(addresses) == 0 {
+		addresses = []string{"/loadgen"}
+	}
+
+	g := &generator{
+		cfg:       cfg,
+		addresses: addresses,
+		rand:      rand.New(rand.NewSource(cfg.Seed)),
+		payload:   strings.Repeat("x", cfg.PayloadSize),
+	}
+
+	if cfg.Pattern == Zipf && len(addresses) > 1 {
+		g.zipf = rand.NewZipf(g.rand, 1.1, 1, uint64(len(addresses)-1))
+	}
+
+	return g, nil
+}
+
+func (g *generator) address() string {
+	defer func() { g.count++ }()
+
+	switch g.cfg.Pattern {
+	case Sequential:
+		return g.addresses[g.count%len(g.addresses)]
+	case Zipf:
+		if g.zipf != nil {
+			return g.addresses[g.zipf.Uint64()]
+		}
+		return g.addresses[0]
+	}
+
+	return g.addresses[g.rand.Intn(len(g.addresses))]
+}
+
+func (g *generator) next() *osc.Message {
+	msg := osc.NewMessage(g.address())
+
+	for _, tag := range g.cfg.TypeTags {
+		switch tag {
+		case 'i':
+			msg.AddArgument(g.rand.Int31())
+		case 'h':
+			msg.AddArgument(g.rand.Int63())
+		case 'f':
+			msg.AddArgument(g.rand.Float32())
+		case 'd':
+			msg.AddArgument(g.rand.Float64())
+		case 's':
+			msg.AddArgument(g.payload)
+		case 'b':
+			msg.AddArgument([]byte(g.payload))
+		case 'T':
+			msg.AddArgument(true)
+		case 'F':
+			msg.AddArgument(false)
+		}
+	}
+
+	return msg
+}
@@ -0,0 +1,68 @@
+package loadgen
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dougfinl/go-osc"
+)
+
+// countingClient is a Client that records the messages it is asked to send.
+type countingClient struct {
+	osc.UDPClient
+	sent []*osc.Message
+}
+
+func (c *countingClient) Send(p osc.Packet) error {
+	c.sent = append(c.sent, p.(*osc.Message))
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	client := &countingClient{}
+
+	result, err := Run(context.Background(), client, Config{
+		Count:       10,
+		Addresses:   Addresses("/ch/%d/fader", 4),
+		Pattern:     Sequential,
+		TypeTags:    "fs",
+		PayloadSize: 8,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Sent != 10 || len(client.sent) != 10 {
+		t.Fatalf("Got %d sent messages, expected 10", result.Sent)
+	}
+
+	expected1 := "/ch/2/fader"
+	if result1 := client.sent[5].Address; result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	expected2 := ",fs"
+	if result2, _ := client.sent[0].TypeTagString(); result2 != expected2 {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+}
+
+func TestRunRate(t *testing.T) {
+	client := &countingClient{}
+
+	result, err := Run(context.Background(), client, Config{Rate: 200, Duration: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Allow for scheduling jitter around the 20 messages expected
+	if result.Sent < 10 || result.Sent > 22 {
+		t.Errorf("Got %d sent messages, expected about 20", result.Sent)
+	}
+
+	_, err = Run(context.Background(), client, Config{})
+	if err == nil {
+		t.Error("Expected an error for an unlimited run")
+	}
+}
@@ -0,0 +1,23 @@
+package osc
+
+import (
+	"log"
+	"os"
+)
+
+/*
+Logger is the interface used to report diagnostic messages. It is satisfied by *log.Logger.
+*/
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// The logger used when none has been set
+var defaultLogger Logger = log.New(os.Stderr, "osc: ", log.LstdFlags)
+
+// loggerFunc adapts a function to the Logger interface.
+type loggerFunc func(format string, v ...interface{})
+
+func (f loggerFunc) Printf(format string, v ...interface{}) {
+	f(format, v...)
+}
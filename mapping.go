@@ -0,0 +1,150 @@
+package osc
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dougfinl/go-osc/codec"
+)
+
+/*
+MappingError describes an invalid row in a mapping table.
+*/
+type MappingError struct {
+	// Row is the line number of the row in the table, counting the header as row 1
+	Row int
+	Err error
+}
+
+func (e *MappingError) Error() string {
+	return fmt.Sprintf("Row %d: %v", e.Row, e.Err)
+}
+
+/*
+MappingErrors holds every invalid row found in a mapping table.
+*/
+type MappingErrors []*MappingError
+
+func (e MappingErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+/*
+LoadMappings reads a mapping table in CSV format (as exported from a spreadsheet), and adds a route to destination for
+each row. The first row is a header naming the columns, in any order:
+
+	pattern   the address pattern of messages to forward (required)
+	address   the address to forward messages to (optional)
+	scale     "inMin inMax outMin outMax" to map the first argument from one range to another (optional)
+	type      the type tag to convert the first argument to, after any scaling: i, h, f or d (optional; a scaled
+	          integer becomes a float32)
+	transform a transform expression applied after any scaling and conversion; see ParseTransform (optional)
+
+Other columns are ignored, and blank rows are skipped. The table is validated before any route is added; if any row is
+invalid, no routes are added and a MappingErrors listing every invalid row is returned.
+*/
+func (r *Router) LoadMappings(reader io.Reader, destination Client) error {
+	table := csv.NewReader(reader)
+	table.FieldsPerRecord = -1
+	table.TrimLeadingSpace = true
+
+	header, err := table.Read()
+	if err != nil {
+		return err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	if _, ok := columns["pattern"]; !ok {
+		return &MappingError{Row: 1, Err: fmt.Errorf("No \"pattern\" column")}
+	}
+
+	var routes []Route
+	var errs MappingErrors
+
+	for row := 2; ; row++ {
+		record, err := table.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		cell := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		if strings.Join(record, "") == "" {
+			continue
+		}
+
+		route, err := mappingRoute(cell("pattern"), cell("address"), cell("scale"), cell("type"), cell("transform"))
+		if err != nil {
+			errs = append(errs, &MappingError{Row: row, Err: err})
+			continue
+		}
+
+		route.Destination = destination
+		routes = append(routes, route)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	for _, route := range routes {
+		err := r.AddRoute(route)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+mappingRoute builds the route for a row of a mapping table.
+*/
+func mappingRoute(pattern, address, scale, typeTag, transform string) (Route, error) {
+	route := Route{AddressPattern: pattern, Address: address}
+
+	err := codec.ValidatePattern(pattern)
+	if err != nil {
+		return route, err
+	}
+
+	if address != "" && !strings.HasPrefix(address, "/") {
+		return route, fmt.Errorf("Address \"%s\" does not start with '/'", address)
+	}
+
+	var statements []string
+	if scale != "" {
+		statements = append(statements, "scale 0 "+scale)
+	}
+	if typeTag != "" {
+		statements = append(statements, "convert 0 "+typeTag)
+	}
+	if transform != "" {
+		statements = append(statements, transform)
+	}
+
+	if len(statements) > 0 {
+		route.Transform, err = ParseTransform(strings.Join(statements, ";"))
+	}
+
+	return route, err
+}
@@ -0,0 +1,95 @@
+package osc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadMappings(t *testing.T) {
+	table := `Pattern,Address,Scale,Type,Notes
+/cc/7,/ch/01/mix/fader,0 127 0 1,f,Lead vocal
+
+/cc/8,,,i,
+/cc/9,/ch/02/mix/fader,0 127 0 1,,
+/cc/10,/ch/03/mix/fader,0 1 0 127,i,
+`
+
+	destination := &testClient{}
+	router := NewRouter()
+
+	err := router.LoadMappings(strings.NewReader(table), destination)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := NewMessage("/cc/7")
+	msg.AddArgument(int32(127))
+	data, _ := msg.MarshalBinary()
+	router.Route(data)
+
+	msg = NewMessage("/cc/8")
+	msg.AddArgument(float32(2.6))
+	data, _ = msg.MarshalBinary()
+	router.Route(data)
+
+	// Mid-range integers are scaled without being truncated
+	msg = NewMessage("/cc/9")
+	msg.AddArgument(int32(63))
+	data, _ = msg.MarshalBinary()
+	router.Route(data)
+
+	msg = NewMessage("/cc/10")
+	msg.AddArgument(float32(0.5))
+	data, _ = msg.MarshalBinary()
+	router.Route(data)
+
+	expected1 := NewMessage("/ch/01/mix/fader")
+	expected1.AddArgument(float32(1))
+	expected2 := NewMessage("/cc/8")
+	expected2.AddArgument(int32(3))
+
+	expected3 := NewMessage("/ch/02/mix/fader")
+	expected3.AddArgument(float32(63.0 / 127))
+	expected4 := NewMessage("/ch/03/mix/fader")
+	expected4.AddArgument(int32(64))
+
+	if len(destination.sent) != 4 {
+		t.Fatalf("Got %d forwarded packets, expected 4", len(destination.sent))
+	}
+	if result1, ok := destination.sent[0].(*Message); !ok || !result1.Equals(expected1) {
+		t.Errorf("Got %v, expected %v", destination.sent[0], expected1)
+	}
+	if result2, ok := destination.sent[1].(*Message); !ok || !result2.Equals(expected2) {
+		t.Errorf("Got %v, expected %v", destination.sent[1], expected2)
+	}
+	if result3, ok := destination.sent[2].(*Message); !ok || !result3.Equals(expected3) {
+		t.Errorf("Got %v, expected %v", destination.sent[2], expected3)
+	}
+	if result4, ok := destination.sent[3].(*Message); !ok || !result4.Equals(expected4) {
+		t.Errorf("Got %v, expected %v", destination.sent[3], expected4)
+	}
+}
+
+func TestLoadMappingsErrors(t *testing.T) {
+	table := `pattern,address,scale
+/ok,/fine,
+cc/7,/ch/01,
+/cc/8,/ch/02,0 127
+`
+
+	router := NewRouter()
+	err := router.LoadMappings(strings.NewReader(table), &testClient{})
+
+	errs, ok := err.(MappingErrors)
+	if !ok {
+		t.Fatalf("Got %v, expected MappingErrors", err)
+	}
+
+	if len(errs) != 2 || errs[0].Row != 3 || errs[1].Row != 4 {
+		t.Errorf("Got %v, expected errors on rows 3 and 4", errs)
+	}
+
+	if len(router.Routes()) != 0 {
+		t.Errorf("Got %d routes, expected none from an invalid table", len(router.Routes()))
+	}
+}
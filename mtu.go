@@ -0,0 +1,191 @@
+package osc
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// The size of a bundle's header: "#bundle" and its time tag
+const bundleHeaderSize = 16
+
+// The sizes of the IP and UDP headers preceding the payload of a datagram
+const (
+	ipv4HeaderSize = 20
+	ipv6HeaderSize = 40
+	udpHeaderSize  = 8
+)
+
+/*
+SetMTUDiscovery enables or disables path MTU discovery for the client's destination (Linux only). When enabled, packets
+are sent with the don't-fragment flag so that the kernel learns the path MTU, and bundles too large for it are split
+into several bundles with the same time tag, so that large cue bundles reach devices behind links with a reduced MTU
+(e.g. VPNs or VLANs). A bundle split in this way is no longer executed atomically by the receiver. Messages too large
+for the path MTU cannot be split, and are not sent. If the client is already connected, the new value takes effect on
+the next call to Connect.
+*/
+func (c *UDPClient) SetMTUDiscovery(enable bool) {
+	c.discoverMTU = enable
+}
+
+/*
+PathMTU returns the path MTU to the client's destination currently known by the kernel. The client must be connected.
+*/
+func (c *UDPClient) PathMTU() (int, error) {
+	if !c.IsConnected() {
+		return 0, fmt.Errorf("Client is not connected")
+	}
+
+	rawConn, err := c.conn.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	return pathMTU(rawConn, isIPv6(c.addr.IP))
+}
+
+/*
+applyMTUDiscovery enables path MTU discovery on a newly connected socket, if configured.
+*/
+func (c *UDPClient) applyMTUDiscovery(conn syscall.Conn) error {
+	if !c.discoverMTU {
+		return nil
+	}
+
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	return setPathMTUDiscovery(rawConn, isIPv6(c.addr.IP))
+}
+
+/*
+maxDatagramSize returns the largest payload that can be sent to the client's destination without fragmentation.
+*/
+func (c *UDPClient) maxDatagramSize() (int, error) {
+	mtu, err := c.PathMTU()
+	if err != nil {
+		return 0, err
+	}
+
+	if isIPv6(c.addr.IP) {
+		return mtu - ipv6HeaderSize - udpHeaderSize, nil
+	}
+
+	return mtu - ipv4HeaderSize - udpHeaderSize, nil
+}
+
+/*
+writeWithinMTU writes an encoded packet, splitting it first if it is a bundle larger than the path MTU. If the kernel
+reports that the path MTU has shrunk since it was checked, the bundle is split and sent again.
+*/
+func (c *UDPClient) writeWithinMTU(p Packet, data []byte) (int, error) {
+	if raw, ok := p.(*rawBundle); ok {
+		p = raw.Bundle
+	}
+
+	b, isBundle := p.(*Bundle)
+	if !isBundle {
+		logWire(wireOut, c.addr, data)
+		return c.conn.Write(data)
+	}
+
+	max, err := c.maxDatagramSize()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(data) <= max {
+		logWire(wireOut, c.addr, data)
+		n, err := c.conn.Write(data)
+		if !isMessageTooLong(err) {
+			return n, err
+		}
+
+		max, err = c.maxDatagramSize()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	parts, err := splitBundle(b, max)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, part := range parts {
+		data, err := c.marshal(part)
+		if err != nil {
+			return sent, err
+		}
+
+		logWire(wireOut, c.addr, data)
+
+		n, err := c.conn.Write(data)
+		sent += n
+		if err != nil {
+			return sent, err
+		}
+	}
+
+	return sent, nil
+}
+
+/*
+splitBundle splits a bundle into bundles with the same time tag, each encoding to at most max bytes. Nested bundles
+which are too large are split in turn. An error is returned if a message is too large to fit in a bundle of max bytes.
+*/
+func splitBundle(b *Bundle, max int) ([]*Bundle, error) {
+	// The space available for the elements of each bundle, each of which is prefixed with its size
+	space := max - bundleHeaderSize
+
+	var elements []Packet
+	for _, e := range b.Elements {
+		data, err := e.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		if 4+len(data) <= space {
+			elements = append(elements, e)
+			continue
+		}
+
+		nested, ok := e.(*Bundle)
+		if !ok {
+			return nil, fmt.Errorf("Message of %d bytes exceeds the maximum datagram size of %d bytes", len(data), max)
+		}
+
+		parts, err := splitBundle(nested, space-4)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, part := range parts {
+			elements = append(elements, part)
+		}
+	}
+
+	parts := []*Bundle{{TimeTag: b.TimeTag}}
+	used := 0
+
+	for _, e := range elements {
+		data, err := e.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		size := 4 + len(data)
+		if used+size > space && used > 0 {
+			parts = append(parts, &Bundle{TimeTag: b.TimeTag})
+			used = 0
+		}
+
+		last := parts[len(parts)-1]
+		last.Elements = append(last.Elements, e)
+		used += size
+	}
+
+	return parts, nil
+}
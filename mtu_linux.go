@@ -0,0 +1,57 @@
+//go:build linux
+
+package osc
+
+import (
+	"errors"
+	"syscall"
+)
+
+/*
+setPathMTUDiscovery sets the don't-fragment flag on packets sent from a socket, so that the kernel discovers the path
+MTU to the connected destination.
+*/
+func setPathMTUDiscovery(c syscall.RawConn, ipv6 bool) error {
+	var sockErr error
+
+	err := c.Control(func(fd uintptr) {
+		if ipv6 {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_MTU_DISCOVER, syscall.IPV6_PMTUDISC_DO)
+		} else {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_MTU_DISCOVER, syscall.IP_PMTUDISC_DO)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return sockErr
+}
+
+/*
+pathMTU returns the path MTU currently known by the kernel to the destination of a connected socket.
+*/
+func pathMTU(c syscall.RawConn, ipv6 bool) (int, error) {
+	var mtu int
+	var sockErr error
+
+	err := c.Control(func(fd uintptr) {
+		if ipv6 {
+			mtu, sockErr = syscall.GetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_MTU)
+		} else {
+			mtu, sockErr = syscall.GetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_MTU)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return mtu, sockErr
+}
+
+/*
+isMessageTooLong returns true if err reports that a datagram was larger than the path MTU.
+*/
+func isMessageTooLong(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE)
+}
@@ -0,0 +1,29 @@
+//go:build !linux
+
+package osc
+
+import (
+	"fmt"
+	"syscall"
+)
+
+/*
+setPathMTUDiscovery is not supported on this platform.
+*/
+func setPathMTUDiscovery(c syscall.RawConn, ipv6 bool) error {
+	return fmt.Errorf("Path MTU discovery is not supported on this platform")
+}
+
+/*
+pathMTU is not supported on this platform.
+*/
+func pathMTU(c syscall.RawConn, ipv6 bool) (int, error) {
+	return 0, fmt.Errorf("Path MTU discovery is not supported on this platform")
+}
+
+/*
+isMessageTooLong always returns false, since path MTU discovery is not supported on this platform.
+*/
+func isMessageTooLong(err error) bool {
+	return false
+}
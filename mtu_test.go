@@ -0,0 +1,88 @@
+package osc
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSplitBundle(t *testing.T) {
+	bundle := NewBundle()
+	for i := 0; i < 10; i++ {
+		msg := NewMessage("/cue/fire")
+		msg.AddArgument(int32(i))
+		bundle.AddPacket(msg)
+	}
+
+	nested := NewBundle()
+	for i := 0; i < 4; i++ {
+		nested.AddPacket(NewMessage("/cue/go"))
+	}
+	bundle.AddPacket(nested)
+
+	max := 100
+	parts, err := splitBundle(bundle, max)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var messages int
+	for _, part := range parts {
+		data, _ := part.MarshalBinary()
+		if len(data) > max {
+			t.Errorf("Got a bundle of %d bytes, expected at most %d", len(data), max)
+		}
+		if part.TimeTag != bundle.TimeTag {
+			t.Errorf("Got time tag %v, expected %v", part.TimeTag, bundle.TimeTag)
+		}
+
+		var count func(p Packet)
+		count = func(p Packet) {
+			Visit(p, func(m *Message) {
+				messages++
+			}, func(b *Bundle) {
+				for _, e := range b.Elements {
+					count(e)
+				}
+			})
+		}
+		count(part)
+	}
+
+	expected1 := 14
+	if messages != expected1 {
+		t.Errorf("Got %v, expected %v", messages, expected1)
+	}
+
+	tooLarge := NewBundle()
+	tooLarge.AddPacket(NewMessage("/a/very/long/address/which/does/not/fit/in/a/datagram/of/the/maximum/size"))
+
+	if _, err := splitBundle(tooLarge, 64); err == nil {
+		t.Error("Expected an error splitting a bundle with a message larger than the maximum size")
+	}
+}
+
+func TestMTUDiscovery(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Path MTU discovery is only supported on Linux")
+	}
+
+	c, _ := NewUDPClient("127.0.0.1", 8765)
+	client := c.(*UDPClient)
+	client.SetMTUDiscovery(true)
+
+	err := client.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	mtu, err := client.PathMTU()
+	if err != nil || mtu <= 0 {
+		t.Errorf("Got %v (%v), expected the loopback MTU", mtu, err)
+	}
+
+	err = client.Send(NewBundle())
+	if err != nil {
+		t.Error(err)
+	}
+}
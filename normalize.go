@@ -0,0 +1,84 @@
+package osc
+
+import (
+	"strings"
+
+	"github.com/dougfinl/go-osc/codec"
+)
+
+/*
+AddressCase determines how an AddressSpace treats the case of incoming addresses.
+*/
+type AddressCase int
+
+const (
+	// CaseSensitive matches addresses exactly, as specified by OSC. This is the default.
+	CaseSensitive AddressCase = iota
+	// CaseInsensitive matches addresses to methods ignoring case. Handlers receive the address as it was sent.
+	CaseInsensitive
+	// LowerCase converts addresses to lower case before dispatch, and matches addresses to methods ignoring case.
+	LowerCase
+)
+
+/*
+SetAddressCase sets how the case of incoming addresses is treated, for controllers that send mixed-case addresses. By
+default, addresses are case sensitive.
+*/
+func (a *AddressSpace) SetAddressCase(addressCase AddressCase) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.addressCase = addressCase
+}
+
+/*
+matchAddress matches an address against a method's address pattern, ignoring case unless the address space is case
+sensitive.
+*/
+func matchAddress(pattern, address string, addressCase AddressCase) bool {
+	if addressCase == CaseSensitive {
+		return codec.Match(pattern, address)
+	}
+
+	return codec.Match(strings.ToLower(pattern), strings.ToLower(address))
+}
+
+/*
+SetNormalizeAddresses sets whether incoming addresses are normalized before dispatch, for senders that produce sloppy
+addresses which would otherwise never match: a trailing slash is removed ("/foo/" becomes "/foo"), and empty address
+parts are collapsed ("/foo//bar" becomes "/foo/bar"). Only the addresses of incoming messages are normalized, so "//"
+keeps its meaning as a wildcard in method address patterns. By default, addresses are not normalized.
+*/
+func (a *AddressSpace) SetNormalizeAddresses(normalize bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.normalize = normalize
+}
+
+/*
+normalizeAddress removes a trailing slash and empty address parts from address.
+*/
+func normalizeAddress(address string) string {
+	if !strings.Contains(address, "//") && (len(address) <= 1 || !strings.HasSuffix(address, "/")) {
+		return address
+	}
+
+	var b strings.Builder
+	b.Grow(len(address))
+
+	for i := 0; i < len(address); i++ {
+		if address[i] == '/' && i+1 < len(address) && address[i+1] == '/' {
+			continue
+		}
+
+		b.WriteByte(address[i])
+	}
+
+	normalized := b.String()
+	if len(normalized) > 1 {
+		normalized = strings.TrimSuffix(normalized, "/")
+	}
+
+	return normalized
+}
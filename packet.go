@@ -1,15 +1,324 @@
 package osc
 
 import (
-	"encoding"
-	"fmt"
+	"errors"
+	"time"
+
+	"github.com/dougfinl/go-osc/codec"
+)
+
+var (
+	// MinTimeTagTime is the earliest time that can be represented by a TimeTag.
+	MinTimeTagTime = codec.MinTimeTagTime
+	// MaxTimeTagTime is the latest time that can be represented by a TimeTag.
+	MaxTimeTagTime = codec.MaxTimeTagTime
 )
 
 /*
 Packet represents and encodable OSC packet.
 */
-type Packet interface {
-	encoding.BinaryMarshaler
-	encoding.BinaryUnmarshaler
-	fmt.Stringer
+type Packet = codec.Packet
+
+/*
+Message represents a single OSC message with address pattern and arguments.
+*/
+type Message = codec.Message
+
+/*
+Bundle represents an OSC bundle, which contains a time tag and multiple child elements.
+*/
+type Bundle = codec.Bundle
+
+/*
+TimeTag represents an OSC time tag with an underlying Go time.Time, and an "immediate" flag.
+*/
+type TimeTag = codec.TimeTag
+
+/*
+Char is an OSC 1.1 ASCII character argument (type tag 'c').
+*/
+type Char = codec.Char
+
+/*
+RGBA is an OSC 1.1 32-bit color argument (type tag 'r').
+*/
+type RGBA = codec.RGBA
+
+/*
+MIDIMessage is an OSC 1.1 4-byte MIDI message argument (type tag 'm').
+*/
+type MIDIMessage = codec.MIDIMessage
+
+/*
+Infinitum is the OSC 1.1 "infinitum" argument (type tag 'I'), which has no data.
+*/
+type Infinitum = codec.Infinitum
+
+/*
+TypeTag is the type tag of a single OSC argument, as it appears in the type tag string of a message.
+*/
+type TypeTag = codec.TypeTag
+
+// Type tags of the OSC argument types supported by this package.
+const (
+	TypeInt32      = codec.TypeInt32
+	TypeFloat32    = codec.TypeFloat32
+	TypeString     = codec.TypeString
+	TypeBlob       = codec.TypeBlob
+	TypeInt64      = codec.TypeInt64
+	TypeTimeTag    = codec.TypeTimeTag
+	TypeFloat64    = codec.TypeFloat64
+	TypeTrue       = codec.TypeTrue
+	TypeFalse      = codec.TypeFalse
+	TypeNil        = codec.TypeNil
+	TypeChar       = codec.TypeChar
+	TypeRGBA       = codec.TypeRGBA
+	TypeMIDI       = codec.TypeMIDI
+	TypeInfinitum  = codec.TypeInfinitum
+	TypeArrayStart = codec.TypeArrayStart
+	TypeArrayEnd   = codec.TypeArrayEnd
+)
+
+/*
+Signature describes the arguments an address takes, as a sequence of type tags (e.g. "ifs").
+*/
+type Signature = codec.Signature
+
+/*
+KeyValue is a named argument, in the convention of arguments alternating between string keys and values.
+*/
+type KeyValue = codec.KeyValue
+
+/*
+KeyValues is an ordered list of named arguments.
+*/
+type KeyValues = codec.KeyValues
+
+/*
+ScheduledMessage is a message from a bundle, with the time at which it should be processed.
+*/
+type ScheduledMessage = codec.ScheduledMessage
+
+/*
+TrailingDataError reports bytes left over after decoding the arguments of a message.
+*/
+type TrailingDataError = codec.TrailingDataError
+
+/*
+NewEmptyMessage returns an OSC message with default values.
+*/
+func NewEmptyMessage() *Message {
+	return codec.NewEmptyMessage()
+}
+
+/*
+NewMessage creates a new OSC message with an address pattern, and empty arguments.
+*/
+func NewMessage(address string) *Message {
+	return codec.NewMessage(address)
+}
+
+/*
+NewMessageFromData is a convenience function to unmarshal a message from a byte slice.
+*/
+func NewMessageFromData(data []byte) (*Message, error) {
+	return codec.NewMessageFromData(data)
+}
+
+/*
+NewMessageFromStruct creates a message whose arguments are the fields of a struct, ordered and typed by their "osc"
+struct tags (see Message.Scan for the reverse).
+*/
+func NewMessageFromStruct(address string, src interface{}) (*Message, error) {
+	return codec.NewMessageFromStruct(address, src)
+}
+
+/*
+NewBundle returns a bundle with immediate time tag.
+*/
+func NewBundle() *Bundle {
+	return codec.NewBundle()
+}
+
+/*
+NewBundleFromData is a convenience factory to decode a bundle from a byte slice.
+*/
+func NewBundleFromData(data []byte) (*Bundle, error) {
+	return codec.NewBundleFromData(data)
+}
+
+/*
+NewTimeTag returns a TimeTag with the specified Go Time.
+*/
+func NewTimeTag(t time.Time) TimeTag {
+	return codec.NewTimeTag(t)
+}
+
+/*
+NewTimeTagFromRaw returns the TimeTag with a 64-bit OSC encoding, as returned by TimeTag.Raw.
+*/
+func NewTimeTagFromRaw(raw uint64) TimeTag {
+	return codec.NewTimeTagFromRaw(raw)
+}
+
+/*
+NewImmediateTimeTag returns a TimeTag representing immediate execution.
+*/
+func NewImmediateTimeTag() TimeTag {
+	return codec.NewImmediateTimeTag()
+}
+
+/*
+decodePacket attempts to decode a packet into a Message or a Bundle. If any message has trailing bytes, the packet is
+returned along with a *TrailingDataError.
+*/
+func decodePacket(data []byte) (Packet, error) {
+	return codec.DecodePacketStrict(data)
+}
+
+/*
+decodeLenient is like decodePacket, but accepts messages with trailing bytes, only returning an error if the data
+cannot be decoded.
+*/
+func decodeLenient(data []byte) (Packet, error) {
+	p, err := decodePacket(data)
+	if isTrailingData(err) {
+		return p, nil
+	}
+
+	return p, err
+}
+
+/*
+isTrailingData returns true if err reports trailing bytes after a message which was otherwise decoded.
+*/
+func isTrailingData(err error) bool {
+	var trailing *TrailingDataError
+
+	return errors.As(err, &trailing)
+}
+
+/*
+Visit invokes onMessage if p is a Message, or onBundle if p is a Bundle. Either callback may be nil, and nil
+Packets (including typed nil pointers) are ignored.
+*/
+func Visit(p Packet, onMessage func(*Message), onBundle func(*Bundle)) {
+	codec.Visit(p, onMessage, onBundle)
+}
+
+/*
+IsMessage returns true if p is a Message.
+*/
+func IsMessage(p Packet) bool {
+	return codec.IsMessage(p)
+}
+
+/*
+IsBundle returns true if p is a Bundle.
+*/
+func IsBundle(p Packet) bool {
+	return codec.IsBundle(p)
+}
+
+/*
+ArgumentConverter converts a Go value into a value of a supported OSC argument type.
+*/
+type ArgumentConverter = codec.ArgumentConverter
+
+/*
+RegisterConverter registers a function to convert values of the same type as sample when they are passed to
+AddArgument, allowing application types to be sent without manual conversion.
+*/
+func RegisterConverter(sample interface{}, fn ArgumentConverter) {
+	codec.RegisterConverter(sample, fn)
+}
+
+/*
+MonotonicClock generates TimeTags from the local monotonic clock, mapped to wall clock time at a fixed epoch.
+*/
+type MonotonicClock = codec.MonotonicClock
+
+/*
+NewMonotonicClock returns a MonotonicClock with its epoch set to the current time.
+*/
+func NewMonotonicClock() *MonotonicClock {
+	return codec.NewMonotonicClock()
+}
+
+/*
+ArgumentTypeTag returns the OSC type tag (e.g. "i" for an int32) of a Go value, or an error if the type is not
+supported.
+*/
+func ArgumentTypeTag(arg interface{}) (string, error) {
+	return codec.ArgumentTypeTag(arg)
+}
+
+/*
+EncodeArgument encodes a single OSC argument, as it would appear in the argument data of a message.
+*/
+func EncodeArgument(arg interface{}) ([]byte, error) {
+	return codec.EncodeArgument(arg)
+}
+
+/*
+DecodeArgument decodes a single OSC argument of the type given by typeTag from the start of data, returning the value
+and the number of bytes consumed.
+*/
+func DecodeArgument(typeTag TypeTag, data []byte) (interface{}, int, error) {
+	return codec.DecodeArgument(typeTag, data)
+}
+
+/*
+LazyMessage is an OSC message that decodes only its address and type tag string up front, and decodes its arguments
+on first access.
+*/
+type LazyMessage = codec.LazyMessage
+
+/*
+NewLazyMessage decodes the address and type tag string of an encoded message, deferring the decoding of arguments.
+*/
+func NewLazyMessage(data []byte) (*LazyMessage, error) {
+	return codec.NewLazyMessage(data)
+}
+
+/*
+ParseTypeTags parses a type tag string, with or without its leading comma, into its type tags.
+*/
+func ParseTypeTags(s string) ([]TypeTag, error) {
+	return codec.ParseTypeTags(s)
+}
+
+/*
+ParseSignature parses a signature from a type tag string, with or without its leading comma.
+*/
+func ParseSignature(s string) (Signature, error) {
+	return codec.ParseSignature(s)
+}
+
+/*
+NewTimeTagFromSeconds returns a TimeTag for a number of seconds since the OSC epoch (1 January 1900).
+*/
+func NewTimeTagFromSeconds(seconds float64) TimeTag {
+	return codec.NewTimeTagFromSeconds(seconds)
+}
+
+/*
+FramesBetween returns the number of sample frames at sampleRate from one TimeTag to another.
+*/
+func FramesBetween(from, to TimeTag, sampleRate float64) float64 {
+	return codec.FramesBetween(from, to, sampleRate)
+}
+
+/*
+FramesUntil returns the number of sample frames at sampleRate from now until the time of tt.
+*/
+func FramesUntil(tt TimeTag, sampleRate float64) float64 {
+	return codec.FramesUntil(tt, sampleRate)
+}
+
+/*
+TimeTagForFrame returns the TimeTag of a sample frame, counting from frame 0 at origin, at sampleRate.
+*/
+func TimeTagForFrame(origin TimeTag, frame float64, sampleRate float64) TimeTag {
+	return codec.TimeTagForFrame(origin, frame, sampleRate)
 }
@@ -0,0 +1,142 @@
+package osc
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+/*
+Snapshot is a copy of the state of a ParameterSpace, mapping each address to its arguments.
+*/
+type Snapshot map[string][]interface{}
+
+/*
+ParameterSpace holds the current value (the arguments of the last message) of a set of OSC addresses, such as the
+parameters exposed by a device or bridge. It is safe for concurrent use.
+*/
+type ParameterSpace struct {
+	mu     sync.RWMutex
+	values map[string][]interface{}
+}
+
+/*
+NewParameterSpace creates an empty ParameterSpace.
+*/
+func NewParameterSpace() *ParameterSpace {
+	return &ParameterSpace{values: make(map[string][]interface{})}
+}
+
+/*
+Set sets the value of an address.
+*/
+func (ps *ParameterSpace) Set(address string, args ...interface{}) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.values == nil {
+		ps.values = make(map[string][]interface{})
+	}
+
+	ps.values[address] = append([]interface{}(nil), args...)
+}
+
+/*
+setLimited sets the value of an address, unless the address has no value and limit addresses already have values. It
+returns false if the value was not set.
+*/
+func (ps *ParameterSpace) setLimited(address string, limit int, args ...interface{}) bool {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.values == nil {
+		ps.values = make(map[string][]interface{})
+	}
+
+	if _, ok := ps.values[address]; !ok && len(ps.values) >= limit {
+		return false
+	}
+
+	ps.values[address] = append([]interface{}(nil), args...)
+
+	return true
+}
+
+/*
+Get returns the value of an address, and whether it has been set.
+*/
+func (ps *ParameterSpace) Get(address string) ([]interface{}, bool) {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	args, ok := ps.values[address]
+
+	return append([]interface{}(nil), args...), ok
+}
+
+/*
+Update sets the value of the message's address to its arguments. It can be passed to Handle to track incoming
+messages.
+*/
+func (ps *ParameterSpace) Update(m *Message) {
+	ps.Set(m.Address, m.Arguments...)
+}
+
+/*
+Snapshot returns a copy of the current state of the ParameterSpace.
+*/
+func (ps *ParameterSpace) Snapshot() Snapshot {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	snapshot := make(Snapshot, len(ps.values))
+	for address, args := range ps.values {
+		snapshot[address] = append([]interface{}(nil), args...)
+	}
+
+	return snapshot
+}
+
+/*
+Apply updates the ParameterSpace with each of the messages, e.g. those returned by Diff.
+*/
+func (ps *ParameterSpace) Apply(messages []*Message) {
+	for _, m := range messages {
+		ps.Update(m)
+	}
+}
+
+/*
+Messages returns a message for each address in the snapshot, ordered by address. Sending these messages to a peer
+transfers the full state.
+*/
+func (s Snapshot) Messages() []*Message {
+	return Diff(nil, s)
+}
+
+/*
+Diff returns the messages needed to move a peer from the state from to the state to: one message for each address
+whose value differs, or that is missing from from, ordered by address. OSC has no way to unset an address, so
+addresses that are only present in from are ignored.
+*/
+func Diff(from, to Snapshot) []*Message {
+	addresses := make([]string, 0, len(to))
+	for address, args := range to {
+		old, ok := from[address]
+		if !ok || !reflect.DeepEqual(old, args) {
+			addresses = append(addresses, address)
+		}
+	}
+
+	sort.Strings(addresses)
+
+	messages := make([]*Message, len(addresses))
+	for i, address := range addresses {
+		messages[i] = &Message{
+			Address:   address,
+			Arguments: append([]interface{}(nil), to[address]...),
+		}
+	}
+
+	return messages
+}
@@ -0,0 +1,72 @@
+package osc
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	ps := NewParameterSpace()
+	ps.Set("/ch/1/fader", float32(0.5))
+	ps.Set("/ch/2/fader", float32(0.75))
+	ps.Set("/ch/1/mute", false)
+	from := ps.Snapshot()
+
+	ps.Set("/ch/2/fader", float32(0))
+	ps.Set("/ch/3/fader", float32(1))
+	to := ps.Snapshot()
+
+	result := Diff(from, to)
+
+	if len(result) != 2 {
+		t.Fatalf("Got %d messages, expected 2", len(result))
+	}
+
+	if result[0].Address != "/ch/2/fader" || result[0].Arguments[0] != float32(0) {
+		t.Errorf("Got %v, expected /ch/2/fader 0", result[0])
+	}
+
+	if result[1].Address != "/ch/3/fader" || result[1].Arguments[0] != float32(1) {
+		t.Errorf("Got %v, expected /ch/3/fader 1", result[1])
+	}
+
+	// Applying the diff to the old state reproduces the new state
+	patched := NewParameterSpace()
+	patched.Apply(from.Messages())
+	patched.Apply(result)
+
+	if remaining := Diff(patched.Snapshot(), to); len(remaining) != 0 {
+		t.Errorf("Got %v after patching, expected no differences", remaining)
+	}
+}
+
+func TestSnapshotJSON(t *testing.T) {
+	ps := NewParameterSpace()
+	ps.Set("/ch/1/fader", float32(0.5))
+	ps.Set("/ch/1/name", "Vocals")
+	ps.Set("/ch/1/eq", []interface{}{float32(100), float32(1000)}, true)
+	ps.Set("/scene", int32(3), []byte{1, 2, 3}, NewImmediateTimeTag(), nil)
+	ps.Set("/ch/1/colour", RGBA{R: 255, G: 128, A: 255}, Char('V'), MIDIMessage{Status: 0x90, Data1: 60, Data2: 127}, Infinitum{})
+
+	path := t.TempDir() + "/preset.json"
+	err := SaveSnapshot(path, ps.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewParameterSpace()
+	restored.Set("/stale", int32(1))
+	restored.Restore(loaded)
+
+	if remaining := Diff(restored.Snapshot(), ps.Snapshot()); len(remaining) != 0 {
+		t.Errorf("Got %v after restoring, expected no differences", remaining)
+	}
+
+	if _, ok := restored.Get("/stale"); ok {
+		t.Error("Restore did not replace the existing state")
+	}
+}
@@ -0,0 +1,51 @@
+package osc
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+/*
+Peer identifies the sender of a received message, and allows replying to it.
+*/
+type Peer struct {
+	// Addr is the network address of the sender, or nil if it has none (e.g. a serial device)
+	Addr net.Addr
+	// ReceivedAt is the time at which the packet containing the message was received
+	ReceivedAt time.Time
+	// KernelTimestamp is true if ReceivedAt was recorded by the operating system kernel, rather than when the packet was
+	// read by the server
+	KernelTimestamp bool
+
+	send func(p Packet) error
+}
+
+/*
+newPeer creates a Peer for a packet received now from addr, which can be replied to using send.
+*/
+func newPeer(addr net.Addr, send func(p Packet) error) *Peer {
+	return &Peer{Addr: addr, ReceivedAt: time.Now(), send: send}
+}
+
+/*
+Reply sends an OSC packet back to the peer.
+*/
+func (p *Peer) Reply(packet Packet) error {
+	if p == nil || p.send == nil {
+		return fmt.Errorf("Peer cannot be replied to")
+	}
+
+	return p.send(packet)
+}
+
+/*
+String implements the fmt.Stringer interface.
+*/
+func (p *Peer) String() string {
+	if p == nil || p.Addr == nil {
+		return "(unknown peer)"
+	}
+
+	return p.Addr.String()
+}
@@ -0,0 +1,400 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// PingAddress is the address of latency measurement requests.
+	PingAddress = "/ping"
+	// PongAddress is the address of replies to latency measurement requests.
+	PongAddress = "/pong"
+
+	// latencyWindow is the number of recent samples kept for each peer by a LatencyMonitor.
+	latencyWindow = 100
+)
+
+// requestToken generates the tokens matching replies to requests, unique across all address spaces.
+var requestToken int64
+
+/*
+replyWaiters holds the requests sent with request which are waiting for a reply, in the AddressSpace of the client
+receiving the replies.
+*/
+type replyWaiters struct {
+	mu      sync.Mutex
+	handled map[string]bool
+	waiters map[int64]chan *Message
+}
+
+/*
+HandlePing adds an OSC method to the AddressSpace which answers the pings sent by Ping. Each "/ping" message is
+answered with a "/pong" message containing the same token, sent back to the sender.
+*/
+func (a *AddressSpace) HandlePing() error {
+	return a.handlePeer(PingAddress, func(m *Message, peer *Peer) {
+		if len(m.Arguments) == 0 {
+			return
+		}
+
+		pong := NewMessage(PongAddress)
+		pong.AddArgument(m.Arguments[0])
+
+		peer.Reply(pong)
+	})
+}
+
+/*
+Ping measures the round-trip time to a remote endpoint which answers pings (see HandlePing). The ping is sent using
+client, and the pong is received by the client's AddressSpace, as replies are sent back to the sender. Ping returns
+when the pong is received, or with an error when ctx is done.
+*/
+func Ping(ctx context.Context, client Client) (time.Duration, error) {
+	start := time.Now()
+
+	_, err := request(ctx, client, PingAddress, PongAddress, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Since(start), nil
+}
+
+/*
+request sends a request to address containing a new token followed by args, and waits for a reply to replyAddress
+with the same token and at least minArgs arguments. The reply is received by the AddressSpace of client.
+*/
+func request(ctx context.Context, client Client, address, replyAddress string, minArgs int, args ...interface{}) (*Message, error) {
+	replies := clientReplies(client)
+	if replies == nil {
+		return nil, fmt.Errorf("Client %T cannot receive replies", client)
+	}
+
+	token := atomic.AddInt64(&requestToken, 1)
+	received, err := replies.awaitReply(replyAddress, token, minArgs)
+	if err != nil {
+		return nil, err
+	}
+	defer replies.stopAwaitingReply(token)
+
+	m := NewMessage(address)
+	m.AddArgument(token)
+	for _, arg := range args {
+		err := m.AddArgument(arg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = client.Send(m)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case reply := <-received:
+		return reply, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+/*
+awaitReply returns a channel receiving the reply to replyAddress with the given token, adding a method to receive
+replies to that address if there is none yet.
+*/
+func (a *AddressSpace) awaitReply(replyAddress string, token int64, minArgs int) (chan *Message, error) {
+	q := &a.waiting
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.handled[replyAddress] {
+		err := a.Handle(replyAddress, func(m *Message) {
+			a.receiveReply(m, minArgs)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if q.handled == nil {
+			q.handled = make(map[string]bool)
+			q.waiters = make(map[int64]chan *Message)
+		}
+		q.handled[replyAddress] = true
+	}
+
+	received := make(chan *Message, 1)
+	q.waiters[token] = received
+
+	return received, nil
+}
+
+/*
+stopAwaitingReply forgets the request with the given token.
+*/
+func (a *AddressSpace) stopAwaitingReply(token int64) {
+	a.waiting.mu.Lock()
+	defer a.waiting.mu.Unlock()
+
+	delete(a.waiting.waiters, token)
+}
+
+/*
+receiveReply passes a reply to the request waiting for its token.
+*/
+func (a *AddressSpace) receiveReply(m *Message, minArgs int) {
+	if len(m.Arguments) < minArgs {
+		return
+	}
+
+	token, ok := m.Arguments[0].(int64)
+	if !ok {
+		return
+	}
+
+	a.waiting.mu.Lock()
+	waiter, ok := a.waiting.waiters[token]
+	a.waiting.mu.Unlock()
+
+	if ok {
+		select {
+		case waiter <- m.Clone():
+		default:
+		}
+	}
+}
+
+/*
+replyReceiver is implemented by clients which dispatch the packets they receive (such as replies to the packets they
+send) to an AddressSpace.
+*/
+type replyReceiver interface {
+	replyAddressSpace() *AddressSpace
+}
+
+/*
+clientReplies returns the AddressSpace receiving replies to the packets sent by client, or nil if it has none.
+*/
+func clientReplies(client Client) *AddressSpace {
+	if r, ok := client.(replyReceiver); ok {
+		return r.replyAddressSpace()
+	}
+
+	return nil
+}
+
+func (c *UDPClient) replyAddressSpace() *AddressSpace {
+	return &c.AddressSpace
+}
+
+func (c *TCPClient) replyAddressSpace() *AddressSpace {
+	return &c.AddressSpace
+}
+
+func (c *SerialClient) replyAddressSpace() *AddressSpace {
+	return &c.AddressSpace
+}
+
+func (c *TransportClient) replyAddressSpace() *AddressSpace {
+	return &c.AddressSpace
+}
+
+func (c *ChaosClient) replyAddressSpace() *AddressSpace {
+	return clientReplies(c.Client)
+}
+
+func (q *PersistentQueue) replyAddressSpace() *AddressSpace {
+	return clientReplies(q.Client)
+}
+
+func (r *TimelineRecorder) replyAddressSpace() *AddressSpace {
+	return clientReplies(r.Client)
+}
+
+func (c *PrefixClient) replyAddressSpace() *AddressSpace {
+	return clientReplies(c.Client)
+}
+
+/*
+LatencyStats summarises the round-trip times recently measured to a peer.
+*/
+type LatencyStats struct {
+	// Samples is the number of successful pings the statistics are calculated from
+	Samples int
+	// Lost is the total number of pings which were not answered in time
+	Lost int
+	Min  time.Duration
+	P50  time.Duration
+	P95  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+/*
+LatencyMonitor continuously pings a set of peers, keeping round-trip time statistics for each. It is safe for
+concurrent use.
+*/
+type LatencyMonitor struct {
+	interval time.Duration
+	timeout  time.Duration
+
+	mu    sync.Mutex
+	peers map[string]*latencyPeer
+}
+
+type latencyPeer struct {
+	client  Client
+	samples []time.Duration
+	next    int
+	lost    int
+}
+
+/*
+NewLatencyMonitor creates a LatencyMonitor. By default each peer is pinged once a second, and pings not answered within
+a second are counted as lost.
+*/
+func NewLatencyMonitor() *LatencyMonitor {
+	return &LatencyMonitor{
+		interval: time.Second,
+		timeout:  time.Second,
+		peers:    make(map[string]*latencyPeer),
+	}
+}
+
+/*
+SetInterval sets how often each peer is pinged, and how long to wait for each pong.
+*/
+func (lm *LatencyMonitor) SetInterval(interval time.Duration) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	lm.interval = interval
+	lm.timeout = interval
+}
+
+/*
+AddPeer adds a peer to be pinged using client, identified by name in the statistics. The client must be able to
+receive replies (see Ping).
+*/
+func (lm *LatencyMonitor) AddPeer(name string, client Client) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	lm.peers[name] = &latencyPeer{client: client}
+}
+
+/*
+Run pings the peers until ctx is done.
+*/
+func (lm *LatencyMonitor) Run(ctx context.Context) {
+	lm.mu.Lock()
+	ticker := time.NewTicker(lm.interval)
+	lm.mu.Unlock()
+	defer ticker.Stop()
+
+	for {
+		lm.pingAll(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+/*
+pingAll pings every peer concurrently, recording the results.
+*/
+func (lm *LatencyMonitor) pingAll(ctx context.Context) {
+	lm.mu.Lock()
+	timeout := lm.timeout
+	peers := make(map[string]Client, len(lm.peers))
+	for name, peer := range lm.peers {
+		peers[name] = peer.client
+	}
+	lm.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for name, client := range peers {
+		wg.Add(1)
+		go func(name string, client Client) {
+			defer wg.Done()
+
+			pingCtx, cancel := context.WithTimeout(ctx, timeout)
+			rtt, err := Ping(pingCtx, client)
+			cancel()
+
+			if ctx.Err() == nil {
+				lm.record(name, rtt, err)
+			}
+		}(name, client)
+	}
+	wg.Wait()
+}
+
+func (lm *LatencyMonitor) record(name string, rtt time.Duration, err error) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	peer, ok := lm.peers[name]
+	if !ok {
+		return
+	}
+
+	if err != nil {
+		peer.lost++
+		return
+	}
+
+	if len(peer.samples) < latencyWindow {
+		peer.samples = append(peer.samples, rtt)
+	} else {
+		peer.samples[peer.next] = rtt
+		peer.next = (peer.next + 1) % latencyWindow
+	}
+}
+
+/*
+Stats returns the latency statistics of a peer. If there is no peer with the given name, an error is returned.
+*/
+func (lm *LatencyMonitor) Stats(name string) (LatencyStats, error) {
+	lm.mu.Lock()
+	peer, ok := lm.peers[name]
+	var samples []time.Duration
+	var lost int
+	if ok {
+		samples = append(samples, peer.samples...)
+		lost = peer.lost
+	}
+	lm.mu.Unlock()
+
+	if !ok {
+		return LatencyStats{}, fmt.Errorf("No peer named %s", name)
+	}
+
+	stats := LatencyStats{Samples: len(samples), Lost: lost}
+	if len(samples) == 0 {
+		return stats, nil
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	percentile := func(p int) time.Duration {
+		return samples[(len(samples)-1)*p/100]
+	}
+
+	stats.Min = samples[0]
+	stats.P50 = percentile(50)
+	stats.P95 = percentile(95)
+	stats.P99 = percentile(99)
+	stats.Max = samples[len(samples)-1]
+
+	return stats, nil
+}
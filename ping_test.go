@@ -0,0 +1,99 @@
+package osc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestPing(t *testing.T) {
+	responder, _ := NewUDPServer("127.0.0.1", 0)
+	responder.(*UDPServer).HandlePing()
+	err := responder.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer responder.StopListening()
+
+	client, _ := NewUDPClient("127.0.0.1", responder.LocalAddr().(*net.UDPAddr).Port)
+	err = client.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	rtt, err := Ping(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	} else if rtt <= 0 {
+		t.Errorf("Got %v, expected a positive round-trip time", rtt)
+	}
+
+	monitor := NewLatencyMonitor()
+	monitor.AddPeer("responder", client)
+	monitor.pingAll(ctx)
+	monitor.pingAll(ctx)
+
+	stats, err := monitor.Stats("responder")
+	if err != nil {
+		t.Fatal(err)
+	} else if stats.Samples != 2 || stats.Lost != 0 || stats.Min > stats.P50 || stats.P50 > stats.Max {
+		t.Errorf("Got %+v, expected 2 ordered samples", stats)
+	}
+}
+
+func TestPingWaiters(t *testing.T) {
+	responder, _ := NewUDPServer("127.0.0.1", 0)
+	responder.(*UDPServer).HandlePing()
+	err := responder.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer responder.StopListening()
+
+	client, _ := NewUDPClient("127.0.0.1", responder.LocalAddr().(*net.UDPAddr).Port)
+	client.Connect()
+	defer client.Disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		if _, err := Ping(ctx, client); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The state of finished pings is kept in the client, and released once they finish
+	replies := clientReplies(client)
+	if result := len(replies.waiting.waiters); result != 0 {
+		t.Errorf("Got %v waiting pings, expected %v", result, 0)
+	}
+	if result := len(replies.Methods()); result != 1 {
+		t.Errorf("Got %v methods, expected %v", result, 1)
+	}
+
+	// A reply port in the ping is ignored, so that pongs cannot be reflected elsewhere
+	victim, _ := NewUDPServer("127.0.0.1", 0)
+	reflected := make(chan struct{}, 1)
+	victim.Handle(PongAddress, func(m *Message) {
+		reflected <- struct{}{}
+	})
+	victim.StartListening()
+	defer victim.StopListening()
+
+	ping := NewMessage(PingAddress)
+	ping.AddArgument(int64(1))
+	ping.AddArgument(int32(victim.LocalAddr().(*net.UDPAddr).Port))
+	client.Send(ping)
+
+	select {
+	case <-reflected:
+		t.Error("Pong was sent to the reply port")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,52 @@
+package osc
+
+import (
+	"sync"
+)
+
+// Messages decoded by address spaces with message pooling enabled
+var messagePool = sync.Pool{
+	New: func() interface{} {
+		return &Message{}
+	},
+}
+
+/*
+SetMessagePooling enables or disables pooling of the messages received by servers and clients, to avoid allocating a
+new message for every packet in high-rate receivers. When enabled, each message received on its own (rather than in a
+bundle) is decoded into a message taken from a pool, and returned to the pool as soon as all of the methods it was
+dispatched to have returned, after which its address and arguments are overwritten by the next packet received.
+
+Methods must therefore not retain the message, or its arguments, beyond the call: a method which keeps a message,
+passes it to another goroutine, or stores one of its blobs or arrays must keep a copy made with Clone instead. The
+package's own methods which retain messages (e.g. those added by HandleTask and SubscribeContext) already do so.
+*/
+func (a *AddressSpace) SetMessagePooling(enable bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.pooling = enable
+}
+
+/*
+decodePooledMessage decodes a message received on its own into a message taken from the pool.
+*/
+func decodePooledMessage(data []byte) (*Message, error) {
+	m := messagePool.Get().(*Message)
+
+	err := m.UnmarshalStrict(data)
+	if err != nil && !isTrailingData(err) {
+		releaseMessage(m)
+		return nil, err
+	}
+
+	return m, err
+}
+
+/*
+releaseMessage returns a message to the pool once it has been dispatched.
+*/
+func releaseMessage(m *Message) {
+	m.Reset()
+	messagePool.Put(m)
+}
@@ -0,0 +1,62 @@
+package osc
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestMessagePooling(t *testing.T) {
+	var a AddressSpace
+	a.SetMessagePooling(true)
+
+	var received []*Message
+	a.Handle("/fader/*", func(m *Message) {
+		received = append(received, m.Clone())
+	})
+
+	for _, level := range []float32{0.25, 0.5} {
+		msg := NewMessage("/fader/1")
+		msg.AddArgument(level)
+		msg.AddArgument([]byte{1, 2})
+		data, _ := msg.MarshalBinary()
+		a.dispatchData(data, nil)
+	}
+
+	expected1 := []interface{}{float32(0.25), []byte{1, 2}}
+	if len(received) != 2 || !reflect.DeepEqual(received[0].Arguments, expected1) {
+		t.Errorf("Got %v, expected the first message to have arguments %v", received, expected1)
+	}
+
+	// Messages retained by the package's own methods are copied
+	ch, err := a.SubscribeContext(context.Background(), "/fader/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := NewMessage("/fader/1")
+	msg.AddArgument(float32(0.75))
+	data, _ := msg.MarshalBinary()
+	a.dispatchData(data, nil)
+
+	other := NewMessage("/fader/1")
+	other.AddArgument(float32(1))
+	data, _ = other.MarshalBinary()
+	a.dispatchData(data, nil)
+
+	result2 := <-ch
+	if !result2.Equals(msg) {
+		t.Errorf("Got %v, expected %v", result2, msg)
+	}
+
+	// Invalid messages are logged and dropped
+	logger := &testLogger{}
+	a.SetLogger(logger)
+	a.dispatchData([]byte("/fader/1"), nil)
+
+	result3 := len(logger.lines)
+	expected3 := 1
+	if result3 != expected3 {
+		t.Errorf("Got %v, expected %v", result3, expected3)
+	}
+}
@@ -0,0 +1,238 @@
+package osc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+/*
+peerPrefix is a prefix added to the addresses of messages from the peers allowed by sources.
+*/
+type peerPrefix struct {
+	prefix  string
+	sources ACL
+}
+
+/*
+SetPeerPrefix dispatches the messages sent from sources under prefix, so that the same AddressSpace can serve several
+controllers which use the same address layout, e.g. after SetPeerPrefix("/a", consoleA) a message sent to "/fader/1" by
+console A is dispatched as "/a/fader/1". Prefixes are added before aliases are resolved. If a peer is allowed by the
+sources of several prefixes, the first prefix set is used; messages from other peers, or whose peer is unknown, are
+dispatched unchanged. Setting a prefix again replaces its sources, and nil sources remove it.
+*/
+func (a *AddressSpace) SetPeerPrefix(prefix string, sources ACL) error {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	if !strings.HasPrefix(prefix, "/") {
+		return fmt.Errorf("Peer prefix must start with '/'")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Copy the prefixes, as messages being dispatched may hold the current ones
+	prefixes := make([]peerPrefix, 0, len(a.prefixes)+1)
+	replaced := false
+	for _, p := range a.prefixes {
+		if p.prefix == prefix {
+			if sources == nil {
+				continue
+			}
+
+			p.sources = sources
+			replaced = true
+		}
+
+		prefixes = append(prefixes, p)
+	}
+
+	if !replaced && sources != nil {
+		prefixes = append(prefixes, peerPrefix{prefix: prefix, sources: sources})
+	}
+
+	a.prefixes = prefixes
+
+	return nil
+}
+
+/*
+peerPrefixFor returns the prefix for messages from peer, or an empty string if it has none.
+*/
+func peerPrefixFor(prefixes []peerPrefix, peer *Peer) string {
+	if peer == nil {
+		return ""
+	}
+
+	for _, p := range prefixes {
+		if p.sources.Allows(peer.Addr) {
+			return p.prefix
+		}
+	}
+
+	return ""
+}
+
+/*
+PrefixClient wraps a Client, adding a prefix to the address of every message sent with it, including the messages of
+bundles, so that application code can address a device without knowing which rig it belongs to. It is created by the
+WithPrefix method of a Client, e.g. client.WithPrefix("/rig1").
+*/
+type PrefixClient struct {
+	Client
+
+	prefix string
+}
+
+/*
+newPrefixClient creates a PrefixClient sending with client, with the trailing '/' (if any) removed from prefix.
+*/
+func newPrefixClient(client Client, prefix string) *PrefixClient {
+	return &PrefixClient{Client: client, prefix: strings.TrimSuffix(prefix, "/")}
+}
+
+/*
+Send sends an OSC packet, with the prefix added to the addresses of its messages. The packet is not modified. An error
+is returned if the prefix does not start with '/'.
+*/
+func (c *PrefixClient) Send(p Packet) error {
+	if c.prefix != "" && !strings.HasPrefix(c.prefix, "/") {
+		return fmt.Errorf("Client prefix \"%s\" must start with '/'", c.prefix)
+	}
+
+	prefixed, err := prefixPacket(p, c.prefix)
+	if err != nil {
+		return err
+	}
+
+	return c.Client.Send(prefixed)
+}
+
+/*
+SendAt sends an OSC packet wrapped in a bundle with a time tag of t plus the wrapped client's time tag offset, with the
+prefix added to the addresses of its messages.
+*/
+func (c *PrefixClient) SendAt(p Packet, t time.Time) error {
+	return c.Send(newTimedBundle(p, offsetTime(c.Client, t)))
+}
+
+/*
+Transaction returns a new Transaction sending through the client.
+*/
+func (c *PrefixClient) Transaction() *Transaction {
+	return NewTransaction(c)
+}
+
+/*
+Prefix returns the prefix added to addresses by the client.
+*/
+func (c *PrefixClient) Prefix() string {
+	return c.prefix
+}
+
+/*
+prefixPacket returns a copy of a packet with prefix added to the addresses of its messages, including those of nested
+bundles. Arguments are shared with the original packet.
+*/
+func prefixPacket(p Packet, prefix string) (Packet, error) {
+	switch e := p.(type) {
+	case *Message:
+		if e == nil {
+			return e, nil
+		}
+
+		prefixed := *e
+		prefixed.Address = prefix + e.Address
+		return &prefixed, nil
+	case *LazyMessage:
+		m, err := e.Message()
+		if err != nil {
+			return nil, err
+		}
+
+		return prefixPacket(m, prefix)
+	case *rawBundle:
+		return prefixPacket(e.Bundle, prefix)
+	case *Bundle:
+		if e == nil {
+			return e, nil
+		}
+
+		prefixed := &Bundle{TimeTag: e.TimeTag, Elements: make([]Packet, len(e.Elements))}
+		for i, element := range e.Elements {
+			var err error
+			prefixed.Elements[i], err = prefixPacket(element, prefix)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return prefixed, nil
+	}
+
+	return nil, fmt.Errorf("Cannot add a prefix to packet of type %T", p)
+}
+
+/*
+WithPrefix returns a client sending through c, which adds prefix to the address of every message it sends (see
+PrefixClient).
+*/
+func (c *UDPClient) WithPrefix(prefix string) Client {
+	return newPrefixClient(c, prefix)
+}
+
+/*
+WithPrefix returns a client sending through c, which adds prefix to the address of every message it sends (see
+PrefixClient).
+*/
+func (c *TCPClient) WithPrefix(prefix string) Client {
+	return newPrefixClient(c, prefix)
+}
+
+/*
+WithPrefix returns a client sending through c, which adds prefix to the address of every message it sends (see
+PrefixClient).
+*/
+func (c *SerialClient) WithPrefix(prefix string) Client {
+	return newPrefixClient(c, prefix)
+}
+
+/*
+WithPrefix returns a client sending through c, which adds prefix to the address of every message it sends (see
+PrefixClient).
+*/
+func (c *TransportClient) WithPrefix(prefix string) Client {
+	return newPrefixClient(c, prefix)
+}
+
+/*
+WithPrefix returns a client sending through the queue, which adds prefix to the address of every message it sends (see
+PrefixClient).
+*/
+func (q *PersistentQueue) WithPrefix(prefix string) Client {
+	return newPrefixClient(q, prefix)
+}
+
+/*
+WithPrefix returns a client sending through c, which adds prefix to the address of every message it sends (see
+PrefixClient).
+*/
+func (c *ChaosClient) WithPrefix(prefix string) Client {
+	return newPrefixClient(c, prefix)
+}
+
+/*
+WithPrefix returns a client sending through the recorder, which adds prefix to the address of every message it sends
+(see PrefixClient).
+*/
+func (r *TimelineRecorder) WithPrefix(prefix string) Client {
+	return newPrefixClient(r, prefix)
+}
+
+/*
+WithPrefix returns a client sending through c, which adds prefix to the address of every message it sends after the
+prefix of c, e.g. "/rig1" then "/stage" gives "/rig1/stage".
+*/
+func (c *PrefixClient) WithPrefix(prefix string) Client {
+	return newPrefixClient(c, prefix)
+}
@@ -0,0 +1,86 @@
+package osc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestSetPeerPrefix(t *testing.T) {
+	var a AddressSpace
+	var received []string
+
+	a.Handle("/*/fader/*", func(m *Message) {
+		received = append(received, m.Address)
+	})
+
+	consoleA, _ := ParseACL("192.168.1.20")
+	consoleB, _ := ParseACL("192.168.1.21")
+	a.SetPeerPrefix("/a", consoleA)
+	a.SetPeerPrefix("/b/", consoleB)
+
+	peerA := newPeer(&net.UDPAddr{IP: net.ParseIP("192.168.1.20")}, nil)
+	peerB := newPeer(&net.UDPAddr{IP: net.ParseIP("192.168.1.21")}, nil)
+	laptop := newPeer(&net.UDPAddr{IP: net.ParseIP("192.168.1.99")}, nil)
+
+	a.DispatchFrom(NewMessage("/fader/1"), peerA)
+	a.DispatchFrom(NewMessage("/fader/2"), peerB)
+	a.DispatchFrom(NewMessage("/main/fader/3"), laptop)
+
+	expected1 := []string{"/a/fader/1", "/b/fader/2", "/main/fader/3"}
+	if len(received) != len(expected1) || received[0] != expected1[0] || received[1] != expected1[1] || received[2] != expected1[2] {
+		t.Errorf("Got %v, expected %v", received, expected1)
+	}
+
+	// Removing a prefix dispatches messages from the peer unchanged
+	a.SetPeerPrefix("/a", nil)
+	received = nil
+	a.DispatchFrom(NewMessage("/desk/fader/1"), peerA)
+
+	if len(received) != 1 || received[0] != "/desk/fader/1" {
+		t.Errorf("Got %v, expected [/desk/fader/1]", received)
+	}
+
+	if err := a.SetPeerPrefix("a", consoleA); err == nil {
+		t.Errorf("Got %v, expected an error", err)
+	}
+}
+
+func TestWithPrefix(t *testing.T) {
+	client := &testClient{}
+	rig := newPrefixClient(client, "/rig1/")
+
+	bundle := NewBundle()
+	bundle.AddPacket(NewMessage("/fader/1"))
+	nested := NewBundle()
+	nested.AddPacket(NewMessage("/mute/1"))
+	bundle.AddPacket(nested)
+
+	err := rig.Send(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rig.WithPrefix("/stage").Send(NewMessage("/light/1"))
+
+	var result []string
+	for _, p := range client.sent {
+		Visit(p, func(m *Message) {
+			result = append(result, m.Address)
+		}, func(b *Bundle) {
+			result = append(result, b.Elements[0].(*Message).Address, b.Elements[1].(*Bundle).Elements[0].(*Message).Address)
+		})
+	}
+
+	expected := []string{"/rig1/fader/1", "/rig1/mute/1", "/rig1/stage/light/1"}
+	if len(result) != len(expected) || result[0] != expected[0] || result[1] != expected[1] || result[2] != expected[2] {
+		t.Errorf("Got %v, expected %v", result, expected)
+	}
+
+	// The packet sent is not modified
+	if address := bundle.Elements[0].(*Message).Address; address != "/fader/1" {
+		t.Errorf("Got %v, expected %v", address, "/fader/1")
+	}
+
+	if err := newPrefixClient(client, "rig1").Send(NewMessage("/fader/1")); err == nil {
+		t.Errorf("Got %v, expected an error", err)
+	}
+}
@@ -0,0 +1,165 @@
+package osc
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+PeerProfile describes the conventions of a kind of OSC device or application, so that device-specific packages can
+configure clients and servers uniformly (see ApplyProfile). Profiles are registered by name with RegisterProfile.
+*/
+type PeerProfile interface {
+	// Name returns the name the profile is registered under, e.g. "qlab".
+	Name() string
+	// Framing returns the framing used over stream transports, or nil for the transport's default.
+	Framing() Framing
+	// Quirks returns the deviations from the OSC specification to accommodate.
+	Quirks() Quirks
+	// Heartbeat returns a packet to be sent periodically to keep a subscription or connection alive, and the interval
+	// between sends. The packet is nil if no heartbeat is needed.
+	Heartbeat() (Packet, time.Duration)
+}
+
+/*
+Quirks lists deviations from the OSC specification made by a kind of peer.
+*/
+type Quirks struct {
+	// AddressCase is how the case of addresses sent by the peer is treated
+	AddressCase AddressCase
+	// NormalizeAddresses is true if addresses sent by the peer may have trailing slashes or empty parts
+	NormalizeAddresses bool
+	// SplitDatagrams is true if the peer sends several length-prefixed packets in one UDP datagram
+	SplitDatagrams bool
+}
+
+/*
+profile is a PeerProfile with fixed values.
+*/
+type profile struct {
+	name      string
+	framing   Framing
+	quirks    Quirks
+	heartbeat Packet
+	interval  time.Duration
+}
+
+/*
+NewProfile creates a PeerProfile. heartbeat may be nil if no heartbeat is needed.
+*/
+func NewProfile(name string, framing Framing, quirks Quirks, heartbeat Packet, interval time.Duration) PeerProfile {
+	return &profile{name: name, framing: framing, quirks: quirks, heartbeat: heartbeat, interval: interval}
+}
+
+func (p *profile) Name() string {
+	return p.name
+}
+
+func (p *profile) Framing() Framing {
+	return p.framing
+}
+
+func (p *profile) Quirks() Quirks {
+	return p.quirks
+}
+
+func (p *profile) Heartbeat() (Packet, time.Duration) {
+	return p.heartbeat, p.interval
+}
+
+var (
+	profilesMu sync.RWMutex
+	profiles   = map[string]PeerProfile{
+		"osc1.0": NewProfile("osc1.0", LengthPrefix, Quirks{}, nil, 0),
+		"osc1.1": NewProfile("osc1.1", SLIP, Quirks{}, nil, 0),
+	}
+)
+
+/*
+RegisterProfile registers a PeerProfile under its name, typically from the init function of a device-specific package.
+An error is returned if a profile with the same name is already registered. The profiles "osc1.0" (length-prefixed
+framing) and "osc1.1" (SLIP framing) are always registered.
+*/
+func RegisterProfile(p PeerProfile) error {
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+
+	if _, ok := profiles[p.Name()]; ok {
+		return fmt.Errorf("A profile named \"%s\" is already registered", p.Name())
+	}
+
+	profiles[p.Name()] = p
+
+	return nil
+}
+
+/*
+LookupProfile returns the profile registered under name.
+*/
+func LookupProfile(name string) (PeerProfile, bool) {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+
+	p, ok := profiles[name]
+	return p, ok
+}
+
+/*
+Profiles returns the names of the registered profiles, in alphabetical order.
+*/
+func Profiles() []string {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+/*
+ApplyProfile configures a client or server for a kind of peer. The profile's framing is applied to stream transports,
+and its quirks to the endpoint's AddressSpace. If endpoint is a Client and the profile has a heartbeat, a KeepAlive is
+returned, which the caller should start once connected; otherwise the KeepAlive is nil. It must be called before
+connecting or listening. For example, with package qlab imported to register its profile:
+
+	profile, _ := osc.LookupProfile("qlab")
+	keepAlive, err := osc.ApplyProfile(client, profile)
+*/
+func ApplyProfile(endpoint interface{}, p PeerProfile) (*KeepAlive, error) {
+	if framing := p.Framing(); framing != nil {
+		if f, ok := endpoint.(interface{ SetFraming(Framing) }); ok {
+			f.SetFraming(framing)
+		}
+	}
+
+	quirks := p.Quirks()
+	if a, ok := endpoint.(interface {
+		SetAddressCase(AddressCase)
+		SetNormalizeAddresses(bool)
+	}); ok {
+		a.SetAddressCase(quirks.AddressCase)
+		a.SetNormalizeAddresses(quirks.NormalizeAddresses)
+	}
+
+	if s, ok := endpoint.(interface{ SetSplitDatagrams(bool) }); ok {
+		s.SetSplitDatagrams(quirks.SplitDatagrams)
+	}
+
+	heartbeat, interval := p.Heartbeat()
+	client, isClient := endpoint.(Client)
+	if heartbeat == nil || !isClient {
+		return nil, nil
+	}
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("Profile \"%s\" has a heartbeat with no interval", p.Name())
+	}
+
+	return NewKeepAlive(client, heartbeat, interval), nil
+}
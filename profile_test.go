@@ -0,0 +1,44 @@
+package osc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyProfile(t *testing.T) {
+	profile := NewProfile("test-console", SLIP, Quirks{AddressCase: LowerCase}, NewMessage("/xremote"), time.Second)
+	if err := RegisterProfile(profile); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterProfile(profile); err == nil {
+		t.Error("Expected an error for a duplicate profile")
+	}
+
+	found, ok := LookupProfile("test-console")
+	if !ok || found != profile {
+		t.Fatalf("Got %v, expected the registered profile", found)
+	}
+
+	client := &TCPClient{}
+	keepAlive, err := ApplyProfile(client, found)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.framingOrDefault(LengthPrefix) != SLIP {
+		t.Error("Expected the profile's framing to be applied")
+	}
+	if client.addressCase != LowerCase {
+		t.Error("Expected the profile's quirks to be applied")
+	}
+	if keepAlive == nil || keepAlive.interval != time.Second {
+		t.Errorf("Got %v, expected a keep-alive", keepAlive)
+	}
+
+	// Servers have no heartbeat
+	server := &UDPServer{}
+	keepAlive, err = ApplyProfile(server, found)
+	if err != nil || keepAlive != nil {
+		t.Errorf("Got %v, %v, expected no keep-alive", keepAlive, err)
+	}
+}
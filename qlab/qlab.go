@@ -0,0 +1,370 @@
+/*
+Package qlab controls QLab workspaces over OSC, using a TCP connection with the SLIP framing QLab expects.
+
+	client, err := qlab.Dial("192.168.1.50", qlab.DefaultPort)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	err = client.SelectWorkspace(ctx, workspaceID, "1234")
+	if err == nil {
+		err = client.StartCue(ctx, "12")
+	}
+
+Requests wait for QLab's reply, which carries a JSON string describing the result. QLab only replies to commands such
+as /go when asked to reply to every message, so Dial sends /alwaysReply 1 when it connects.
+*/
+package qlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dougfinl/go-osc"
+)
+
+// DefaultPort is the port QLab listens on for OSC connections.
+const DefaultPort = 53000
+
+// Profile describes the conventions of QLab, and is registered as "qlab".
+var Profile = osc.NewProfile("qlab", osc.SLIP, osc.Quirks{}, nil, 0)
+
+func init() {
+	osc.RegisterProfile(Profile)
+}
+
+// The logger used when none has been set
+var defaultLogger osc.Logger = log.New(os.Stderr, "qlab: ", log.LstdFlags)
+
+// replyPrefix is prepended by QLab to the address of a request to form the address of its reply.
+const replyPrefix = "/reply"
+
+/*
+Reply is QLab's reply to a request.
+*/
+type Reply struct {
+	// WorkspaceID is the unique ID of the workspace that handled the request, if any
+	WorkspaceID string `json:"workspace_id"`
+	// Address is the address of the request
+	Address string `json:"address"`
+	// Status is "ok" if the request succeeded, or otherwise a description of the failure (e.g. "error", "denied")
+	Status string `json:"status"`
+	// Data is the result of the request, as JSON
+	Data json.RawMessage `json:"data"`
+}
+
+/*
+ParseReply parses a reply message from QLab, whose address is that of the request prefixed with "/reply", and whose
+only argument is a JSON string.
+*/
+func ParseReply(m *osc.Message) (*Reply, error) {
+	if !strings.HasPrefix(m.Address, replyPrefix+"/") {
+		return nil, fmt.Errorf("Message %s is not a QLab reply", m.Address)
+	}
+
+	if len(m.Arguments) != 1 {
+		return nil, fmt.Errorf("QLab reply %s has %d arguments, expected 1", m.Address, len(m.Arguments))
+	}
+
+	s, ok := m.Arguments[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("QLab reply %s does not contain a JSON string", m.Address)
+	}
+
+	reply := &Reply{}
+	err := json.Unmarshal([]byte(s), reply)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.Address == "" {
+		reply.Address = strings.TrimPrefix(m.Address, replyPrefix)
+	}
+
+	return reply, nil
+}
+
+/*
+Err returns an error if the request failed.
+*/
+func (r *Reply) Err() error {
+	if r.Status != "ok" {
+		return fmt.Errorf("QLab request %s failed with status \"%s\"", r.Address, r.Status)
+	}
+
+	return nil
+}
+
+/*
+Workspace describes a workspace open in QLab.
+*/
+type Workspace struct {
+	UniqueID    string `json:"uniqueID"`
+	DisplayName string `json:"displayName"`
+	HasPasscode bool   `json:"hasPasscode"`
+	Version     string `json:"version"`
+}
+
+/*
+Client is a connection to QLab.
+*/
+type Client struct {
+	conn *osc.TCPClient
+
+	mu        sync.Mutex
+	workspace string
+	waiters   map[string][]chan *Reply
+	logger    osc.Logger
+}
+
+/*
+Dial connects to QLab at the given host and port (usually DefaultPort), and asks QLab to reply to every message sent
+on the connection.
+*/
+func Dial(ip string, port int) (*Client, error) {
+	conn, err := osc.NewTCPClient(ip, port)
+	if err != nil {
+		return nil, err
+	}
+
+	tcp := conn.(*osc.TCPClient)
+	_, err = osc.ApplyProfile(tcp, Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{conn: tcp, waiters: make(map[string][]chan *Reply)}
+
+	err = tcp.Handle(replyPrefix+"//*", c.receiveReply)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tcp.Connect()
+	if err != nil {
+		return nil, err
+	}
+
+	// Without this, QLab only replies to requests which return data
+	alwaysReply := osc.NewMessage("/alwaysReply")
+	alwaysReply.AddArgument(int32(1))
+	err = tcp.Send(alwaysReply)
+	if err != nil {
+		tcp.Disconnect()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+/*
+SetLogger sets the Logger used to report malformed replies from QLab. By default, messages are logged to standard
+error.
+*/
+func (c *Client) SetLogger(logger osc.Logger) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.logger = logger
+}
+
+/*
+Close closes the connection to QLab.
+*/
+func (c *Client) Close() error {
+	return c.conn.Disconnect()
+}
+
+/*
+Request sends a message to QLab, and waits for its reply. If a workspace has been selected, address is relative to it
+(e.g. "/go" is sent as "/workspace/{id}/go"), unless it already starts with "/workspace". The reply's status is not
+checked; use Reply.Err.
+*/
+func (c *Client) Request(ctx context.Context, address string, args ...interface{}) (*Reply, error) {
+	address = c.workspaceAddress(address)
+
+	m := osc.NewMessage(address)
+	for _, arg := range args {
+		err := m.AddArgument(arg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Replies are matched to requests by address, in the order they were sent
+	received := make(chan *Reply, 1)
+	c.mu.Lock()
+	c.waiters[address] = append(c.waiters[address], received)
+	c.mu.Unlock()
+
+	err := c.conn.Send(m)
+	if err != nil {
+		c.removeWaiter(address, received)
+		return nil, err
+	}
+
+	select {
+	case reply := <-received:
+		return reply, nil
+	case <-ctx.Done():
+		c.removeWaiter(address, received)
+		return nil, ctx.Err()
+	}
+}
+
+/*
+Workspaces returns the workspaces open in QLab.
+*/
+func (c *Client) Workspaces(ctx context.Context) ([]Workspace, error) {
+	reply, err := c.Request(ctx, "/workspaces")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := reply.Err(); err != nil {
+		return nil, err
+	}
+
+	var workspaces []Workspace
+	err = json.Unmarshal(reply.Data, &workspaces)
+
+	return workspaces, err
+}
+
+/*
+SelectWorkspace connects to the workspace with the given unique ID, using passcode if it is not empty. Later requests
+are sent to this workspace.
+*/
+func (c *Client) SelectWorkspace(ctx context.Context, id string, passcode string) error {
+	var args []interface{}
+	if passcode != "" {
+		args = append(args, passcode)
+	}
+
+	reply, err := c.Request(ctx, "/workspace/"+id+"/connect", args...)
+	if err != nil {
+		return err
+	}
+
+	if err := reply.Err(); err != nil {
+		return err
+	}
+
+	var result string
+	if err := json.Unmarshal(reply.Data, &result); err == nil && result != "ok" {
+		return fmt.Errorf("QLab refused connection to workspace %s: %s", id, result)
+	}
+
+	c.mu.Lock()
+	c.workspace = id
+	c.mu.Unlock()
+
+	return nil
+}
+
+/*
+Go starts the cue at the playhead of the selected workspace, and advances the playhead.
+*/
+func (c *Client) Go(ctx context.Context) error {
+	return c.command(ctx, "/go")
+}
+
+/*
+StartCue starts the cue with the given number.
+*/
+func (c *Client) StartCue(ctx context.Context, number string) error {
+	return c.command(ctx, "/cue/"+number+"/start")
+}
+
+/*
+StopCue stops the cue with the given number.
+*/
+func (c *Client) StopCue(ctx context.Context, number string) error {
+	return c.command(ctx, "/cue/"+number+"/stop")
+}
+
+/*
+Panic stops all cues in the selected workspace, fading them out.
+*/
+func (c *Client) Panic(ctx context.Context) error {
+	return c.command(ctx, "/panic")
+}
+
+func (c *Client) command(ctx context.Context, address string) error {
+	reply, err := c.Request(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	return reply.Err()
+}
+
+func (c *Client) workspaceAddress(address string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.workspace == "" || strings.HasPrefix(address, "/workspace") {
+		return address
+	}
+
+	return "/workspace/" + c.workspace + address
+}
+
+/*
+receiveReply passes a reply from QLab to the oldest request waiting for it.
+*/
+func (c *Client) receiveReply(m *osc.Message) {
+	reply, err := ParseReply(m)
+	if err != nil {
+		c.mu.Lock()
+		logger := c.logger
+		c.mu.Unlock()
+		if logger == nil {
+			logger = defaultLogger
+		}
+
+		logger.Printf("%v", err)
+		return
+	}
+
+	address := strings.TrimPrefix(m.Address, replyPrefix)
+
+	c.mu.Lock()
+	waiters := c.waiters[address]
+	var waiter chan *Reply
+	if len(waiters) > 0 {
+		waiter = waiters[0]
+		c.waiters[address] = waiters[1:]
+		if len(waiters) == 1 {
+			delete(c.waiters, address)
+		}
+	}
+	c.mu.Unlock()
+
+	if waiter != nil {
+		waiter <- reply
+	}
+}
+
+func (c *Client) removeWaiter(address string, waiter chan *Reply) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	waiters := c.waiters[address]
+	for i, w := range waiters {
+		if w == waiter {
+			c.waiters[address] = append(waiters[:i:i], waiters[i+1:]...)
+			break
+		}
+	}
+
+	if len(c.waiters[address]) == 0 {
+		delete(c.waiters, address)
+	}
+}
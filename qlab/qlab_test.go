@@ -0,0 +1,124 @@
+package qlab
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dougfinl/go-osc"
+)
+
+// fakeQLab answers requests in the same way as QLab, only replying to commands after /alwaysReply 1.
+func fakeQLab(t *testing.T) *osc.TCPServer {
+	var alwaysReply int32
+
+	server := &osc.TCPServer{}
+	server.SetLocalAddr("127.0.0.1", 0)
+	server.SetFraming(osc.SLIP)
+
+	server.AddPacketHook(func(data []byte, peer *osc.Peer) bool {
+		m, err := osc.NewMessageFromData(data)
+		if err != nil {
+			return false
+		}
+
+		reply := Reply{Address: m.Address, Status: "ok"}
+		switch m.Address {
+		case "/alwaysReply":
+			if len(m.Arguments) == 1 && m.Arguments[0] == int32(1) {
+				atomic.StoreInt32(&alwaysReply, 1)
+			}
+			return false
+		case "/workspaces":
+			reply.Data, _ = json.Marshal([]Workspace{{UniqueID: "W1", DisplayName: "Show", HasPasscode: true}})
+		case "/workspace/W1/connect":
+			result := "badpass"
+			if len(m.Arguments) == 1 && m.Arguments[0] == "1234" {
+				result = "ok"
+			}
+			reply.WorkspaceID = "W1"
+			reply.Data, _ = json.Marshal(result)
+		case "/workspace/W1/cue/12/start":
+			if atomic.LoadInt32(&alwaysReply) == 0 {
+				return false
+			}
+			reply.WorkspaceID = "W1"
+		default:
+			reply.Status = "error"
+		}
+
+		encoded, _ := json.Marshal(reply)
+		msg := osc.NewMessage("/reply" + m.Address)
+		msg.AddArgument(string(encoded))
+		peer.Reply(msg)
+
+		return false
+	})
+
+	if err := server.StartListening(); err != nil {
+		t.Fatal(err)
+	}
+
+	return server
+}
+
+func TestClient(t *testing.T) {
+	server := fakeQLab(t)
+	defer server.StopListening()
+
+	client, err := Dial("127.0.0.1", server.LocalAddr().(*net.TCPAddr).Port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	workspaces, err := client.Workspaces(ctx)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(workspaces) != 1 || workspaces[0].UniqueID != "W1" || !workspaces[0].HasPasscode {
+		t.Errorf("Got %+v, expected workspace W1", workspaces)
+	}
+
+	if err := client.SelectWorkspace(ctx, "W1", "0000"); err == nil {
+		t.Error("Expected an error for a wrong passcode")
+	}
+
+	if err := client.SelectWorkspace(ctx, "W1", "1234"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.StartCue(ctx, "12"); err != nil {
+		t.Error(err)
+	}
+
+	if err := client.StartCue(ctx, "99"); err == nil {
+		t.Error("Expected an error for a failed request")
+	}
+}
+
+func TestParseReply(t *testing.T) {
+	test1 := osc.NewMessage("/reply/cue/1/name")
+	test1.AddArgument(`{"workspace_id":"W1","address":"/cue/1/name","status":"ok","data":"Intro"}`)
+
+	result1, err1 := ParseReply(test1)
+	if err1 != nil {
+		t.Fatal(err1)
+	}
+
+	var name string
+	json.Unmarshal(result1.Data, &name)
+	if result1.WorkspaceID != "W1" || result1.Address != "/cue/1/name" || name != "Intro" {
+		t.Errorf("Got %+v, expected the reply from workspace W1", result1)
+	}
+
+	test2 := osc.NewMessage("/cue/1/name")
+	if _, err := ParseReply(test2); err == nil {
+		t.Error("Expected an error for a message which is not a reply")
+	}
+}
@@ -0,0 +1,273 @@
+package osc
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dougfinl/go-osc/codec"
+)
+
+/*
+RetentionPolicy selects which undeliverable packets a PersistentQueue keeps.
+*/
+type RetentionPolicy int
+
+const (
+	// RetainNone drops undeliverable packets, returning the send error.
+	RetainNone RetentionPolicy = iota
+	// RetainLatest keeps only the most recent undeliverable packet for each address, e.g. for fader levels.
+	RetainLatest
+	// RetainAll keeps every undeliverable packet, e.g. for cue triggers.
+	RetainAll
+)
+
+type retentionRule struct {
+	pattern string
+	policy  RetentionPolicy
+}
+
+type queuedPacket struct {
+	address string
+	data    []byte
+}
+
+/*
+PersistentQueue wraps a Client for use over unreliable links. Packets that cannot be sent are spooled to a file
+according to the retention policy of their address, and sent in order once the link recovers: on Connect, on Flush, or
+before the next packet sent. The spool survives restarts of the program. It is safe for concurrent use.
+
+A bundle is retained according to the policy of its first message.
+*/
+type PersistentQueue struct {
+	Client
+
+	path string
+
+	mu     sync.Mutex
+	rules  []retentionRule
+	queued []queuedPacket
+}
+
+/*
+NewPersistentQueue creates a PersistentQueue sending with client, and spooling to the file at path. Any packets spooled
+there by a previous run are loaded, to be sent once the link recovers.
+*/
+func NewPersistentQueue(client Client, path string) (*PersistentQueue, error) {
+	q := &PersistentQueue{Client: client, path: path}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return q, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for {
+		data, err := readLengthPrefixed(reader, maxStreamPacketSize)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		q.queued = append(q.queued, queuedPacket{address: packetAddress(data), data: data})
+	}
+
+	return q, nil
+}
+
+/*
+SetRetention sets the retention policy of addresses matching an address pattern. Patterns are checked in the order they
+were set, and the first match applies; addresses matching no pattern use RetainNone. If the pattern is of invalid
+format, an error is returned.
+*/
+func (q *PersistentQueue) SetRetention(addressPattern string, policy RetentionPolicy) error {
+	err := codec.ValidatePattern(addressPattern)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rules = append(q.rules, retentionRule{pattern: addressPattern, policy: policy})
+
+	return nil
+}
+
+/*
+Connect connects the underlying client, then sends any spooled packets.
+*/
+func (q *PersistentQueue) Connect() error {
+	err := q.Client.Connect()
+	if err != nil {
+		return err
+	}
+
+	return q.Flush()
+}
+
+/*
+Send sends any spooled packets, then p. If p cannot be sent, it is spooled according to the retention policy of its
+address, and nil is returned; if the policy is RetainNone, the send error is returned.
+*/
+func (q *PersistentQueue) Send(p Packet) error {
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	err = q.flush()
+	if err == nil {
+		err = q.Client.Send(p)
+		if err == nil {
+			return nil
+		}
+	}
+
+	address := packetAddress(data)
+	policy := q.policy(address)
+	if policy == RetainNone {
+		return err
+	}
+
+	if policy == RetainLatest {
+		kept := q.queued[:0]
+		for _, queued := range q.queued {
+			if queued.address != address {
+				kept = append(kept, queued)
+			}
+		}
+		q.queued = kept
+	}
+
+	q.queued = append(q.queued, queuedPacket{address: address, data: data})
+
+	return q.save()
+}
+
+/*
+SendAt sends p wrapped in a bundle with a time tag of t plus the wrapped client's time tag offset, through the
+queue.
+*/
+func (q *PersistentQueue) SendAt(p Packet, t time.Time) error {
+	return q.Send(newTimedBundle(p, offsetTime(q.Client, t)))
+}
+
+/*
+Flush sends spooled packets in order, stopping at the first that cannot be sent.
+*/
+func (q *PersistentQueue) Flush() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.flush()
+}
+
+func (q *PersistentQueue) flush() error {
+	if len(q.queued) == 0 {
+		return nil
+	}
+
+	var sendErr error
+	sent := 0
+	for _, queued := range q.queued {
+		p, err := decodeLenient(queued.data)
+		if err != nil {
+			// Drop packets which cannot be decoded, so that they do not block the queue
+			sent++
+			continue
+		}
+
+		sendErr = q.Client.Send(p)
+		if sendErr != nil {
+			break
+		}
+		sent++
+	}
+
+	q.queued = q.queued[sent:]
+
+	err := q.save()
+	if sendErr != nil {
+		return sendErr
+	}
+
+	return err
+}
+
+/*
+Pending returns the number of spooled packets.
+*/
+func (q *PersistentQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.queued)
+}
+
+func (q *PersistentQueue) policy(address string) RetentionPolicy {
+	for _, rule := range q.rules {
+		if codec.Match(rule.pattern, address) {
+			return rule.policy
+		}
+	}
+
+	return RetainNone
+}
+
+/*
+save writes the spooled packets to the spool file, replacing it atomically.
+*/
+func (q *PersistentQueue) save() error {
+	var buf bytes.Buffer
+	for _, queued := range q.queued {
+		buf.Write(encodeLengthPrefixed(queued.data))
+	}
+
+	tmp := q.path + ".tmp"
+	err := ioutil.WriteFile(tmp, buf.Bytes(), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, q.path)
+}
+
+/*
+packetAddress returns the address of an encoded message, or of the first message in an encoded bundle. If there is no
+message, an empty string is returned.
+*/
+func packetAddress(data []byte) string {
+	p, err := decodeLenient(data)
+	if err != nil {
+		return ""
+	}
+
+	var address string
+	var find func(p Packet)
+	find = func(p Packet) {
+		Visit(p, func(m *Message) {
+			address = m.Address
+		}, func(b *Bundle) {
+			for _, e := range b.Elements {
+				if address == "" {
+					find(e)
+				}
+			}
+		})
+	}
+	find(p)
+
+	return address
+}
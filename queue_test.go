@@ -0,0 +1,83 @@
+package osc
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyClient is a Client whose link can be taken down.
+type flakyClient struct {
+	testClient
+	down bool
+}
+
+func (c *flakyClient) Send(p Packet) error {
+	if c.down {
+		return errors.New("Link is down")
+	}
+
+	return c.testClient.Send(p)
+}
+
+func (c *flakyClient) SendAt(p Packet, t time.Time) error {
+	return c.Send(newTimedBundle(p, t))
+}
+
+func TestPersistentQueue(t *testing.T) {
+	path := t.TempDir() + "/spool"
+	client := &flakyClient{down: true}
+
+	q, err := NewPersistentQueue(client, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.SetRetention("/fader/*", RetainLatest)
+	q.SetRetention("/cue/*", RetainAll)
+
+	send := func(address string, value float32) error {
+		msg := NewMessage(address)
+		msg.AddArgument(value)
+		return q.Send(msg)
+	}
+
+	send("/fader/1", 0.1)
+	send("/cue/go", 1)
+	send("/fader/1", 0.2)
+	send("/cue/go", 2)
+
+	if err := send("/meter/1", 0.5); err == nil {
+		t.Error("Expected an error sending an unretained message")
+	}
+
+	expected1 := 3
+	if result1 := q.Pending(); result1 != expected1 {
+		t.Errorf("Got %d pending, expected %d", result1, expected1)
+	}
+
+	// The spool survives a restart
+	q, err = NewPersistentQueue(client, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.down = false
+	if err := q.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected2 := []string{"/cue/go", "/fader/1", "/cue/go"}
+	if len(client.sent) != len(expected2) {
+		t.Fatalf("Got %d sent packets, expected %d", len(client.sent), len(expected2))
+	}
+
+	for i, address := range expected2 {
+		if result := client.sent[i].(*Message); result.Address != address {
+			t.Errorf("Got %v, expected %v", result.Address, address)
+		}
+	}
+
+	if latest := client.sent[1].(*Message).Arguments[0]; latest != float32(0.2) {
+		t.Errorf("Got %v, expected the latest fader value 0.2", latest)
+	}
+}
@@ -0,0 +1,50 @@
+package osc
+
+/*
+SetStateReplay sets the address patterns whose current values are sent to each client when it connects, as consoles
+do when a remote attaches, so that the client starts in sync without querying each value. The values are taken from
+the value cache (see SetValueCache), which only holds values accepted by a method, and sent as one message per address, ordered by address, before any packets from
+the client are dispatched (and after it is authenticated, if credentials are required). Without a value cache, nothing
+is sent. It must be set before the server starts listening.
+*/
+func (s *TCPServer) SetStateReplay(addressPatterns ...string) {
+	s.replay = append([]string(nil), addressPatterns...)
+}
+
+/*
+replayState sends the cached values of the addresses matching any of the patterns with send.
+*/
+func (a *AddressSpace) replayState(patterns []string, send func(p Packet) error) error {
+	a.mu.RLock()
+	cache := a.cache
+	addressCase := a.addressCase
+	a.mu.RUnlock()
+
+	if cache == nil {
+		return nil
+	}
+
+	state := cache.Snapshot()
+	for address := range state {
+		matched := false
+		for _, pattern := range patterns {
+			if matchAddress(pattern, address, addressCase) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			delete(state, address)
+		}
+	}
+
+	for _, m := range state.Messages() {
+		err := send(m)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
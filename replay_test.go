@@ -0,0 +1,117 @@
+package osc
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestStateReplay(t *testing.T) {
+	cache := NewParameterSpace()
+	cache.Set("/mixer/fader/2", float32(0.5))
+	cache.Set("/mixer/fader/1", float32(0.25))
+	cache.Set("/lights/dimmer", int32(255))
+
+	server := &TCPServer{}
+	server.SetLocalAddr("127.0.0.1", 0)
+	server.SetValueCache(cache)
+	server.SetStateReplay("/mixer/fader/*")
+
+	err := server.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopListening()
+
+	received := make(chan string, 3)
+	client := &TCPClient{}
+	client.SetAddr("127.0.0.1", server.LocalAddr().(*net.TCPAddr).Port)
+	client.Handle("//*", func(m *Message) {
+		received <- m.String()
+	})
+
+	err = client.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	expected := []string{"Message: /mixer/fader/1 (f)0.25", "Message: /mixer/fader/2 (f)0.5"}
+	for _, e := range expected {
+		select {
+		case result := <-received:
+			if result != e {
+				t.Errorf("Got %v, expected %v", result, e)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Message %v was not received", e)
+		}
+	}
+
+	select {
+	case result := <-received:
+		t.Errorf("Got %v, expected no more messages", result)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestStateReplayRejectedPeer(t *testing.T) {
+	acl, err := ParseACL("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &TCPServer{}
+	server.SetLocalAddr("127.0.0.1", 0)
+	server.SetValueCache(NewParameterSpace())
+	server.SetStateReplay("/mixer/fader/*")
+	server.HandleFrom("/mixer/fader/*", func(*Message) {}, acl)
+
+	send := func(Packet) error { return nil }
+	allowed := newPeer(&net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 9000}, send)
+	rejected := newPeer(&net.UDPAddr{IP: net.IPv4(192, 168, 1, 1), Port: 9000}, send)
+
+	fader1 := NewMessage("/mixer/fader/1")
+	fader1.AddArgument(float32(0.25))
+	server.DispatchFrom(fader1, allowed)
+
+	// A value from a rejected peer must not reach newly connected clients
+	fader2 := NewMessage("/mixer/fader/2")
+	fader2.AddArgument(float32(0.5))
+	server.DispatchFrom(fader2, rejected)
+
+	err = server.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopListening()
+
+	received := make(chan string, 2)
+	client := &TCPClient{}
+	client.SetAddr("127.0.0.1", server.LocalAddr().(*net.TCPAddr).Port)
+	client.Handle("//*", func(m *Message) {
+		received <- m.String()
+	})
+
+	err = client.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	expected1 := "Message: /mixer/fader/1 (f)0.25"
+	select {
+	case result1 := <-received:
+		if result1 != expected1 {
+			t.Errorf("Got %v, expected %v", result1, expected1)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Message %v was not received", expected1)
+	}
+
+	select {
+	case result := <-received:
+		t.Errorf("Got %v, expected no more messages", result)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
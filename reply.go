@@ -0,0 +1,82 @@
+package osc
+
+const (
+	// ReplyAddress is the address of messages sent in reply to a successfully handled message.
+	ReplyAddress = "/reply"
+	// ErrorAddress is the address of messages sent in reply to a message whose handler failed.
+	ErrorAddress = "/error"
+	// ProgressAddress is the address of messages sent to report the progress of a long-running task.
+	ProgressAddress = "/progress"
+)
+
+/*
+ReplyHandleFunc is a function type that handles a Message, returning a value to reply with, or an error.
+*/
+type ReplyHandleFunc func(*Message) (interface{}, error)
+
+/*
+HandleReply adds an OSC method to the AddressSpace whose result is sent back to the sender of the message. If fn
+returns an error, a "/error" message is sent containing the address of the handled message and the error text.
+Otherwise, if fn returns a non-nil value, a "/reply" message is sent containing the address of the handled message
+followed by the value (or, if the value is a []interface{}, each of its elements). If the message was not received from
+a peer that can be replied to, the result is discarded.
+*/
+func (a *AddressSpace) HandleReply(addressPattern string, fn ReplyHandleFunc) error {
+	return a.handlePeer(addressPattern, func(m *Message, peer *Peer) {
+		value, err := fn(m)
+		replyResult(m, peer, value, err)
+	})
+}
+
+/*
+replyResult sends the result of handling m back to peer, as described by HandleReply.
+*/
+func replyResult(m *Message, peer *Peer, value interface{}, err error) {
+	var reply *Message
+	if err != nil {
+		reply = NewErrorReply(m, err)
+	} else if value != nil {
+		reply, err = NewReply(m, value)
+		if err != nil {
+			reply = NewErrorReply(m, err)
+		}
+	}
+
+	if reply != nil && peer != nil {
+		peer.Reply(reply)
+	}
+}
+
+/*
+NewReply creates a "/reply" message in response to m, containing the address of m followed by value (or, if value is a
+[]interface{}, each of its elements).
+*/
+func NewReply(m *Message, value interface{}) (*Message, error) {
+	reply := NewMessage(ReplyAddress)
+	reply.AddArgument(m.Address)
+
+	values, ok := value.([]interface{})
+	if !ok {
+		values = []interface{}{value}
+	}
+
+	for _, v := range values {
+		err := reply.AddArgument(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return reply, nil
+}
+
+/*
+NewErrorReply creates an "/error" message in response to m, containing the address of m followed by the error text.
+*/
+func NewErrorReply(m *Message, err error) *Message {
+	reply := NewMessage(ErrorAddress)
+	reply.AddArgument(m.Address)
+	reply.AddArgument(err.Error())
+
+	return reply
+}
@@ -0,0 +1,79 @@
+package osc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHandleReply(t *testing.T) {
+	var a AddressSpace
+	var replies []*Message
+
+	peer := newPeer(nil, func(p Packet) error {
+		replies = append(replies, p.(*Message))
+		return nil
+	})
+
+	a.HandleReply("/sum", func(m *Message) (interface{}, error) {
+		if len(m.Arguments) != 2 {
+			return nil, errors.New("Expected two arguments")
+		}
+
+		return m.Arguments[0].(int32) + m.Arguments[1].(int32), nil
+	})
+
+	msg1 := NewMessage("/sum")
+	msg1.AddArgument(int32(2))
+	msg1.AddArgument(int32(3))
+	a.DispatchFrom(msg1, peer)
+	a.DispatchFrom(NewMessage("/sum"), peer)
+
+	// Messages without a peer are handled, but not replied to
+	a.Dispatch(msg1)
+
+	if len(replies) != 2 {
+		t.Fatalf("Got %d replies, expected 2", len(replies))
+	}
+
+	expected1 := NewMessage("/reply")
+	expected1.AddArgument("/sum")
+	expected1.AddArgument(int32(5))
+
+	if !replies[0].Equals(expected1) {
+		t.Errorf("Got %v, expected %v", replies[0], expected1)
+	}
+
+	expected2 := NewMessage("/error")
+	expected2.AddArgument("/sum")
+	expected2.AddArgument("Expected two arguments")
+
+	if !replies[1].Equals(expected2) {
+		t.Errorf("Got %v, expected %v", replies[1], expected2)
+	}
+}
+
+func TestHandleEcho(t *testing.T) {
+	var a AddressSpace
+	var replies []*Message
+
+	peer := newPeer(nil, func(p Packet) error {
+		replies = append(replies, p.(*Message))
+		return nil
+	})
+
+	a.HandleEcho(EchoPrefix)
+
+	msg := NewMessage("/ch/1/fader")
+	msg.AddArgument(float32(0.5))
+	a.DispatchFrom(msg, peer)
+	a.DispatchFrom(NewMessage("/echo/ch/1/fader"), peer)
+
+	expected := NewMessage("/echo/ch/1/fader")
+	expected.AddArgument(float32(0.5))
+
+	if len(replies) != 1 {
+		t.Fatalf("Got %d replies, expected 1", len(replies))
+	} else if !replies[0].Equals(expected) {
+		t.Errorf("Got %v, expected %v", replies[0], expected)
+	}
+}
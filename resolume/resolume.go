@@ -0,0 +1,265 @@
+/*
+Package resolume builds and parses the layered OSC addresses used by Resolume Arena and Avenue, as a worked example of
+integrating with VJ software. VDMX can be configured to send and receive the same addresses.
+
+	client, _ := osc.NewUDPClient("192.168.1.60", 7000)
+	client.Connect()
+
+	client.Send(resolume.Layer(3).Clip(2).Connect())
+	client.Send(resolume.Layer(3).Opacity(0.5))
+
+Parameters are sent and received as floats normalized to the range 0 to 1. Normalize and Denormalize convert to and
+from the range shown in the user interface (e.g. 20 to 500 BPM for the tempo).
+*/
+package resolume
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dougfinl/go-osc"
+)
+
+// Ranges of parameters normalized by Resolume
+const (
+	MinTempo = 20.0
+	MaxTempo = 500.0
+)
+
+/*
+Normalize maps value from the range min to max onto the range 0 to 1, clamping values outside the range.
+*/
+func Normalize(value, min, max float64) float32 {
+	n := (value - min) / (max - min)
+	if n < 0 {
+		n = 0
+	} else if n > 1 {
+		n = 1
+	}
+
+	return float32(n)
+}
+
+/*
+Denormalize maps value from the range 0 to 1 onto the range min to max.
+*/
+func Denormalize(value float32, min, max float64) float64 {
+	return min + float64(value)*(max-min)
+}
+
+/*
+LayerRef refers to a layer of the composition. Layers are numbered from 1.
+*/
+type LayerRef struct {
+	Index int
+}
+
+/*
+Layer returns a reference to the layer with the given index.
+*/
+func Layer(index int) LayerRef {
+	return LayerRef{Index: index}
+}
+
+/*
+Address returns the address of the layer, e.g. "/composition/layers/3".
+*/
+func (l LayerRef) Address() string {
+	return fmt.Sprintf("/composition/layers/%d", l.Index)
+}
+
+/*
+Clip returns a reference to a clip in the layer.
+*/
+func (l LayerRef) Clip(index int) ClipRef {
+	return ClipRef{Layer: l.Index, Index: index}
+}
+
+/*
+Opacity returns a message setting the opacity of the layer.
+*/
+func (l LayerRef) Opacity(opacity float32) *osc.Message {
+	return floatMessage(l.Address()+"/video/opacity", opacity)
+}
+
+/*
+Bypass returns a message bypassing (or restoring) the layer.
+*/
+func (l LayerRef) Bypass(bypassed bool) *osc.Message {
+	return boolMessage(l.Address()+"/bypassed", bypassed)
+}
+
+/*
+Clear returns a message disconnecting all clips in the layer.
+*/
+func (l LayerRef) Clear() *osc.Message {
+	return trigger(l.Address() + "/clear")
+}
+
+/*
+ClipRef refers to a clip in a layer. Clips are numbered from 1.
+*/
+type ClipRef struct {
+	Layer int
+	Index int
+}
+
+/*
+Address returns the address of the clip, e.g. "/composition/layers/3/clips/2".
+*/
+func (c ClipRef) Address() string {
+	return fmt.Sprintf("/composition/layers/%d/clips/%d", c.Layer, c.Index)
+}
+
+/*
+Connect returns a message triggering the clip.
+*/
+func (c ClipRef) Connect() *osc.Message {
+	return trigger(c.Address() + "/connect")
+}
+
+/*
+Select returns a message selecting the clip in the user interface.
+*/
+func (c ClipRef) Select() *osc.Message {
+	return trigger(c.Address() + "/select")
+}
+
+/*
+ColumnRef refers to a column of the composition. Columns are numbered from 1.
+*/
+type ColumnRef struct {
+	Index int
+}
+
+/*
+Column returns a reference to the column with the given index.
+*/
+func Column(index int) ColumnRef {
+	return ColumnRef{Index: index}
+}
+
+/*
+Address returns the address of the column, e.g. "/composition/columns/4".
+*/
+func (c ColumnRef) Address() string {
+	return fmt.Sprintf("/composition/columns/%d", c.Index)
+}
+
+/*
+Connect returns a message triggering every clip in the column.
+*/
+func (c ColumnRef) Connect() *osc.Message {
+	return trigger(c.Address() + "/connect")
+}
+
+/*
+Master returns a message setting the master level of the composition.
+*/
+func Master(level float32) *osc.Message {
+	return floatMessage("/composition/master", level)
+}
+
+/*
+Tempo returns a message setting the tempo of the composition in BPM.
+*/
+func Tempo(bpm float64) *osc.Message {
+	return floatMessage("/composition/tempocontroller/tempo", Normalize(bpm, MinTempo, MaxTempo))
+}
+
+/*
+Feedback is a parameter value sent by Resolume, with its target parsed from the address. Layer, Clip and Column are 0
+if the parameter does not belong to one.
+*/
+type Feedback struct {
+	Layer     int
+	Clip      int
+	Column    int
+	Parameter string // the rest of the address, e.g. "video/opacity" or "connect"
+	Value     float32
+}
+
+/*
+ParseFeedback parses a feedback message sent by Resolume. The value of integer and boolean arguments is converted to a
+float.
+*/
+func ParseFeedback(m *osc.Message) (Feedback, error) {
+	parts := strings.Split(strings.TrimPrefix(m.Address, "/"), "/")
+	if len(parts) < 2 || parts[0] != "composition" {
+		return Feedback{}, fmt.Errorf("Address %s is not a Resolume composition address", m.Address)
+	}
+
+	var fb Feedback
+	parts = parts[1:]
+
+	for len(parts) >= 2 {
+		var target *int
+		switch parts[0] {
+		case "layers":
+			target = &fb.Layer
+		case "clips":
+			target = &fb.Clip
+		case "columns":
+			target = &fb.Column
+		}
+
+		if target == nil {
+			break
+		}
+
+		index, err := strconv.Atoi(parts[1])
+		if err != nil || index < 1 {
+			return Feedback{}, fmt.Errorf("Address %s has invalid %s index \"%s\"", m.Address, parts[0], parts[1])
+		}
+
+		*target = index
+		parts = parts[2:]
+	}
+
+	fb.Parameter = strings.Join(parts, "/")
+
+	if len(m.Arguments) > 0 {
+		switch v := m.Arguments[0].(type) {
+		case float32:
+			fb.Value = v
+		case float64:
+			fb.Value = float32(v)
+		case int32:
+			fb.Value = float32(v)
+		case bool:
+			if v {
+				fb.Value = 1
+			}
+		default:
+			return Feedback{}, fmt.Errorf("Argument %v of %s is not a number", v, m.Address)
+		}
+	}
+
+	return fb, nil
+}
+
+func trigger(address string) *osc.Message {
+	m := osc.NewMessage(address)
+	m.AddArgument(int32(1))
+
+	return m
+}
+
+func floatMessage(address string, value float32) *osc.Message {
+	m := osc.NewMessage(address)
+	m.AddArgument(value)
+
+	return m
+}
+
+func boolMessage(address string, value bool) *osc.Message {
+	m := osc.NewMessage(address)
+	if value {
+		m.AddArgument(int32(1))
+	} else {
+		m.AddArgument(int32(0))
+	}
+
+	return m
+}
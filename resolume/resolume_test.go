@@ -0,0 +1,52 @@
+package resolume
+
+import (
+	"testing"
+
+	"github.com/dougfinl/go-osc"
+)
+
+func TestAddresses(t *testing.T) {
+	test1 := Layer(3).Clip(2).Connect()
+	expected1 := "/composition/layers/3/clips/2/connect"
+
+	if test1.Address != expected1 || test1.Arguments[0] != int32(1) {
+		t.Errorf("Got %v, expected %v with argument 1", test1, expected1)
+	}
+
+	test2 := Tempo(260)
+	expected2 := float32(0.5)
+
+	if test2.Arguments[0] != expected2 {
+		t.Errorf("Got %v, expected %v", test2.Arguments[0], expected2)
+	}
+}
+
+func TestParseFeedback(t *testing.T) {
+	test1 := osc.NewMessage("/composition/layers/3/clips/2/connect")
+	test1.AddArgument(int32(1))
+	expected1 := Feedback{Layer: 3, Clip: 2, Parameter: "connect", Value: 1}
+	result1, err1 := ParseFeedback(test1)
+
+	if err1 != nil {
+		t.Error(err1)
+	} else if result1 != expected1 {
+		t.Errorf("Got %+v, expected %+v", result1, expected1)
+	}
+
+	test2 := osc.NewMessage("/composition/layers/1/video/opacity")
+	test2.AddArgument(float32(0.25))
+	expected2 := Feedback{Layer: 1, Parameter: "video/opacity", Value: 0.25}
+	result2, err2 := ParseFeedback(test2)
+
+	if err2 != nil {
+		t.Error(err2)
+	} else if result2 != expected2 {
+		t.Errorf("Got %+v, expected %+v", result2, expected2)
+	}
+
+	test3 := osc.NewMessage("/composition/layers/x/bypassed")
+	if _, err := ParseFeedback(test3); err == nil {
+		t.Error("Expected an error for an invalid layer index")
+	}
+}
@@ -0,0 +1,298 @@
+package osc
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/dougfinl/go-osc/codec"
+)
+
+/*
+Route forwards messages matching an address pattern to a client, optionally changing their address.
+*/
+type Route struct {
+	// AddressPattern selects the messages to forward
+	AddressPattern string
+	// Address replaces the address of forwarded messages, or is empty to keep the original address
+	Address string
+	// Destination is the client that forwarded messages are sent from
+	Destination Client
+	// Transform optionally changes forwarded messages, after any change of address. See ParseTransform.
+	Transform TransformFunc
+}
+
+/*
+modifies returns true if the route changes the messages it forwards.
+*/
+func (r Route) modifies() bool {
+	return r.Address != "" || r.Transform != nil
+}
+
+/*
+Router forwards received OSC messages to other clients according to a set of routes. Messages that a route forwards
+unmodified are sent as the exact bytes that were received, without being decoded and re-encoded, preserving any quirks
+of the original encoding. Bundles are forwarded as bundles with their original time tag, containing only the messages
+routed to each destination; a bundle whose messages are all routed unmodified to a destination is sent there as the
+exact bytes that were received, while other bundles are re-encoded.
+
+A Router is usually attached to a server with AddPacketHook(router.PacketHook), but Route can also be called directly
+with encoded packets from any source. It is safe for concurrent use.
+*/
+type Router struct {
+	mu     sync.RWMutex
+	routes []Route
+}
+
+/*
+NewRouter creates a Router with no routes.
+*/
+func NewRouter() *Router {
+	return &Router{}
+}
+
+/*
+AddRoute adds a route to the Router. If the AddressPattern is of invalid format, or the route has no destination, an
+error is returned.
+*/
+func (r *Router) AddRoute(route Route) error {
+	err := codec.ValidatePattern(route.AddressPattern)
+	if err != nil {
+		return err
+	}
+
+	if route.Destination == nil {
+		return errors.New("Route has no destination")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.routes = append(r.routes, route)
+
+	return nil
+}
+
+/*
+Routes returns the routes held by the Router.
+*/
+func (r *Router) Routes() []Route {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]Route(nil), r.routes...)
+}
+
+/*
+Route forwards an encoded packet according to the Router's routes. If any forward fails, the first error is returned.
+*/
+func (r *Router) Route(data []byte) error {
+	if len(data) > 0 && data[0] == '#' {
+		bundle, err := NewBundleFromData(data)
+		if err != nil {
+			return err
+		}
+
+		return r.routeBundle(bundle, data)
+	}
+
+	msg, err := NewLazyMessage(data)
+	if err != nil {
+		return err
+	}
+
+	return r.forward(msg.Address, msg, msg.Message)
+}
+
+/*
+PacketHook routes a received packet, and allows it to continue to be dispatched locally. It can be passed to
+AddPacketHook.
+*/
+func (r *Router) PacketHook(data []byte, peer *Peer) bool {
+	r.Route(data)
+
+	return true
+}
+
+/*
+routeBundle forwards a bundle to the destination of each route matching any of its messages. Each destination is sent
+a bundle with the original time tag, containing the messages routed to it (and any nested bundles containing such
+messages), so that they are still delivered together. If a destination is sent every message unmodified, the original
+bundle is forwarded whole, as the bytes in data.
+*/
+func (r *Router) routeBundle(bundle *Bundle, data []byte) error {
+	r.mu.RLock()
+	routes := r.routes
+	r.mu.RUnlock()
+
+	var destinations []Client
+	for _, route := range routes {
+		seen := false
+		for _, d := range destinations {
+			if d == route.Destination {
+				seen = true
+				break
+			}
+		}
+
+		if !seen {
+			destinations = append(destinations, route.Destination)
+		}
+	}
+
+	var firstErr error
+
+	for _, destination := range destinations {
+		routed, err := routedBundle(bundle, routes, destination)
+		if routed != nil {
+			var p Packet = routed
+			if routed == bundle {
+				p = &rawBundle{Bundle: bundle, data: data}
+			}
+
+			if sendErr := destination.Send(p); err == nil {
+				err = sendErr
+			}
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+/*
+routedBundle returns the bundle to forward to destination, or nil if none of its messages are routed there. If every
+message is routed there unmodified, bundle itself is returned. Messages that a route fails to transform are left out,
+and the first error is returned.
+*/
+func routedBundle(bundle *Bundle, routes []Route, destination Client) (*Bundle, error) {
+	routed := &Bundle{TimeTag: bundle.TimeTag}
+	unchanged := true
+	var firstErr error
+
+	for _, e := range bundle.Elements {
+		Visit(e, func(m *Message) {
+			forwarded := 0
+			for _, route := range routes {
+				if route.Destination != destination || !codec.Match(route.AddressPattern, m.Address) {
+					continue
+				}
+
+				if !route.modifies() {
+					routed.Elements = append(routed.Elements, m)
+					forwarded++
+					continue
+				}
+
+				unchanged = false
+				modified, err := route.apply(m)
+				if err != nil && firstErr == nil {
+					firstErr = err
+				} else if modified != nil {
+					routed.Elements = append(routed.Elements, modified)
+				}
+			}
+
+			if forwarded != 1 {
+				unchanged = false
+			}
+		}, func(b *Bundle) {
+			nested, err := routedBundle(b, routes, destination)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+
+			if nested != b {
+				unchanged = false
+			}
+
+			if nested != nil {
+				routed.Elements = append(routed.Elements, nested)
+			}
+		})
+	}
+
+	if unchanged {
+		return bundle, firstErr
+	} else if len(routed.Elements) == 0 {
+		return nil, firstErr
+	}
+
+	return routed, firstErr
+}
+
+/*
+forward sends a message to the destination of each matching route. Unmodified messages are sent as p; the decoded
+message is only requested from decode if a route needs to modify it.
+*/
+func (r *Router) forward(address string, p Packet, decode func() (*Message, error)) error {
+	r.mu.RLock()
+	routes := r.routes
+	r.mu.RUnlock()
+
+	var firstErr error
+
+	for _, route := range routes {
+		if !codec.Match(route.AddressPattern, address) {
+			continue
+		}
+
+		var err error
+		if route.modifies() {
+			var m *Message
+			m, err = decode()
+			if err == nil {
+				m, err = route.apply(m)
+			}
+			if err == nil && m != nil {
+				err = route.Destination.Send(m)
+			}
+		} else {
+			err = route.Destination.Send(p)
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+/*
+apply returns a copy of a message with the route's changes applied, or nil if the route's Transform drops it.
+*/
+func (r Route) apply(m *Message) (*Message, error) {
+	forwarded := &Message{Address: m.Address, Arguments: append([]interface{}(nil), m.Arguments...)}
+	if r.Address != "" {
+		forwarded.Address = r.Address
+	}
+
+	if r.Transform != nil {
+		var err error
+		forwarded, err = r.Transform(forwarded)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return forwarded, nil
+}
+
+/*
+rawBundle is a received bundle forwarded unmodified. MarshalBinary returns the bundle exactly as it was received, as
+LazyMessage does for messages.
+*/
+type rawBundle struct {
+	*Bundle
+	data []byte
+}
+
+/*
+MarshalBinary returns the encoded bundle exactly as it was received, without re-encoding it.
+*/
+func (b *rawBundle) MarshalBinary() ([]byte, error) {
+	return b.data, nil
+}
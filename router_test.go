@@ -0,0 +1,175 @@
+package osc
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// testClient is a Client that records the packets it is asked to send.
+type testClient struct {
+	UDPClient
+	sent []Packet
+}
+
+func (c *testClient) Send(p Packet) error {
+	c.sent = append(c.sent, p)
+	return nil
+}
+
+func (c *testClient) SendAt(p Packet, t time.Time) error {
+	return c.Send(c.timedBundle(p, t))
+}
+
+func TestRouter(t *testing.T) {
+	passthrough := &testClient{}
+	renamed := &testClient{}
+
+	router := NewRouter()
+	router.AddRoute(Route{AddressPattern: "/fader/*", Destination: passthrough})
+	router.AddRoute(Route{AddressPattern: "/fader/1", Address: "/ch/01/mix/fader", Destination: renamed})
+
+	msg := NewMessage("/fader/1")
+	msg.AddArgument(float32(0.5))
+	data, _ := msg.MarshalBinary()
+
+	err := router.Route(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(passthrough.sent) != 1 || len(renamed.sent) != 1 {
+		t.Fatalf("Got %d and %d forwarded packets, expected 1 each", len(passthrough.sent), len(renamed.sent))
+	}
+
+	// The unmodified message is forwarded byte-for-byte
+	result1, _ := passthrough.sent[0].MarshalBinary()
+	if !bytes.Equal(result1, data) {
+		t.Errorf("Got %v, expected %v", result1, data)
+	}
+
+	expected2 := NewMessage("/ch/01/mix/fader")
+	expected2.AddArgument(float32(0.5))
+	if result2, ok := renamed.sent[0].(*Message); !ok || !result2.Equals(expected2) {
+		t.Errorf("Got %v, expected %v", renamed.sent[0], expected2)
+	}
+}
+
+func TestRouterBundle(t *testing.T) {
+	faders := &testClient{}
+	renamed := &testClient{}
+	everything := &testClient{}
+
+	router := NewRouter()
+	router.AddRoute(Route{AddressPattern: "/fader/*", Destination: faders})
+	router.AddRoute(Route{AddressPattern: "/fader/1", Address: "/ch/01/mix/fader", Destination: renamed})
+	router.AddRoute(Route{AddressPattern: "/*/1", Destination: everything})
+
+	fader := NewMessage("/fader/1")
+	fader.AddArgument(float32(0.5))
+	mute := NewMessage("/mute/1")
+	mute.AddArgument(int32(1))
+
+	bundle := &Bundle{TimeTag: NewTimeTagFromRaw(1 << 40), Elements: []Packet{fader, mute}}
+	data, _ := bundle.MarshalBinary()
+
+	err := router.Route(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(faders.sent) != 1 || len(renamed.sent) != 1 || len(everything.sent) != 1 {
+		t.Fatalf("Got %d, %d and %d forwarded packets, expected 1 each", len(faders.sent), len(renamed.sent),
+			len(everything.sent))
+	}
+
+	// Each destination is sent a bundle with the original time tag, containing the messages routed to it
+	result1, ok := faders.sent[0].(*Bundle)
+	if !ok || result1.TimeTag != bundle.TimeTag || len(result1.Elements) != 1 ||
+		!result1.Elements[0].(*Message).Equals(fader) {
+		t.Errorf("Got %v, expected a bundle containing %v", faders.sent[0], fader)
+	}
+
+	expected2 := NewMessage("/ch/01/mix/fader")
+	expected2.AddArgument(float32(0.5))
+	result2, ok := renamed.sent[0].(*Bundle)
+	if !ok || result2.TimeTag != bundle.TimeTag || len(result2.Elements) != 1 ||
+		!result2.Elements[0].(*Message).Equals(expected2) {
+		t.Errorf("Got %v, expected a bundle containing %v", renamed.sent[0], expected2)
+	}
+
+	// A bundle forwarded unmodified is sent whole
+	result3, _ := everything.sent[0].MarshalBinary()
+	if !bytes.Equal(result3, data) {
+		t.Errorf("Got %v, expected %v", result3, data)
+	}
+}
+
+func TestRouterBundleRaw(t *testing.T) {
+	destination := &testClient{}
+
+	router := NewRouter()
+	router.AddRoute(Route{AddressPattern: "/data", Destination: destination})
+
+	blob := NewMessage("/data")
+	blob.AddArgument([]byte{1, 2, 3})
+	bundle := &Bundle{TimeTag: NewTimeTagFromRaw(1 << 40), Elements: []Packet{blob}}
+	data, _ := bundle.MarshalBinary()
+
+	// Set the blob's padding byte, which decoders ignore but re-encoding would clear
+	data[len(data)-1] = 0xff
+
+	err := router.Route(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(destination.sent) != 1 {
+		t.Fatalf("Got %d forwarded packets, expected 1", len(destination.sent))
+	}
+
+	result1, _ := destination.sent[0].MarshalBinary()
+	if !bytes.Equal(result1, data) {
+		t.Errorf("Got %v, expected %v", result1, data)
+	}
+}
+
+func TestRouterTransform(t *testing.T) {
+	destination := &testClient{}
+
+	transform, err := ParseTransform("address /ch/01/mix/fader; swap 0 1; scale 0 0 127 0 1; drop 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := NewRouter()
+	router.AddRoute(Route{AddressPattern: "/cc/7", Destination: destination, Transform: transform})
+
+	msg := NewMessage("/cc/7")
+	msg.AddArgument(int32(1))
+	msg.AddArgument(float32(63.5))
+	data, _ := msg.MarshalBinary()
+
+	err = router.Route(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected1 := NewMessage("/ch/01/mix/fader")
+	expected1.AddArgument(float32(0.5))
+	if len(destination.sent) != 1 {
+		t.Fatalf("Got %d forwarded packets, expected 1", len(destination.sent))
+	} else if result1, ok := destination.sent[0].(*Message); !ok || !result1.Equals(expected1) {
+		t.Errorf("Got %v, expected %v", destination.sent[0], expected1)
+	}
+
+	_, err = ParseTransform("scale 0 1 1 0 1")
+	if err == nil {
+		t.Error("Expected an error for an empty input range")
+	}
+
+	_, err = ParseTransform("rotate 0")
+	if err == nil {
+		t.Error("Expected an error for an unknown operation")
+	}
+}
@@ -0,0 +1,139 @@
+package osc
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+/*
+SerialClient provides functionality to exchange OSC packets with a device over a serial line (e.g. a microcontroller
+connected by USB-serial), using SLIP framing as specified by OSC 1.1 unless another is set with SetFraming. The serial
+port itself is opened by the caller using a serial library of their choice. Packets received from the device are
+dispatched to the client's AddressSpace.
+*/
+type SerialClient struct {
+	port      io.ReadWriteCloser
+	transport *StreamTransport
+	mu        sync.Mutex
+	connected bool
+	framingOptions
+	compatOptions
+	timeOffsetOptions
+	sendStats
+
+	AddressSpace
+}
+
+// Compile-time check to ensure SerialClient implements the Client interface.
+var _ Client = &SerialClient{}
+
+/*
+NewSerialClient creates a new serial OSC client communicating over an already opened serial port.
+*/
+func NewSerialClient(port io.ReadWriteCloser) *SerialClient {
+	return &SerialClient{port: port}
+}
+
+/*
+SetAddr is not supported by serial clients, which are bound to the port given to NewSerialClient.
+*/
+func (c *SerialClient) SetAddr(ip string, port int) error {
+	return fmt.Errorf("Serial clients do not have a network address")
+}
+
+/*
+SetLocalAddr is not supported by serial clients, which are bound to the port given to NewSerialClient.
+*/
+func (c *SerialClient) SetLocalAddr(ip string, port int) error {
+	return fmt.Errorf("Serial clients do not have a network address")
+}
+
+/*
+Connect starts reading OSC packets from the serial port.
+*/
+func (c *SerialClient) Connect() error {
+	if c.port == nil {
+		return fmt.Errorf("Client has no serial port")
+	}
+
+	transport := NewStreamTransport(c.port, c.framingOrDefault(SLIP))
+	transport.SetLogger(loggerFunc(c.AddressSpace.logf))
+	transport.onClose = func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		// A later call to Connect may already have replaced the transport
+		if c.transport == transport {
+			c.connected = false
+		}
+	}
+
+	c.mu.Lock()
+	c.transport = transport
+	c.connected = true
+	c.mu.Unlock()
+
+	return transport.Open(func(data []byte) {
+		c.AddressSpace.dispatchData(data, newPeer(nil, c.Send))
+	})
+}
+
+/*
+Disconnect closes the serial port.
+*/
+func (c *SerialClient) Disconnect() error {
+	if !c.IsConnected() {
+		return nil
+	}
+
+	c.mu.Lock()
+	c.connected = false
+	c.mu.Unlock()
+
+	return c.transport.Close()
+}
+
+/*
+IsConnected returns true if the client is reading from the serial port, and it has not been closed.
+*/
+func (c *SerialClient) IsConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.port != nil && c.connected
+}
+
+/*
+Send sends an OSC packet (message or bundle) over the serial port.
+*/
+func (c *SerialClient) Send(p Packet) error {
+	n, err := c.send(p)
+	c.recordSend(n, err)
+
+	return err
+}
+
+func (c *SerialClient) send(p Packet) (int, error) {
+	if !c.IsConnected() {
+		return 0, fmt.Errorf("Client is not connected")
+	}
+
+	data, err := c.marshal(p)
+	if err != nil {
+		return 0, err
+	}
+
+	logWire(wireOut, "serial port", data)
+
+	return c.transport.send(data)
+}
+
+/*
+SendAt sends an OSC packet wrapped in a bundle with a time tag of t plus the client's time tag offset (see
+SetTimeTagOffset), so that the receiver executes it at that time.
+*/
+func (c *SerialClient) SendAt(p Packet, t time.Time) error {
+	return c.Send(c.timedBundle(p, t))
+}
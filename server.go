@@ -1,19 +1,29 @@
 package osc
 
 import (
+	"bufio"
+	"context"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"net"
+	"time"
 )
 
 const udpReadBufSize = 4096
 
+// The size of the buffer for control messages carrying kernel timestamps
+const udpOOBBufSize = 128
+
 /*
 Server provides functionality to receive OSC messages over UDP or TCP.
 */
 type Server interface {
 	SetLocalAddr(ip string, port int) error
 	StartListening() error
+	StopListening() error
+	LocalAddr() net.Addr
+	Ready() <-chan struct{}
 	Handle(addressPattern string, fn MessageHandleFunc) error
 }
 
@@ -21,7 +31,12 @@ type Server interface {
 UDPServer provides functionality to receive OSC messages over UDP.
 */
 type UDPServer struct {
-	localAddr *net.UDPAddr
+	localAddr      *net.UDPAddr
+	conn           *net.UDPConn
+	listening      listenState
+	splitDatagrams bool
+	kernelTime     bool
+	listenOptions
 
 	AddressSpace
 }
@@ -61,54 +76,208 @@ func (s *UDPServer) SetLocalAddr(ip string, port int) error {
 StartListening starts the server listening for OSC packets.
 */
 func (s *UDPServer) StartListening() error {
-	conn, err := net.ListenUDP("udp", s.localAddr)
+	addr, err := s.bindAddress(s.localAddr.String())
+	if err != nil {
+		return err
+	}
+
+	packetConn, err := s.listenConfig().ListenPacket(context.Background(), s.network("udp"), addr)
 	if err != nil {
 		return err
 	}
 
+	conn := packetConn.(*net.UDPConn)
+
+	if s.kernelTime {
+		err = s.enableKernelTimestamps(conn)
+		if err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	s.conn = conn
+
 	// defer conn.Close()
 
+	s.listening.setListening(conn.LocalAddr())
+
 	go s.listen(conn)
 
 	return nil
 }
 
-func (s *UDPServer) listen(conn net.Conn) {
+/*
+StopListening stops the server listening for OSC packets, and cancels any running tasks and scheduled bundles.
+*/
+func (s *UDPServer) StopListening() error {
+	var err error
+	if s.conn != nil {
+		err = s.conn.Close()
+	}
+
+	s.AddressSpace.CancelTasks()
+	s.AddressSpace.CancelScheduled()
+
+	return err
+}
+
+/*
+LocalAddr returns the address the server is bound to. Once listening, this reflects the actual port chosen by the
+operating system (e.g. when port 0 was requested). Before then, the configured local address is returned.
+*/
+func (s *UDPServer) LocalAddr() net.Addr {
+	if addr := s.listening.addr(); addr != nil {
+		return addr
+	}
+
+	if s.localAddr == nil {
+		return nil
+	}
+
+	return s.localAddr
+}
+
+/*
+Ready returns a channel that is closed once the server has bound its socket and is listening for packets.
+*/
+func (s *UDPServer) Ready() <-chan struct{} {
+	return s.listening.readyChan()
+}
+
+func (s *UDPServer) listen(conn *net.UDPConn) {
+	var oob []byte
+	if s.kernelTime {
+		oob = make([]byte, udpOOBBufSize)
+	}
+
 	for {
 		// Read a datagram into the buffer
 		buf := make([]byte, udpReadBufSize)
-		n, err := conn.Read(buf)
+		n, oobn, _, addr, err := conn.ReadMsgUDP(buf, oob)
 		if err != nil {
 			return
 		}
 
-		go s.handleIncomingData(buf[:n])
+		var receivedAt time.Time
+		if oobn > 0 {
+			receivedAt, _ = parseKernelTimestamp(oob[:oobn])
+		}
+
+		go s.handleIncomingData(buf[:n], addr, receivedAt)
 	}
 }
 
 /*
-handleIncomingData attempts to decode and dispatch the incoming OSC packet. If the data is not a valid OSC packet, it is silently ignored.
+sendTo sends an OSC packet from the server's socket to addr.
 */
-func (s *UDPServer) handleIncomingData(data []byte) {
-	p, err := decodePacket(data)
+func (s *UDPServer) sendTo(p Packet, addr *net.UDPAddr) error {
+	data, err := p.MarshalBinary()
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
+	}
+
+	logWire(wireOut, addr, data)
+
+	_, err = s.conn.WriteToUDP(data, addr)
+
+	return err
+}
+
+/*
+handleIncomingData dispatches an incoming datagram, recording its sender so that methods can reply to it. If
+receivedAt is not zero, it is the time the datagram was received according to the kernel.
+*/
+func (s *UDPServer) handleIncomingData(data []byte, addr *net.UDPAddr, receivedAt time.Time) {
+	peer := newPeer(addr, func(p Packet) error {
+		return s.sendTo(p, addr)
+	})
+
+	if !receivedAt.IsZero() {
+		peer.ReceivedAt = receivedAt
+		peer.KernelTimestamp = true
+	}
+
+	if s.splitDatagrams {
+		if packets, ok := splitLengthPrefixed(data); ok {
+			for _, packet := range packets {
+				s.AddressSpace.dispatchData(packet, peer)
+			}
+			return
+		}
+	}
+
+	s.AddressSpace.dispatchData(data, peer)
+}
+
+/*
+SetSplitDatagrams sets whether the server detects datagrams containing several packets, each preceded by its length as
+a 32-bit big-endian integer (as sent by some devices), and dispatches the packets individually. Datagrams which are not
+entirely made up of such packets are dispatched as normal. It must be set before the server starts listening.
+*/
+func (s *UDPServer) SetSplitDatagrams(split bool) {
+	s.splitDatagrams = split
+}
+
+/*
+SetKernelTimestamps sets whether the time each datagram is received is recorded by the kernel (using SO_TIMESTAMPNS),
+and reported in Peer.ReceivedAt, for more precise latency measurements than the time the server reads the datagram.
+It is only supported on Linux; on other platforms StartListening returns an error if it is enabled. It must be set
+before the server starts listening.
+*/
+func (s *UDPServer) SetKernelTimestamps(enable bool) {
+	s.kernelTime = enable
+}
+
+func (s *UDPServer) enableKernelTimestamps(conn *net.UDPConn) error {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	return setKernelTimestamps(raw)
+}
+
+/*
+splitLengthPrefixed splits data made up of length-prefixed packets. It returns false if data does not consist entirely
+of such packets, including if it starts like an ordinary OSC packet.
+*/
+func splitLengthPrefixed(data []byte) ([][]byte, bool) {
+	if len(data) == 0 || data[0] == '/' || data[0] == '#' {
+		return nil, false
 	}
 
-	switch p.(type) {
-	case *Message:
-		s.AddressSpace.Dispatch(p.(*Message))
-	case *Bundle:
-		fmt.Println("ERROR server does not yet handle bundles")
+	var packets [][]byte
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, false
+		}
+
+		size := binary.BigEndian.Uint32(data)
+		data = data[4:]
+		if size == 0 || size%4 != 0 || uint64(size) > uint64(len(data)) {
+			return nil, false
+		}
+
+		packets = append(packets, data[:size])
+		data = data[size:]
 	}
+
+	return packets, true
 }
 
 /*
 TCPServer provides functionality to receive OSC messages over TCP.
 */
 type TCPServer struct {
-	localAddr *net.TCPAddr
+	localAddr   *net.TCPAddr
+	listener    net.Listener
+	credentials CredentialsFunc
+	listening   listenState
+	replay      []string
+	readLimit   int
+	listenOptions
+	framingOptions
 
 	AddressSpace
 }
@@ -148,51 +317,133 @@ func (s *TCPServer) SetLocalAddr(ip string, port int) error {
 StartListening starts the server listening for incoming TCP connections.
 */
 func (s *TCPServer) StartListening() error {
-	listener, err := net.ListenTCP("tcp", s.localAddr)
+	addr, err := s.bindAddress(s.localAddr.String())
+	if err != nil {
+		return err
+	}
+
+	listener, err := s.listenConfig().Listen(context.Background(), s.network("tcp"), addr)
 	if err != nil {
 		return err
 	}
 
-	defer listener.Close()
+	s.listener = listener
+	s.listening.setListening(listener.Addr())
 
 	go s.listen(listener)
 
 	return nil
 }
 
+/*
+StopListening stops the server accepting incoming connections, and cancels any running tasks and scheduled bundles.
+*/
+func (s *TCPServer) StopListening() error {
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+
+	s.AddressSpace.CancelTasks()
+	s.AddressSpace.CancelScheduled()
+
+	return err
+}
+
+/*
+LocalAddr returns the address the server is bound to. Once listening, this reflects the actual port chosen by the
+operating system (e.g. when port 0 was requested). Before then, the configured local address is returned.
+*/
+func (s *TCPServer) LocalAddr() net.Addr {
+	if addr := s.listening.addr(); addr != nil {
+		return addr
+	}
+
+	if s.localAddr == nil {
+		return nil
+	}
+
+	return s.localAddr
+}
+
+/*
+Ready returns a channel that is closed once the server is accepting incoming connections.
+*/
+func (s *TCPServer) Ready() <-chan struct{} {
+	return s.listening.readyChan()
+}
+
+/*
+SetReadLimit sets the size in bytes of the largest packet accepted from each connection, whatever the framing. A
+connection sending a larger packet is closed. The default, or if limit is 0, is 1 MiB. It must be set before listening.
+*/
+func (s *TCPServer) SetReadLimit(limit int) {
+	s.readLimit = limit
+}
+
 func (s *TCPServer) listen(listener net.Listener) {
 	for {
 		conn, err := listener.Accept()
-
-		// Read a datagram into the buffer
-		buf := make([]byte, udpReadBufSize)
-		n, err := conn.Read(buf)
 		if err != nil {
 			return
 		}
 
-		go s.handleIncomingData(buf[:n])
+		go s.serveConn(conn)
 	}
 }
 
 /*
-handleIncomingData attempts to decode and dispatch the incoming OSC packet. If the data is not a valid OSC packet encoded with a packet length header (OSC 1.0), it is silently ignored.
+serveConn reads framed OSC packets from a connection until it is closed, authenticating the remote host first if
+required.
 */
-func (s *TCPServer) handleIncomingData(data []byte) {
-	// First four bytes should be the data length
-	lenP := binary.BigEndian.Uint32(data[:4])
-	fmt.Print(lenP)
+func (s *TCPServer) serveConn(conn net.Conn) {
+	defer conn.Close()
 
-	p, err := decodePacket(data[len(data)-3:])
-	if err != nil {
-		fmt.Println(err)
-		return
+	limit := s.readLimit
+	if limit <= 0 {
+		limit = maxStreamPacketSize
+	}
+
+	reader := bufio.NewReader(conn)
+	framing := limitedFraming{Framing: s.framingOrDefault(LengthPrefix), limit: limit}
+
+	send := func(p Packet) error {
+		data, err := p.MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		logWire(wireOut, conn.RemoteAddr(), data)
+
+		return framing.WritePacket(conn, data)
 	}
 
-	switch p.(type) {
-	case *Message:
-		s.AddressSpace.Dispatch(p.(*Message))
-	case *Bundle:
-		fmt.Println("ERROR server does not yet handle bundles")
+	if s.credentials != nil {
+		err := authenticate(conn, reader, framing, send, s.credentials)
+		if err != nil {
+			s.AddressSpace.logf("%v (from %s)", err, conn.RemoteAddr())
+			return
+		}
+	}
+
+	if len(s.replay) > 0 {
+		err := s.AddressSpace.replayState(s.replay, send)
+		if err != nil {
+			s.AddressSpace.logf("Cannot replay state: %v (to %s)", err, conn.RemoteAddr())
+			return
+		}
+	}
+
+	for {
+		data, err := framing.ReadPacket(reader)
+		if err != nil {
+			// Network errors and the end of the stream only mean that the connection has closed
+			if _, ok := err.(net.Error); !ok && err != io.EOF {
+				s.AddressSpace.logf("%v (from %s)", err, conn.RemoteAddr())
+			}
+			return
+		}
+
+		s.AddressSpace.dispatchData(data, newPeer(conn.RemoteAddr(), send))
 	}
 }
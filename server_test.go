@@ -0,0 +1,213 @@
+package osc
+
+import (
+	"io"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestUDPServerLocalAddr(t *testing.T) {
+	server, err := NewUDPServer("127.0.0.1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = server.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-server.Ready():
+	case <-time.After(time.Second):
+		t.Fatal("Server did not signal readiness")
+	}
+
+	addr, ok := server.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		t.Fatalf("Got %T, expected *net.UDPAddr", server.LocalAddr())
+	} else if addr.Port == 0 {
+		t.Error("Bound port is 0, expected the port chosen by the operating system")
+	}
+}
+
+func TestSplitLengthPrefixed(t *testing.T) {
+	msg1 := NewMessage("/a")
+	msg2 := NewMessage("/b")
+	msg2.AddArgument(int32(2))
+
+	encoded1, _ := msg1.MarshalBinary()
+	encoded2, _ := msg2.MarshalBinary()
+	data1 := encodeLengthPrefixed(encoded1)
+	data2 := encodeLengthPrefixed(encoded2)
+
+	packets, ok := splitLengthPrefixed(append(data1, data2...))
+	if !ok || len(packets) != 2 {
+		t.Fatalf("Got %d packets, expected 2", len(packets))
+	}
+
+	result1, _ := NewMessageFromData(packets[1])
+	if !result1.Equals(msg2) {
+		t.Errorf("Got %v, expected %v", result1, msg2)
+	}
+
+	// An ordinary packet is not split
+	if _, ok := splitLengthPrefixed(encoded2); ok {
+		t.Error("Expected an ordinary packet not to be split")
+	}
+
+	// Nor is one with a length prefix that does not match its data
+	if _, ok := splitLengthPrefixed(data1[:len(data1)-4]); ok {
+		t.Error("Expected a truncated packet not to be split")
+	}
+}
+
+func TestKernelTimestamps(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Kernel timestamps are only supported on Linux")
+	}
+
+	server := &UDPServer{}
+	server.SetLocalAddr("127.0.0.1", 0)
+	server.SetKernelTimestamps(true)
+
+	peers := make(chan *Peer, 1)
+	server.handlePeer("/stamp", func(m *Message, peer *Peer) {
+		peers <- peer
+	})
+
+	if err := server.StartListening(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopListening()
+
+	addr := server.LocalAddr().(*net.UDPAddr)
+	client, _ := NewUDPClient("127.0.0.1", addr.Port)
+	client.Connect()
+	defer client.Disconnect()
+
+	sent := time.Now()
+	client.Send(NewMessage("/stamp"))
+
+	select {
+	case peer := <-peers:
+		if !peer.KernelTimestamp {
+			t.Error("Expected a kernel timestamp")
+		}
+		if delay := peer.ReceivedAt.Sub(sent); delay < -time.Millisecond || delay > time.Second {
+			t.Errorf("Got a receive time %v after sending, expected a small delay", delay)
+		}
+	case <-time.After(time.Second):
+		t.Error("Message was not received")
+	}
+}
+
+func TestUDPServerBundles(t *testing.T) {
+	server, _ := NewUDPServer("127.0.0.1", 0)
+
+	received := make(chan string, 4)
+	server.Handle("/*", func(m *Message) {
+		received <- m.Address
+	})
+
+	if err := server.StartListening(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopListening()
+
+	client, _ := NewUDPClient("127.0.0.1", server.LocalAddr().(*net.UDPAddr).Port)
+	client.Connect()
+	defer client.Disconnect()
+
+	sent := time.Now()
+
+	later := &Bundle{TimeTag: NewTimeTag(sent.Add(100 * time.Millisecond))}
+	later.AddPacket(NewMessage("/later"))
+
+	nested := NewBundle()
+	nested.AddPacket(NewMessage("/nested"))
+
+	bundle := NewBundle()
+	bundle.AddPacket(later)
+	bundle.AddPacket(NewMessage("/now"))
+	bundle.AddPacket(nested)
+	client.Send(bundle)
+
+	expected := []string{"/now", "/nested", "/later"}
+	for i, address := range expected {
+		select {
+		case result := <-received:
+			if result != address {
+				t.Errorf("Got %v, expected %v", result, address)
+			}
+			if elapsed := time.Since(sent); i == 2 && elapsed < 90*time.Millisecond {
+				t.Errorf("Got %v dispatched after %v, expected after its time tag", result, elapsed)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Message %v was not received", address)
+		}
+	}
+}
+
+func TestTCPServerReadLimit(t *testing.T) {
+	server := &TCPServer{}
+	server.SetLocalAddr("127.0.0.1", 0)
+	server.SetFraming(SLIP)
+	server.SetReadLimit(64)
+	logger := &testLogger{}
+	server.SetLogger(logger)
+
+	received := make(chan string, 4)
+	server.Handle("/*", func(m *Message) {
+		received <- m.Address
+	})
+
+	if err := server.StartListening(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopListening()
+
+	conn, err := net.Dial("tcp", server.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Packets are read from the connection until it closes
+	for _, address := range []string{"/first", "/second"} {
+		data, _ := NewMessage(address).MarshalBinary()
+		SLIP.WritePacket(conn, data)
+
+		select {
+		case result := <-received:
+			if result != address {
+				t.Errorf("Got %v, expected %v", result, address)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Message %v was not received", address)
+		}
+	}
+
+	// A packet over the limit closes the connection
+	large := NewMessage("/large")
+	large.AddArgument(make([]byte, 128))
+	data, _ := large.MarshalBinary()
+	SLIP.WritePacket(conn, data)
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Got %v, expected %v", err, io.EOF)
+	}
+
+	if result1 := logger.count(); result1 != 1 {
+		t.Errorf("Got %d log lines, expected 1", result1)
+	}
+
+	select {
+	case result := <-received:
+		t.Errorf("Got %v, expected no message", result)
+	default:
+	}
+}
@@ -0,0 +1,174 @@
+package osc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dougfinl/go-osc/codec"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+/*
+RegisterService adds an OSC method to the AddressSpace for each exported method of svc with a supported signature, in
+the manner of net/rpc. A method named SetFreq is registered at "/prefix/setfreq".
+
+A method's parameters may be any of bool, int, int32, int64, float32, float64, string and []byte; numeric arguments are
+converted to the type of the parameter. It may return nothing, an error, a value, or a value and an error. If it
+returns anything, the result is sent back to the sender of the message in the same way as HandleReply. Messages with
+the wrong number or types of arguments are answered with an "/error" message.
+
+	type Synth struct{}
+	func (s *Synth) SetFreq(f float32) { ... }
+	func (s *Synth) Freq() (float32, error) { ... }
+
+	server.RegisterService("/synth", &Synth{})
+
+Methods with other signatures are ignored. An error is returned if svc has no methods with supported signatures, or the
+prefix is not a valid address, in which case no methods are added.
+*/
+func (a *AddressSpace) RegisterService(prefix string, svc interface{}) error {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	v := reflect.ValueOf(svc)
+	t := v.Type()
+
+	var methods []Method
+	for i := 0; i < t.NumMethod(); i++ {
+		method := t.Method(i)
+		if !supportedServiceMethod(method.Type) {
+			continue
+		}
+
+		pattern := prefix + "/" + strings.ToLower(method.Name)
+		if err := codec.ValidatePattern(pattern); err != nil {
+			return err
+		}
+
+		fn := v.Method(i)
+		handler := func(m *Message, peer *Peer) {
+			callServiceMethod(fn, m, peer)
+		}
+
+		methods = append(methods, Method{
+			AddressPattern: pattern,
+			Function: func(m *Message) {
+				handler(m, nil)
+			},
+			handler: handler,
+		})
+	}
+
+	if len(methods) == 0 {
+		return fmt.Errorf("Type %s has no methods with supported signatures", t)
+	}
+
+	a.addMethods(methods)
+
+	return nil
+}
+
+/*
+supportedServiceMethod returns true if a method (including its receiver) has a signature supported by RegisterService.
+*/
+func supportedServiceMethod(t reflect.Type) bool {
+	for i := 1; i < t.NumIn(); i++ {
+		if !supportedServiceParameter(t.In(i)) {
+			return false
+		}
+	}
+
+	switch t.NumOut() {
+	case 0, 1:
+		return true
+	case 2:
+		return t.Out(1) == errorType
+	}
+
+	return false
+}
+
+func supportedServiceParameter(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	}
+
+	return false
+}
+
+/*
+callServiceMethod calls a service method with the arguments of m, replying to peer with its result, if any.
+*/
+func callServiceMethod(fn reflect.Value, m *Message, peer *Peer) {
+	t := fn.Type()
+
+	args, err := serviceArguments(t, m)
+	if err != nil {
+		replyResult(m, peer, nil, err)
+		return
+	}
+
+	results := fn.Call(args)
+	if len(results) == 0 {
+		return
+	}
+
+	var value interface{}
+	last := results[len(results)-1]
+	if t.Out(len(results)-1) == errorType {
+		if !last.IsNil() {
+			err = last.Interface().(error)
+		}
+		results = results[:len(results)-1]
+	}
+
+	if len(results) > 0 {
+		value = results[0].Interface()
+	}
+
+	replyResult(m, peer, value, err)
+}
+
+/*
+serviceArguments converts the arguments of m to the parameter types of a service method.
+*/
+func serviceArguments(t reflect.Type, m *Message) ([]reflect.Value, error) {
+	if len(m.Arguments) != t.NumIn() {
+		return nil, fmt.Errorf("Expected %d arguments, got %d", t.NumIn(), len(m.Arguments))
+	}
+
+	args := make([]reflect.Value, t.NumIn())
+	for i, arg := range m.Arguments {
+		param := t.In(i)
+
+		if arg == nil {
+			return nil, fmt.Errorf("Argument %d is nil, expected %s", i, param)
+		}
+
+		v := reflect.ValueOf(arg)
+		switch {
+		case v.Type().AssignableTo(param):
+		case isNumericKind(v.Kind()) && isNumericKind(param.Kind()):
+			v = v.Convert(param)
+		default:
+			return nil, fmt.Errorf("Argument %d is %T, expected %s", i, arg, param)
+		}
+
+		args[i] = v
+	}
+
+	return args, nil
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,89 @@
+package osc
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testSynth struct {
+	freq float32
+	name string
+}
+
+func (s *testSynth) SetFreq(f float32) {
+	s.freq = f
+}
+
+func (s *testSynth) Freq() float32 {
+	return s.freq
+}
+
+func (s *testSynth) Rename(name string) error {
+	if name == "" {
+		return errors.New("Name is empty")
+	}
+
+	s.name = name
+	return nil
+}
+
+func (s *testSynth) Unsupported(m map[string]int) {}
+
+func TestRegisterService(t *testing.T) {
+	var a AddressSpace
+	synth := &testSynth{}
+
+	if err := a.RegisterService("/synth/", synth); err != nil {
+		t.Fatal(err)
+	}
+
+	var patterns []string
+	for _, method := range a.Methods() {
+		patterns = append(patterns, method.AddressPattern)
+	}
+
+	expected := []string{"/synth/freq", "/synth/rename", "/synth/setfreq"}
+	if !reflect.DeepEqual(patterns, expected) {
+		t.Errorf("Got %v, expected %v", patterns, expected)
+	}
+
+	var replies []*Message
+	peer := newPeer(nil, func(p Packet) error {
+		replies = append(replies, p.(*Message))
+		return nil
+	})
+
+	// Integer arguments are converted to the parameter type
+	setFreq := NewMessage("/synth/setfreq")
+	setFreq.AddArgument(int32(440))
+	a.DispatchFrom(setFreq, peer)
+
+	a.DispatchFrom(NewMessage("/synth/freq"), peer)
+
+	rename := NewMessage("/synth/rename")
+	rename.AddArgument("")
+	a.DispatchFrom(rename, peer)
+
+	a.DispatchFrom(NewMessage("/synth/setfreq"), peer)
+
+	if synth.freq != 440 {
+		t.Errorf("Got %v, expected 440", synth.freq)
+	}
+
+	if len(replies) != 3 {
+		t.Fatalf("Got %d replies, expected 3", len(replies))
+	}
+
+	if replies[0].Address != ReplyAddress || replies[0].Arguments[1] != float32(440) {
+		t.Errorf("Got %v, expected a reply of 440", replies[0])
+	}
+
+	if replies[1].Address != ErrorAddress || replies[2].Address != ErrorAddress {
+		t.Errorf("Got %v and %v, expected errors", replies[1], replies[2])
+	}
+
+	if err := a.RegisterService("/none", struct{}{}); err == nil {
+		t.Error("Expected an error for a service without methods")
+	}
+}
@@ -0,0 +1,144 @@
+package osc
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/dougfinl/go-osc/codec"
+)
+
+// The address pattern of the method added by ShardedAddressSpace.Attach, matching every address
+const matchAllPattern = "//*"
+
+/*
+ShardedAddressSpace holds a large number of OSC methods (e.g. hundreds of thousands, for a pixel-mapping rig), sharded
+by the first part of their address patterns. Each shard is an AddressSpace with its own lock, so that a message is only
+matched against the methods in its shard, and adding methods to one shard does not block dispatching to others. Methods
+whose first address part contains wildcards are held in a separate shard, which is checked for every message.
+
+A ShardedAddressSpace is attached to a server's AddressSpace with Attach, so that packets received by the server
+(including timed bundles) are dispatched to it. Its zero value is ready to use.
+*/
+type ShardedAddressSpace struct {
+	mu       sync.RWMutex
+	shards   map[string]*AddressSpace
+	wildcard AddressSpace
+}
+
+/*
+Handle adds an OSC method to the shard for its address pattern. If the AddressPattern is of invalid format, an error is
+returned.
+*/
+func (s *ShardedAddressSpace) Handle(addressPattern string, fn MessageHandleFunc) error {
+	return s.HandleFiltered(addressPattern, fn)
+}
+
+/*
+HandleFiltered adds an OSC method to the shard for its address pattern, which is only invoked for matching messages
+accepted by all of the filters. If the AddressPattern is of invalid format, an error is returned.
+*/
+func (s *ShardedAddressSpace) HandleFiltered(addressPattern string, fn MessageHandleFunc, filters ...MessageFilter) error {
+	err := codec.ValidatePattern(addressPattern)
+	if err != nil {
+		return err
+	}
+
+	return s.shardFor(addressPattern).HandleFiltered(addressPattern, fn, filters...)
+}
+
+/*
+shardFor returns the shard holding methods with an address pattern, creating it if necessary.
+*/
+func (s *ShardedAddressSpace) shardFor(addressPattern string) *AddressSpace {
+	key := shardKey(addressPattern)
+	if key == "" || strings.ContainsAny(key, "*?[]{}") || strings.HasPrefix(addressPattern, "//") {
+		return &s.wildcard
+	}
+
+	s.mu.RLock()
+	shard := s.shards[key]
+	s.mu.RUnlock()
+
+	if shard != nil {
+		return shard
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.shards == nil {
+		s.shards = make(map[string]*AddressSpace)
+	}
+
+	shard = s.shards[key]
+	if shard == nil {
+		shard = &AddressSpace{}
+		s.shards[key] = shard
+	}
+
+	return shard
+}
+
+/*
+shardKey returns the first part of an address or address pattern, e.g. "universe1" for "/universe1/pixel/1".
+*/
+func shardKey(address string) string {
+	address = strings.TrimPrefix(address, "/")
+
+	if i := strings.IndexByte(address, '/'); i >= 0 {
+		return address[:i]
+	}
+
+	return address
+}
+
+/*
+Methods returns the OSC methods held in all of the shards.
+*/
+func (s *ShardedAddressSpace) Methods() []Method {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	methods := s.wildcard.Methods()
+	for _, shard := range s.shards {
+		methods = append(methods, shard.Methods()...)
+	}
+
+	return methods
+}
+
+/*
+Dispatch invokes the matching OSC methods for the Message m.
+*/
+func (s *ShardedAddressSpace) Dispatch(m *Message) {
+	s.DispatchFrom(m, nil)
+}
+
+/*
+DispatchFrom is like Dispatch, but also records the peer that sent the message, so that methods can reply to it. The
+peer may be nil if it is unknown.
+*/
+func (s *ShardedAddressSpace) DispatchFrom(m *Message, peer *Peer) {
+	if m == nil {
+		return
+	}
+
+	s.mu.RLock()
+	shard := s.shards[shardKey(m.Address)]
+	s.mu.RUnlock()
+
+	if shard != nil {
+		shard.DispatchFrom(m, peer)
+	}
+
+	s.wildcard.DispatchFrom(m, peer)
+}
+
+/*
+Attach adds a method to an AddressSpace (typically one embedded in a server) which dispatches every message it
+receives to the ShardedAddressSpace. Address normalisation, aliases, packet hooks and bundle scheduling are applied by
+the AddressSpace before messages reach the shards.
+*/
+func (s *ShardedAddressSpace) Attach(a *AddressSpace) error {
+	return a.handlePeer(matchAllPattern, s.DispatchFrom)
+}
@@ -0,0 +1,93 @@
+package osc
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+func TestShardedAddressSpace(t *testing.T) {
+	var s ShardedAddressSpace
+	var pixel, anyPixel, wildcard int32
+
+	s.Handle("/universe1/pixel/1", func(m *Message) { atomic.AddInt32(&pixel, 1) })
+	s.Handle("/universe1/pixel/*", func(m *Message) { atomic.AddInt32(&anyPixel, 1) })
+	s.Handle("/universe*/pixel/1", func(m *Message) { atomic.AddInt32(&wildcard, 1) })
+
+	if err := s.Handle("/universe1/[", func(m *Message) {}); err == nil {
+		t.Error("Expected an error for an invalid address pattern")
+	}
+
+	var server AddressSpace
+	err := s.Attach(&server)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.Dispatch(NewMessage("/universe1/pixel/1"))
+	server.Dispatch(NewMessage("/universe1/pixel/2"))
+	server.Dispatch(NewMessage("/universe2/pixel/1"))
+	server.Dispatch(NewMessage("/universe3/pixel/2"))
+
+	result1 := []int32{pixel, anyPixel, wildcard}
+	expected1 := []int32{1, 2, 2}
+	for i := range expected1 {
+		if result1[i] != expected1[i] {
+			t.Errorf("Got %v, expected %v", result1, expected1)
+			break
+		}
+	}
+
+	result2 := len(s.Methods())
+	expected2 := 3
+	if result2 != expected2 {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+}
+
+// The number of universes and pixels per universe in the dispatch benchmarks
+const (
+	benchmarkUniverses = 1000
+	benchmarkPixels    = 100
+)
+
+func BenchmarkDispatch(b *testing.B) {
+	var a AddressSpace
+	for u := 0; u < benchmarkUniverses; u++ {
+		for p := 0; p < benchmarkPixels; p++ {
+			a.Handle(fmt.Sprintf("/universe%d/pixel/%d", u, p), func(m *Message) {})
+		}
+	}
+
+	benchmarkDispatch(b, a.Dispatch)
+}
+
+func BenchmarkShardedDispatch(b *testing.B) {
+	var s ShardedAddressSpace
+	for u := 0; u < benchmarkUniverses; u++ {
+		for p := 0; p < benchmarkPixels; p++ {
+			s.Handle(fmt.Sprintf("/universe%d/pixel/%d", u, p), func(m *Message) {})
+		}
+	}
+
+	benchmarkDispatch(b, s.Dispatch)
+}
+
+/*
+benchmarkDispatch dispatches messages to addresses spread across the universes from all cores.
+*/
+func benchmarkDispatch(b *testing.B, dispatch func(*Message)) {
+	messages := make([]*Message, benchmarkUniverses)
+	for u := range messages {
+		messages[u] = NewMessage(fmt.Sprintf("/universe%d/pixel/%d", u, u%benchmarkPixels))
+	}
+
+	b.ResetTimer()
+
+	var next uint64
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			dispatch(messages[atomic.AddUint64(&next, 1)%benchmarkUniverses])
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package osc
+
+import (
+	"errors"
+	"io"
+)
+
+const (
+	// SLIP special characters (RFC 1055)
+	slipEnd    = 0xC0
+	slipEsc    = 0xDB
+	slipEscEnd = 0xDC
+	slipEscEsc = 0xDD
+)
+
+var (
+	// Returned when a SLIP frame contains an invalid escape sequence
+	errMalformedSLIP = errors.New("Malformed SLIP escape sequence")
+)
+
+/*
+encodeSLIP frames a packet using double-ended SLIP encoding, as recommended by OSC 1.1 for stream transports.
+*/
+func encodeSLIP(data []byte) []byte {
+	encoded := make([]byte, 0, len(data)+2)
+
+	encoded = append(encoded, slipEnd)
+	for _, b := range data {
+		switch b {
+		case slipEnd:
+			encoded = append(encoded, slipEsc, slipEscEnd)
+		case slipEsc:
+			encoded = append(encoded, slipEsc, slipEscEsc)
+		default:
+			encoded = append(encoded, b)
+		}
+	}
+	encoded = append(encoded, slipEnd)
+
+	return encoded
+}
+
+/*
+readSLIP reads the next SLIP-framed packet from a reader. Empty frames (e.g. between two END characters) are skipped.
+*/
+func readSLIP(reader io.ByteReader) ([]byte, error) {
+	var data []byte
+	escaped := false
+
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		if escaped {
+			switch b {
+			case slipEscEnd:
+				data = append(data, slipEnd)
+			case slipEscEsc:
+				data = append(data, slipEsc)
+			default:
+				return nil, errMalformedSLIP
+			}
+
+			escaped = false
+			continue
+		}
+
+		switch b {
+		case slipEnd:
+			if len(data) > 0 {
+				return data, nil
+			}
+		case slipEsc:
+			escaped = true
+		default:
+			data = append(data, b)
+		}
+	}
+}
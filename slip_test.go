@@ -0,0 +1,59 @@
+package osc
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+)
+
+func TestEncodeSLIP(t *testing.T) {
+	// Plain data is only wrapped in END characters
+	test1 := []byte{'/', 'a', '\x00', '\x00'}
+	expected1 := []byte{'\xC0', '/', 'a', '\x00', '\x00', '\xC0'}
+	result1 := encodeSLIP(test1)
+
+	if !bytes.Equal(result1, expected1) {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	// END and ESC characters in the data are escaped
+	test2 := []byte{'\xC0', '\xDB'}
+	expected2 := []byte{'\xC0', '\xDB', '\xDC', '\xDB', '\xDD', '\xC0'}
+	result2 := encodeSLIP(test2)
+
+	if !bytes.Equal(result2, expected2) {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+}
+
+func TestReadSLIP(t *testing.T) {
+	// Two escaped packets in a row, separated by an empty frame
+	data := []byte{'\xC0', '\xDB', '\xDC', '\xDB', '\xDD', '\xC0', '\xC0', '/', 'a', '\x00', '\x00', '\xC0'}
+	reader := bufio.NewReader(bytes.NewReader(data))
+
+	expected1 := []byte{'\xC0', '\xDB'}
+	result1, err1 := readSLIP(reader)
+
+	if err1 != nil {
+		t.Error(err1)
+	} else if !bytes.Equal(result1, expected1) {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	expected2 := []byte{'/', 'a', '\x00', '\x00'}
+	result2, err2 := readSLIP(reader)
+
+	if err2 != nil {
+		t.Error(err2)
+	} else if !bytes.Equal(result2, expected2) {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+
+	// An invalid escape sequence is an error
+	reader3 := bufio.NewReader(bytes.NewReader([]byte{'\xC0', '\xDB', 'x', '\xC0'}))
+	_, err3 := readSLIP(reader3)
+
+	if err3 == nil {
+		t.Error("Expected an error for a malformed escape sequence")
+	}
+}
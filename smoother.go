@@ -0,0 +1,150 @@
+package osc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+Smoother turns bursty, jittery parameter messages into a steady stream of interpolated values, e.g. to drive DMX or
+audio engines from a wireless control surface. Each received value becomes the target of its address, which is
+approached linearly over the glide time; the current value of every moving parameter is passed to the output function
+at a fixed rate. It is safe for concurrent use.
+*/
+type Smoother struct {
+	interval time.Duration
+	glide    time.Duration
+	output   func(address string, value float32)
+
+	mu     sync.Mutex
+	params map[string]*smoothedParam
+}
+
+type smoothedParam struct {
+	from, to float32
+	start    time.Time
+	emitted  bool
+	last     float32
+}
+
+/*
+NewSmoother creates a Smoother which calls output rate times per second with the interpolated value of each moving
+parameter, reaching each new target value glide after it is received. An error is returned if rate is not positive,
+or is too high to be timed.
+*/
+func NewSmoother(rate float64, glide time.Duration, output func(address string, value float32)) (*Smoother, error) {
+	interval := time.Duration(float64(time.Second) / rate)
+	if rate <= 0 || interval <= 0 {
+		return nil, fmt.Errorf("Invalid smoother rate %v", rate)
+	}
+
+	return &Smoother{
+		interval: interval,
+		glide:    glide,
+		output:   output,
+		params:   make(map[string]*smoothedParam),
+	}, nil
+}
+
+/*
+Handle sets the target of the message's address to its first argument, if it is numeric. It can be passed to Handle.
+*/
+func (s *Smoother) Handle(m *Message) {
+	if len(m.Arguments) == 0 {
+		return
+	}
+
+	value, err := convertNumber(m.Arguments[0], 'f')
+	if err != nil {
+		return
+	}
+
+	s.Set(m.Address, value.(float32))
+}
+
+/*
+Set sets the target value of an address. The first value set for an address is output without interpolation.
+*/
+func (s *Smoother) Set(address string, target float32) {
+	s.SetAt(address, target, time.Now())
+}
+
+/*
+SetAt is like Set, but starts the interpolation at t rather than now.
+*/
+func (s *Smoother) SetAt(address string, target float32, t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	param, ok := s.params[address]
+	if !ok {
+		s.params[address] = &smoothedParam{from: target, to: target, start: t}
+		return
+	}
+
+	param.from = param.valueAt(t, s.glide)
+	param.to = target
+	param.start = t
+}
+
+/*
+valueAt returns the interpolated value of the parameter at t.
+*/
+func (p *smoothedParam) valueAt(t time.Time, glide time.Duration) float32 {
+	elapsed := t.Sub(p.start)
+	if glide <= 0 || elapsed >= glide {
+		return p.to
+	} else if elapsed <= 0 {
+		return p.from
+	}
+
+	progress := float32(elapsed) / float32(glide)
+
+	return p.from + (p.to-p.from)*progress
+}
+
+/*
+Run outputs interpolated values at the Smoother's rate until ctx is done.
+*/
+func (s *Smoother) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			s.Tick(now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+/*
+Tick outputs the value of each parameter that has changed since it was last output, as of t. Run calls Tick at the
+Smoother's rate; it can also be called directly to drive the Smoother from another clock (e.g. an audio callback).
+*/
+func (s *Smoother) Tick(t time.Time) {
+	type update struct {
+		address string
+		value   float32
+	}
+
+	s.mu.Lock()
+	var updates []update
+	for address, param := range s.params {
+		value := param.valueAt(t, s.glide)
+		if !param.emitted || value != param.last {
+			param.emitted = true
+			param.last = value
+			updates = append(updates, update{address, value})
+		}
+	}
+	s.mu.Unlock()
+
+	for _, u := range updates {
+		s.output(u.address, u.value)
+	}
+}
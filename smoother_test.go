@@ -0,0 +1,58 @@
+package osc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSmoother(t *testing.T) {
+	var values []float32
+
+	s, err := NewSmoother(100, 100*time.Millisecond, func(address string, value float32) {
+		values = append(values, value)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	s.SetAt("/fader", 0, start)
+	s.SetAt("/fader", 1, start)
+
+	for _, ms := range []int{0, 50, 100, 150} {
+		s.Tick(start.Add(time.Duration(ms) * time.Millisecond))
+	}
+
+	// Unchanged values are not output again
+	expected := []float32{0, 0.5, 1}
+	if len(values) != len(expected) {
+		t.Fatalf("Got %v, expected %v", values, expected)
+	}
+
+	for i := range expected {
+		if values[i] != expected[i] {
+			t.Errorf("Got %v, expected %v", values, expected)
+			break
+		}
+	}
+
+	// A message sets a new target
+	msg := NewMessage("/fader")
+	msg.AddArgument(int32(0))
+	s.Handle(msg)
+	s.Tick(time.Now().Add(time.Second))
+
+	if result := values[len(values)-1]; result != 0 {
+		t.Errorf("Got %v, expected 0", result)
+	}
+}
+
+func TestSmootherRate(t *testing.T) {
+	output := func(address string, value float32) {}
+
+	for _, rate := range []float64{0, -1, 1e10} {
+		if _, err := NewSmoother(rate, time.Second, output); err == nil {
+			t.Errorf("Expected an error for rate %v", rate)
+		}
+	}
+}
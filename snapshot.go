@@ -0,0 +1,269 @@
+package osc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+/*
+jsonValue is the JSON representation of the arguments of one address in a Snapshot. The type tags are stored
+alongside the values, so that each value can be restored with its original OSC type.
+*/
+type jsonValue struct {
+	Types  string            `json:"types"`
+	Values []json.RawMessage `json:"values"`
+}
+
+/*
+Restore replaces the entire state of the ParameterSpace with a snapshot.
+*/
+func (ps *ParameterSpace) Restore(s Snapshot) {
+	values := make(map[string][]interface{}, len(s))
+	for address, args := range s {
+		values[address] = append([]interface{}(nil), args...)
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	ps.values = values
+}
+
+/*
+SaveSnapshot writes a snapshot to a JSON file.
+*/
+func SaveSnapshot(path string, s Snapshot) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+/*
+LoadSnapshot reads a snapshot from a JSON file written by SaveSnapshot.
+*/
+func LoadSnapshot(path string) (Snapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Snapshot
+	err = json.Unmarshal(data, &s)
+
+	return s, err
+}
+
+/*
+MarshalJSON implements the json.Marshaler interface. Each address maps to an object holding the type tags of its
+arguments (without the leading comma), and their values. Blobs are encoded as base64 strings, and time tags as
+RFC 3339 strings (or "immediate").
+*/
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	encoded := make(map[string]jsonValue, len(s))
+
+	for address, args := range s {
+		msg := Message{Address: address, Arguments: args}
+
+		typeTagString, err := msg.TypeTagString()
+		if err != nil {
+			return nil, err
+		}
+
+		values, err := marshalJSONArguments(args)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded[address] = jsonValue{Types: typeTagString[1:], Values: values}
+	}
+
+	return json.Marshal(encoded)
+}
+
+/*
+UnmarshalJSON implements the json.Unmarshaler interface.
+*/
+func (s *Snapshot) UnmarshalJSON(data []byte) error {
+	var encoded map[string]jsonValue
+
+	err := json.Unmarshal(data, &encoded)
+	if err != nil {
+		return err
+	}
+
+	snapshot := make(Snapshot, len(encoded))
+
+	for address, value := range encoded {
+		args, rest, err := unmarshalJSONArguments(value.Types, value.Values)
+		if err != nil {
+			return fmt.Errorf("%s: %v", address, err)
+		} else if rest != "" {
+			return fmt.Errorf("%s: unexpected \"%s\" in type tags", address, rest)
+		}
+
+		snapshot[address] = args
+	}
+
+	*s = snapshot
+
+	return nil
+}
+
+func marshalJSONArguments(args []interface{}) ([]json.RawMessage, error) {
+	values := make([]json.RawMessage, len(args))
+
+	for i, arg := range args {
+		var value interface{} = arg
+
+		switch v := arg.(type) {
+		case TimeTag:
+			if v.Immediate {
+				value = "immediate"
+			} else {
+				value = v.Time().Format(time.RFC3339Nano)
+			}
+		case Char:
+			value = v.String()
+		case Infinitum:
+			value = nil
+		case []interface{}:
+			elements, err := marshalJSONArguments(v)
+			if err != nil {
+				return nil, err
+			}
+			value = elements
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = encoded
+	}
+
+	return values, nil
+}
+
+/*
+unmarshalJSONArguments decodes values according to the type tags, returning the arguments and any type tags remaining
+after the closing bracket of an array.
+*/
+func unmarshalJSONArguments(typeTags string, values []json.RawMessage) ([]interface{}, string, error) {
+	args := []interface{}{}
+
+	for len(typeTags) > 0 {
+		tag := typeTags[0]
+		typeTags = typeTags[1:]
+
+		if tag == ']' {
+			return args, typeTags, nil
+		}
+
+		if len(values) == 0 {
+			return nil, "", fmt.Errorf("missing value for type tag '%c'", tag)
+		}
+
+		raw := values[0]
+		values = values[1:]
+
+		var arg interface{}
+		var err error
+
+		switch tag {
+		case 'i':
+			var v int32
+			err = json.Unmarshal(raw, &v)
+			arg = v
+		case 'f':
+			var v float32
+			err = json.Unmarshal(raw, &v)
+			arg = v
+		case 's':
+			var v string
+			err = json.Unmarshal(raw, &v)
+			arg = v
+		case 'b':
+			var v []byte
+			err = json.Unmarshal(raw, &v)
+			arg = v
+		case 'T', 'F':
+			var v bool
+			err = json.Unmarshal(raw, &v)
+			arg = v
+		case 'N':
+			arg = nil
+		case 'h':
+			var v int64
+			err = json.Unmarshal(raw, &v)
+			arg = v
+		case 'd':
+			var v float64
+			err = json.Unmarshal(raw, &v)
+			arg = v
+		case 't':
+			var v string
+			err = json.Unmarshal(raw, &v)
+			if err == nil {
+				arg, err = parseJSONTimeTag(v)
+			}
+		case 'c':
+			var v string
+			err = json.Unmarshal(raw, &v)
+			if err == nil && len([]rune(v)) != 1 {
+				err = fmt.Errorf("char \"%s\" is not a single character", v)
+			}
+			if err == nil {
+				arg = Char([]rune(v)[0])
+			}
+		case 'r':
+			var v RGBA
+			err = json.Unmarshal(raw, &v)
+			arg = v
+		case 'm':
+			var v MIDIMessage
+			err = json.Unmarshal(raw, &v)
+			arg = v
+		case 'I':
+			arg = Infinitum{}
+		case '[':
+			var elements []json.RawMessage
+			err = json.Unmarshal(raw, &elements)
+			if err == nil {
+				arg, typeTags, err = unmarshalJSONArguments(typeTags, elements)
+			}
+		default:
+			err = fmt.Errorf("unsupported type tag '%c'", tag)
+		}
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		args = append(args, arg)
+	}
+
+	if len(values) != 0 {
+		return nil, "", fmt.Errorf("more values than type tags")
+	}
+
+	return args, "", nil
+}
+
+func parseJSONTimeTag(s string) (TimeTag, error) {
+	if s == "immediate" {
+		return NewImmediateTimeTag(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return TimeTag{}, err
+	}
+
+	return NewTimeTag(t), nil
+}
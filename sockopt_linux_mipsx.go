@@ -0,0 +1,6 @@
+//go:build linux && (mips || mipsle || mips64 || mips64le)
+
+package osc
+
+// SO_REUSEPORT is missing from package syscall on some Linux architectures
+const soReusePort = 0x200
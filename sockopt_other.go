@@ -0,0 +1,36 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package osc
+
+import (
+	"fmt"
+	"syscall"
+)
+
+/*
+setReuseOptions is not supported on this platform.
+*/
+func setReuseOptions(c syscall.RawConn, reuseAddr, reusePort bool) error {
+	return fmt.Errorf("Socket reuse options are not supported on this platform")
+}
+
+/*
+setTrafficClass is not supported on this platform.
+*/
+func setTrafficClass(c syscall.RawConn, ipv6 bool, tos int) error {
+	return fmt.Errorf("Setting the IP traffic class is not supported on this platform")
+}
+
+/*
+setMulticastTTL is not supported on this platform.
+*/
+func setMulticastTTL(c syscall.RawConn, ipv6 bool, ttl int) error {
+	return fmt.Errorf("Setting the multicast TTL is not supported on this platform")
+}
+
+/*
+setMulticastLoopback is not supported on this platform.
+*/
+func setMulticastLoopback(c syscall.RawConn, ipv6 bool, loop bool) error {
+	return fmt.Errorf("Setting multicast loopback is not supported on this platform")
+}
@@ -0,0 +1,97 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package osc
+
+import (
+	"syscall"
+)
+
+/*
+setReuseOptions sets SO_REUSEADDR and/or SO_REUSEPORT on a socket before it is bound.
+*/
+func setReuseOptions(c syscall.RawConn, reuseAddr, reusePort bool) error {
+	var sockErr error
+
+	err := c.Control(func(fd uintptr) {
+		if reuseAddr {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1)
+			if sockErr != nil {
+				return
+			}
+		}
+
+		if reusePort {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, soReusePort, 1)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return sockErr
+}
+
+/*
+setTrafficClass sets the IPv4 TOS byte or IPv6 traffic class of a socket.
+*/
+func setTrafficClass(c syscall.RawConn, ipv6 bool, tos int) error {
+	var sockErr error
+
+	err := c.Control(func(fd uintptr) {
+		if ipv6 {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_TCLASS, tos)
+		} else {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IP, syscall.IP_TOS, tos)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return sockErr
+}
+
+/*
+setMulticastTTL sets the IPv4 multicast TTL or IPv6 multicast hop limit of a socket.
+*/
+func setMulticastTTL(c syscall.RawConn, ipv6 bool, ttl int) error {
+	var sockErr error
+
+	err := c.Control(func(fd uintptr) {
+		if ipv6 {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_MULTICAST_HOPS, ttl)
+		} else {
+			sockErr = syscall.SetsockoptByte(int(fd), syscall.IPPROTO_IP, syscall.IP_MULTICAST_TTL, byte(ttl))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return sockErr
+}
+
+/*
+setMulticastLoopback enables or disables local delivery of multicast packets sent from a socket.
+*/
+func setMulticastLoopback(c syscall.RawConn, ipv6 bool, loop bool) error {
+	var value int
+	if loop {
+		value = 1
+	}
+
+	var sockErr error
+
+	err := c.Control(func(fd uintptr) {
+		if ipv6 {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_IPV6, syscall.IPV6_MULTICAST_LOOP, value)
+		} else {
+			sockErr = syscall.SetsockoptByte(int(fd), syscall.IPPROTO_IP, syscall.IP_MULTICAST_LOOP, byte(value))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return sockErr
+}
@@ -0,0 +1,80 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package osc
+
+import (
+	"syscall"
+	"testing"
+)
+
+// getsockoptInt reads an integer socket option from a connection.
+func getsockoptInt(t *testing.T, conn syscall.Conn, level, opt int) int {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var value int
+	var sockErr error
+	err = rawConn.Control(func(fd uintptr) {
+		value, sockErr = syscall.GetsockoptInt(int(fd), level, opt)
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if sockErr != nil {
+		t.Fatal(sockErr)
+	}
+
+	return value
+}
+
+func TestDSCP(t *testing.T) {
+	client := &UDPClient{}
+	client.SetAddr("127.0.0.1", 9)
+
+	err := client.SetDSCP(46)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	result1 := getsockoptInt(t, client.conn, syscall.IPPROTO_IP, syscall.IP_TOS)
+	expected1 := 46 << 2
+	if result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+}
+
+func TestMulticastOptions(t *testing.T) {
+	client := &UDPClient{}
+	client.SetAddr("239.255.0.1", 9000)
+
+	err := client.SetMulticastTTL(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetMulticastLoopback(false)
+
+	err = client.Connect()
+	if err != nil {
+		t.Skip("Cannot connect to a multicast group: ", err)
+	}
+	defer client.Disconnect()
+
+	result1 := getsockoptInt(t, client.conn, syscall.IPPROTO_IP, syscall.IP_MULTICAST_TTL)
+	expected1 := 4
+	if result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	result2 := getsockoptInt(t, client.conn, syscall.IPPROTO_IP, syscall.IP_MULTICAST_LOOP)
+	expected2 := 0
+	if result2 != expected2 {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+}
@@ -0,0 +1,70 @@
+package osc
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/*
+ClientStats summarises the packets sent by a client.
+*/
+type ClientStats struct {
+	// PacketsSent is the number of packets sent successfully
+	PacketsSent uint64
+	// BytesSent is the number of bytes written to the transport, including any framing
+	BytesSent uint64
+	// Errors is the number of sends that failed
+	Errors uint64
+	// LastError is the error returned by the most recent failed send, or nil if no send has failed
+	LastError error
+	// LastSend is the time of the most recent successful send
+	LastSend time.Time
+}
+
+/*
+sendStats records the outcome of each send made by a client.
+*/
+type sendStats struct {
+	mu      sync.Mutex
+	stats   ClientStats
+	failing int32
+}
+
+/*
+recordSend records a send of n bytes, which failed if err is not nil.
+*/
+func (s *sendStats) recordSend(n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		s.stats.Errors++
+		s.stats.LastError = err
+		atomic.StoreInt32(&s.failing, 1)
+		return
+	}
+
+	s.stats.PacketsSent++
+	s.stats.BytesSent += uint64(n)
+	s.stats.LastSend = time.Now()
+	atomic.StoreInt32(&s.failing, 0)
+}
+
+/*
+Stats returns a snapshot of the client's send statistics.
+*/
+func (s *sendStats) Stats() ClientStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stats
+}
+
+/*
+Healthy returns false if the client's most recent send failed, and true otherwise. It is cheap enough to poll
+frequently, e.g. to drive a link status indicator.
+*/
+func (s *sendStats) Healthy() bool {
+	return atomic.LoadInt32(&s.failing) == 0
+}
@@ -0,0 +1,36 @@
+package osc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSendStats(t *testing.T) {
+	var s sendStats
+
+	if !s.Healthy() {
+		t.Error("A client that has not sent anything should be healthy")
+	}
+
+	s.recordSend(16, nil)
+	s.recordSend(0, errors.New("Network is unreachable"))
+
+	if s.Healthy() {
+		t.Error("A client whose last send failed should not be healthy")
+	}
+
+	s.recordSend(8, nil)
+	stats := s.Stats()
+
+	if !s.Healthy() {
+		t.Error("A client whose last send succeeded should be healthy")
+	}
+
+	if stats.PacketsSent != 2 || stats.BytesSent != 24 || stats.Errors != 1 {
+		t.Errorf("Got %+v, expected 2 packets, 24 bytes and 1 error", stats)
+	}
+
+	if stats.LastError == nil || stats.LastSend.IsZero() {
+		t.Errorf("Got %+v, expected the last error and send time to be set", stats)
+	}
+}
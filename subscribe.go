@@ -0,0 +1,55 @@
+package osc
+
+import (
+	"context"
+	"sync"
+)
+
+// The number of messages buffered by a subscription before further messages are dropped
+const subscriptionBufSize = 64
+
+/*
+SubscribeContext returns a channel receiving the messages dispatched to addresses matching an address pattern, until
+ctx is done, when the subscription is removed and the channel closed. This makes consuming a stream of responses (e.g.
+on a TCPClient) composable with the rest of a program. If the channel's buffer is full, further messages are dropped
+rather than blocking dispatch. If the AddressPattern is of invalid format, an error is returned.
+*/
+func (a *AddressSpace) SubscribeContext(ctx context.Context, addressPattern string) (<-chan *Message, error) {
+	ch := make(chan *Message, subscriptionBufSize)
+
+	var mu sync.Mutex
+	closed := false
+
+	id, err := a.addMethodID(Method{
+		AddressPattern: addressPattern,
+		Function: func(m *Message) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if closed {
+				return
+			}
+
+			select {
+			case ch <- m.Clone():
+			default:
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		a.removeMethod(id)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		closed = true
+		close(ch)
+	}()
+
+	return ch, nil
+}
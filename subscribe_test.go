@@ -0,0 +1,44 @@
+package osc
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSubscribeContext(t *testing.T) {
+	var a AddressSpace
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := a.SubscribeContext(ctx, "/meters/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.Dispatch(NewMessage("/meters/1"))
+	a.Dispatch(NewMessage("/faders/1"))
+
+	select {
+	case m := <-ch:
+		if m.Address != "/meters/1" {
+			t.Errorf("Got %v, expected /meters/1", m.Address)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Subscribed message was not received")
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("Got a message, expected the channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Channel was not closed when the context ended")
+	}
+
+	if len(a.Methods()) != 0 {
+		t.Errorf("Got %d methods, expected the subscription to be removed", len(a.Methods()))
+	}
+}
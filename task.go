@@ -0,0 +1,157 @@
+package osc
+
+import (
+	"context"
+	"sync"
+)
+
+// The maximum number of tasks run concurrently when not set with SetMaxTasks
+const defaultMaxTasks = 16
+
+/*
+ProgressFunc sends a "/progress" message back to the sender of the message being handled by a task, containing the
+address of the message followed by args.
+*/
+type ProgressFunc func(args ...interface{}) error
+
+/*
+TaskHandleFunc is a function type that handles a Message in a long-running task. It should return promptly once ctx
+is cancelled, which happens when the server stops listening (or CancelTasks is called). The task's result is sent back
+to the sender as described by HandleReply.
+*/
+type TaskHandleFunc func(ctx context.Context, m *Message, progress ProgressFunc) (interface{}, error)
+
+/*
+taskPool runs tasks on a limited number of goroutines, and allows them to be cancelled together.
+*/
+type taskPool struct {
+	mu       sync.Mutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	slots    chan struct{}
+	maxTasks int
+	stopped  bool
+	running  sync.WaitGroup
+}
+
+/*
+start returns the context and slots of the pool, creating them if necessary. The lock must be held.
+*/
+func (p *taskPool) start() (context.Context, chan struct{}) {
+	if p.ctx == nil {
+		p.ctx, p.cancel = context.WithCancel(context.Background())
+	}
+
+	if p.slots == nil {
+		if p.maxTasks <= 0 {
+			p.maxTasks = defaultMaxTasks
+		}
+		p.slots = make(chan struct{}, p.maxTasks)
+	}
+
+	return p.ctx, p.slots
+}
+
+/*
+run runs fn on the pool once a slot is free. If the pool is cancelled before a slot becomes free, or is being stopped,
+fn is run with the cancelled context straight away, so it can report its cancellation.
+*/
+func (p *taskPool) run(fn func(ctx context.Context)) {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+
+		// The task must not be added to those being waited for by stop
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		go fn(ctx)
+
+		return
+	}
+
+	ctx, slots := p.start()
+	p.running.Add(1)
+	p.mu.Unlock()
+
+	go func() {
+		defer p.running.Done()
+
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+		case <-ctx.Done():
+		}
+
+		fn(ctx)
+	}()
+}
+
+/*
+stop cancels the context of all running and waiting tasks, and waits for them to return. Tasks started afterwards
+run with a new context.
+*/
+func (p *taskPool) stop() {
+	p.mu.Lock()
+	cancel := p.cancel
+	p.ctx, p.cancel = nil, nil
+	p.stopped = true
+	p.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	p.running.Wait()
+
+	p.mu.Lock()
+	p.stopped = false
+	p.mu.Unlock()
+}
+
+/*
+HandleTask adds an OSC method to the AddressSpace that handles messages in long-running tasks, so as not to hold up
+the dispatch of other messages. At most SetMaxTasks tasks run at once; further tasks wait for a running one to finish.
+*/
+func (a *AddressSpace) HandleTask(addressPattern string, fn TaskHandleFunc) error {
+	return a.handlePeer(addressPattern, func(m *Message, peer *Peer) {
+		// The task outlives the dispatch of the message, which may be pooled
+		m = m.Clone()
+
+		progress := func(args ...interface{}) error {
+			report := NewMessage(ProgressAddress)
+			report.AddArgument(m.Address)
+
+			for _, arg := range args {
+				err := report.AddArgument(arg)
+				if err != nil {
+					return err
+				}
+			}
+
+			return peer.Reply(report)
+		}
+
+		a.tasks.run(func(ctx context.Context) {
+			value, err := fn(ctx, m, progress)
+			replyResult(m, peer, value, err)
+		})
+	})
+}
+
+/*
+SetMaxTasks sets the maximum number of tasks started by HandleTask methods that may run at once. It must be called
+before any tasks are started.
+*/
+func (a *AddressSpace) SetMaxTasks(n int) {
+	a.tasks.mu.Lock()
+	defer a.tasks.mu.Unlock()
+
+	a.tasks.maxTasks = n
+}
+
+/*
+CancelTasks cancels the context of all running tasks started by HandleTask methods, and waits for them to return.
+*/
+func (a *AddressSpace) CancelTasks() {
+	a.tasks.stop()
+}
@@ -0,0 +1,76 @@
+package osc
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestHandleTask(t *testing.T) {
+	var a AddressSpace
+	var mu sync.Mutex
+	var replies []*Message
+
+	peer := newPeer(nil, func(p Packet) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		replies = append(replies, p.(*Message))
+		return nil
+	})
+
+	started := make(chan struct{})
+	a.HandleTask("/render", func(ctx context.Context, m *Message, progress ProgressFunc) (interface{}, error) {
+		progress(float32(0.5))
+		close(started)
+
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	a.DispatchFrom(NewMessage("/render"), peer)
+	<-started
+	a.CancelTasks()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(replies) != 2 {
+		t.Fatalf("Got %d replies, expected 2", len(replies))
+	}
+
+	if replies[0].Address != "/progress" || replies[0].Arguments[1] != float32(0.5) {
+		t.Errorf("Got %v, expected a progress report", replies[0])
+	}
+
+	if replies[1].Address != "/error" {
+		t.Errorf("Got %v, expected an error reply", replies[1])
+	}
+}
+
+func TestCancelTasksWhileRunning(t *testing.T) {
+	var a AddressSpace
+	a.HandleTask("/render", func(ctx context.Context, m *Message, progress ProgressFunc) (interface{}, error) {
+		return nil, nil
+	})
+
+	// Tasks started while the pool is being stopped must not race with waiting for the running tasks
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				a.Dispatch(NewMessage("/render"))
+			}
+		}()
+	}
+
+	for i := 0; i < 100; i++ {
+		a.CancelTasks()
+	}
+
+	wg.Wait()
+	a.CancelTasks()
+}
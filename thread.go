@@ -0,0 +1,110 @@
+package osc
+
+import (
+	"bytes"
+	"runtime"
+)
+
+/*
+DispatchMode determines which goroutines an AddressSpace invokes its handlers on.
+*/
+type DispatchMode int
+
+const (
+	// ConcurrentDispatch invokes handlers on the goroutine dispatching each message, so handlers may run concurrently.
+	// This is the default.
+	ConcurrentDispatch DispatchMode = iota
+	// SingleThreadedDispatch invokes all handlers on one dedicated goroutine, one message at a time.
+	SingleThreadedDispatch
+	// LockedThreadDispatch is like SingleThreadedDispatch, but also locks the dedicated goroutine to its OS thread, for
+	// handlers driving thread-affine libraries (e.g. OpenGL, or some audio APIs).
+	LockedThreadDispatch
+)
+
+/*
+SetDispatchMode sets which goroutines handlers are invoked on. In the single-threaded modes, dispatching a message
+blocks until its handlers have run on the dedicated goroutine; messages dispatched by a handler are handled
+immediately, on the same goroutine.
+*/
+func (a *AddressSpace) SetDispatchMode(mode DispatchMode) {
+	var thread *dispatchThread
+	if mode != ConcurrentDispatch {
+		thread = newDispatchThread(mode == LockedThreadDispatch)
+	}
+
+	a.mu.Lock()
+	old := a.thread
+	a.thread = thread
+	a.mu.Unlock()
+
+	if old != nil {
+		old.stop()
+	}
+}
+
+/*
+dispatchThread runs functions one at a time on a dedicated goroutine.
+*/
+type dispatchThread struct {
+	calls     chan func()
+	quit      chan struct{}
+	goroutine []byte
+}
+
+func newDispatchThread(lockOSThread bool) *dispatchThread {
+	d := &dispatchThread{calls: make(chan func()), quit: make(chan struct{})}
+
+	started := make(chan struct{})
+	go func() {
+		if lockOSThread {
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+		}
+
+		d.goroutine = currentGoroutine()
+		close(started)
+
+		for {
+			select {
+			case fn := <-d.calls:
+				fn()
+			case <-d.quit:
+				return
+			}
+		}
+	}()
+	<-started
+
+	return d
+}
+
+/*
+run calls fn on the dedicated goroutine, and waits for it to return. If called from the dedicated goroutine itself, fn
+is called immediately. Once the thread has been stopped, fn is called on the calling goroutine.
+*/
+func (d *dispatchThread) run(fn func()) {
+	if bytes.Equal(currentGoroutine(), d.goroutine) {
+		fn()
+		return
+	}
+
+	done := make(chan struct{})
+	call := func() {
+		defer close(done)
+		fn()
+	}
+
+	select {
+	case d.calls <- call:
+		<-done
+	case <-d.quit:
+		fn()
+	}
+}
+
+/*
+stop ends the dedicated goroutine once the function it is running has returned.
+*/
+func (d *dispatchThread) stop() {
+	close(d.quit)
+}
@@ -0,0 +1,120 @@
+package osc
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+TimecodeSource is an external source of show timecode, such as an LTC decoder or a MIDI timecode receiver.
+*/
+type TimecodeSource interface {
+	// Position returns the current timecode, as the time since 00:00:00:00.
+	Position() time.Duration
+}
+
+/*
+TimecodeClock is a Clock driven by a TimecodeSource, so that time-tagged bundles (see AddressSpace.SetClock) and cues
+(see CueList.SetClock) lock to show timecode rather than the wall clock: they follow the timecode when it stops,
+chases, or is played at a different speed. Its time is origin plus the timecode position, so a bundle with a time tag
+of origin plus 1 hour is dispatched at timecode 01:00:00:00.
+
+Timers are checked against the timecode each time Update is called, either by Run, or directly by a source which
+reports each frame. Timers passed by a jump forward are called in order of their due time; after a jump backward, timers
+not yet called wait until the timecode reaches them again.
+*/
+type TimecodeClock struct {
+	source TimecodeSource
+	origin time.Time
+
+	mu     sync.Mutex
+	timers []*timecodeTimer
+}
+
+type timecodeTimer struct {
+	clock *TimecodeClock
+	due   time.Duration
+	fn    func()
+}
+
+/*
+NewTimecodeClock creates a TimecodeClock whose time is origin plus the position of source.
+*/
+func NewTimecodeClock(source TimecodeSource, origin time.Time) *TimecodeClock {
+	return &TimecodeClock{source: source, origin: origin}
+}
+
+/*
+Now returns origin plus the current timecode position.
+*/
+func (c *TimecodeClock) Now() time.Time {
+	return c.origin.Add(c.source.Position())
+}
+
+/*
+AfterFunc calls fn once the timecode has advanced by d from its current position.
+*/
+func (c *TimecodeClock) AfterFunc(d time.Duration, fn func()) Timer {
+	t := &timecodeTimer{clock: c, due: c.source.Position() + d, fn: fn}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.timers = append(c.timers, t)
+
+	return t
+}
+
+/*
+Update calls the timers which are due at the current timecode position, in order of their due time.
+*/
+func (c *TimecodeClock) Update() {
+	position := c.source.Position()
+
+	c.mu.Lock()
+	sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].due < c.timers[j].due })
+
+	n := sort.Search(len(c.timers), func(i int) bool { return c.timers[i].due > position })
+	due := c.timers[:n:n]
+	c.timers = append([]*timecodeTimer(nil), c.timers[n:]...)
+	c.mu.Unlock()
+
+	for _, t := range due {
+		t.fn()
+	}
+}
+
+/*
+Run calls Update every interval (e.g. once per timecode frame) until ctx is done.
+*/
+func (c *TimecodeClock) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.Update()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (t *timecodeTimer) Stop() bool {
+	c := t.clock
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, pending := range c.timers {
+		if pending == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,79 @@
+package osc
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testTimecode is a TimecodeSource whose position is set by the test.
+type testTimecode struct {
+	mu       sync.Mutex
+	position time.Duration
+}
+
+func (tc *testTimecode) Position() time.Duration {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	return tc.position
+}
+
+func (tc *testTimecode) set(position time.Duration) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.position = position
+}
+
+func TestTimecodeClock(t *testing.T) {
+	origin := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := &testTimecode{}
+	clock := NewTimecodeClock(source, origin)
+
+	var a AddressSpace
+	var received []string
+	a.SetClock(clock)
+	a.Handle("/*", func(m *Message) {
+		received = append(received, m.Address)
+	})
+
+	for i, address := range []string{"/intro", "/verse", "/chorus"} {
+		bundle := NewBundle()
+		bundle.TimeTag = NewTimeTag(origin.Add(time.Duration(i+1) * 10 * time.Second))
+		bundle.AddPacket(NewMessage(address))
+		a.DispatchPacket(bundle)
+	}
+
+	source.set(5 * time.Second)
+	clock.Update()
+
+	if len(received) != 0 {
+		t.Fatalf("Got %v, expected no messages before their timecode", received)
+	}
+
+	// A jump forward dispatches the messages passed, in order
+	source.set(20 * time.Second)
+	clock.Update()
+
+	expected1 := []string{"/intro", "/verse"}
+	if !reflect.DeepEqual(received, expected1) {
+		t.Errorf("Got %v, expected %v", received, expected1)
+	}
+
+	// After a jump backward, the remaining message waits for the timecode to reach it again
+	source.set(2 * time.Second)
+	clock.Update()
+	source.set(30 * time.Second)
+	clock.Update()
+
+	expected2 := []string{"/intro", "/verse", "/chorus"}
+	if !reflect.DeepEqual(received, expected2) {
+		t.Errorf("Got %v, expected %v", received, expected2)
+	}
+
+	if result3 := clock.Now(); !result3.Equal(origin.Add(30 * time.Second)) {
+		t.Errorf("Got %v, expected %v", result3, origin.Add(30*time.Second))
+	}
+}
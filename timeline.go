@@ -0,0 +1,250 @@
+package osc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+/*
+TimelineEvent is a packet in a Timeline, sent at an offset from the start of the timeline.
+*/
+type TimelineEvent struct {
+	Offset time.Duration
+	Packet Packet
+}
+
+/*
+Timeline is a sequence of packets with the times at which they are sent, ordered by offset, as recorded by a
+TimelineRecorder and played back by a TimelinePlayer.
+*/
+type Timeline []TimelineEvent
+
+/*
+TimelineRecorder wraps a Client, recording the packets sent with it and when they were sent, e.g. to capture the
+operator's actions during a rehearsal for later playback. It is safe for concurrent use.
+*/
+type TimelineRecorder struct {
+	Client
+
+	mu     sync.Mutex
+	start  time.Time
+	events Timeline
+}
+
+/*
+NewTimelineRecorder creates a TimelineRecorder recording the packets sent with client. Offsets are measured from the
+first packet sent.
+*/
+func NewTimelineRecorder(client Client) *TimelineRecorder {
+	return &TimelineRecorder{Client: client}
+}
+
+/*
+Send sends an OSC packet, and records it if it was sent successfully. A copy of the packet is recorded, so it may be
+reused after Send returns.
+*/
+func (r *TimelineRecorder) Send(p Packet) error {
+	err := r.Client.Send(p)
+	if err != nil {
+		return err
+	}
+
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	recorded, err := decodePacket(data)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if r.events == nil {
+		r.start = now
+	}
+	r.events = append(r.events, TimelineEvent{Offset: now.Sub(r.start), Packet: recorded})
+
+	return nil
+}
+
+/*
+SendAt sends an OSC packet wrapped in a bundle with a time tag of t plus the wrapped client's time tag offset, and
+records the bundle.
+*/
+func (r *TimelineRecorder) SendAt(p Packet, t time.Time) error {
+	return r.Send(newTimedBundle(p, offsetTime(r.Client, t)))
+}
+
+/*
+Timeline returns the packets recorded so far.
+*/
+func (r *TimelineRecorder) Timeline() Timeline {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append(Timeline(nil), r.events...)
+}
+
+/*
+Reset discards the recorded packets. Offsets of packets sent afterwards are measured from the next packet sent.
+*/
+func (r *TimelineRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = nil
+}
+
+/*
+SaveTimeline writes a timeline to a file, as a sequence of records each holding the offset of a packet in nanoseconds
+as a 64-bit big-endian integer, followed by the packet framed with a length prefix.
+*/
+func SaveTimeline(path string, tl Timeline) error {
+	var buf bytes.Buffer
+	for _, e := range tl {
+		data, err := e.Packet.MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		binary.Write(&buf, binary.BigEndian, int64(e.Offset))
+		buf.Write(encodeLengthPrefixed(data))
+	}
+
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+/*
+LoadTimeline reads a timeline written by SaveTimeline.
+*/
+func LoadTimeline(path string) (Timeline, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var tl Timeline
+	reader := bufio.NewReader(f)
+	for {
+		var offset int64
+		err := binary.Read(reader, binary.BigEndian, &offset)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		data, err := readLengthPrefixed(reader, maxStreamPacketSize)
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := decodeLenient(data)
+		if err != nil {
+			return nil, err
+		}
+
+		tl = append(tl, TimelineEvent{Offset: time.Duration(offset), Packet: p})
+	}
+
+	return tl, nil
+}
+
+/*
+TimelinePlayer sends the packets of a Timeline through a Client at the times they were recorded, scaled by an
+adjustable speed, and starting from an adjustable offset into the timeline. It is a simple cue playback engine for show
+automation.
+*/
+type TimelinePlayer struct {
+	timeline Timeline
+	client   Client
+
+	mu     sync.Mutex
+	speed  float64
+	offset time.Duration
+}
+
+/*
+NewTimelinePlayer creates a TimelinePlayer sending the packets of tl with client, at normal speed from the start.
+*/
+func NewTimelinePlayer(tl Timeline, client Client) *TimelinePlayer {
+	return &TimelinePlayer{timeline: tl, client: client, speed: 1}
+}
+
+/*
+SetSpeed sets the playback speed, e.g. 2 to play twice as fast. An error is returned if speed is not positive. It takes
+effect on the next call to Play.
+*/
+func (pl *TimelinePlayer) SetSpeed(speed float64) error {
+	if !(speed > 0) {
+		return errors.New("Playback speed must be positive")
+	}
+
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	pl.speed = speed
+
+	return nil
+}
+
+/*
+SetOffset sets the position in the timeline to start playback from: packets with earlier offsets are skipped. It takes
+effect on the next call to Play.
+*/
+func (pl *TimelinePlayer) SetOffset(offset time.Duration) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	pl.offset = offset
+}
+
+/*
+Play sends the packets of the timeline in order, each at its offset (relative to the starting offset, and scaled by
+the speed) after Play is called. It returns once all the packets have been sent, or with an error when the client
+fails to send one or ctx is done.
+*/
+func (pl *TimelinePlayer) Play(ctx context.Context) error {
+	pl.mu.Lock()
+	speed, offset := pl.speed, pl.offset
+	pl.mu.Unlock()
+
+	start := time.Now()
+
+	for _, e := range pl.timeline {
+		if e.Offset < offset {
+			continue
+		}
+
+		due := time.Duration(float64(e.Offset-offset) / speed)
+		if wait := due - time.Since(start); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+		}
+
+		err := pl.client.Send(e.Packet)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package osc
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimeline(t *testing.T) {
+	destination := &testClient{}
+	recorder := NewTimelineRecorder(destination)
+
+	msg := NewMessage("/cue/1/go")
+	recorder.Send(msg)
+	time.Sleep(100 * time.Millisecond)
+	msg.Address = "/cue/2/go"
+	recorder.Send(msg)
+
+	tl := recorder.Timeline()
+	if len(tl) != 2 || len(destination.sent) != 2 {
+		t.Fatalf("Got %d recorded and %d sent packets, expected 2 each", len(tl), len(destination.sent))
+	}
+
+	// The packet is copied, so reusing it does not change the recording
+	expected1 := "Message: /cue/1/go"
+	if result1 := tl[0].Packet.String(); result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	if tl[0].Offset != 0 || tl[1].Offset < 100*time.Millisecond {
+		t.Errorf("Got offsets %v and %v, expected 0 and at least 100ms", tl[0].Offset, tl[1].Offset)
+	}
+
+	path := t.TempDir() + "/show.timeline"
+	err := SaveTimeline(path, tl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadTimeline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Played back at double speed, the second cue follows after about half the recorded interval
+	playback := &testClient{}
+	player := NewTimelinePlayer(loaded, playback)
+	player.SetSpeed(2)
+
+	start := time.Now()
+	err = player.Play(context.Background())
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(playback.sent) != 2 || playback.sent[1].String() != "Message: /cue/2/go" {
+		t.Fatalf("Got %v, expected both cues", playback.sent)
+	} else if elapsed < tl[1].Offset/2 || elapsed >= tl[1].Offset {
+		t.Errorf("Got %v, expected about %v", elapsed, tl[1].Offset/2)
+	}
+
+	// Playback from an offset skips earlier packets
+	playback.sent = nil
+	player.SetOffset(tl[1].Offset)
+
+	err = player.Play(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	} else if len(playback.sent) != 1 {
+		t.Errorf("Got %v, expected only the second cue", playback.sent)
+	}
+
+	if err := player.SetSpeed(0); err == nil {
+		t.Error("Expected an error setting a speed of 0")
+	}
+}
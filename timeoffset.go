@@ -0,0 +1,56 @@
+package osc
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+timeOffsetOptions holds the offset a client applies to the time tags it generates.
+*/
+type timeOffsetOptions struct {
+	offsetMu sync.Mutex
+	offset   time.Duration
+}
+
+/*
+SetTimeTagOffset sets a constant offset added to the time tags of bundles generated by SendAt, e.g. to compensate for
+the known latency of a downstream system when synchronising lighting and video playback. A negative offset makes
+packets execute earlier. It may be changed at any time, taking effect from the next call to SendAt. Time tags of
+bundles passed to Send are not changed.
+*/
+func (o *timeOffsetOptions) SetTimeTagOffset(offset time.Duration) {
+	o.offsetMu.Lock()
+	defer o.offsetMu.Unlock()
+
+	o.offset = offset
+}
+
+/*
+TimeTagOffset returns the offset added to the time tags of bundles generated by SendAt.
+*/
+func (o *timeOffsetOptions) TimeTagOffset() time.Duration {
+	o.offsetMu.Lock()
+	defer o.offsetMu.Unlock()
+
+	return o.offset
+}
+
+/*
+timedBundle wraps a packet in a bundle to be executed at time t, plus the offset.
+*/
+func (o *timeOffsetOptions) timedBundle(p Packet, t time.Time) *Bundle {
+	return newTimedBundle(p, t.Add(o.TimeTagOffset()))
+}
+
+/*
+offsetTime adds the time tag offset of client (if it has one) to t, so that clients wrapping another client apply its
+offset in their own SendAt.
+*/
+func offsetTime(client Client, t time.Time) time.Time {
+	if c, ok := client.(interface{ TimeTagOffset() time.Duration }); ok {
+		return t.Add(c.TimeTagOffset())
+	}
+
+	return t
+}
@@ -0,0 +1,36 @@
+package osc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeTagOffset(t *testing.T) {
+	client := &testClient{}
+	client.SetTimeTagOffset(40 * time.Millisecond)
+
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expected1 := at.Add(40 * time.Millisecond)
+
+	err := client.SendAt(NewMessage("/cue/go"), at)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chaos := NewChaosClient(client, ChaosConfig{})
+	err = chaos.SendAt(NewMessage("/cue/go"), at)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(client.sent) != 2 {
+		t.Fatalf("Got %v, expected 2 packets", client.sent)
+	}
+
+	for _, p := range client.sent {
+		result1 := p.(*Bundle).TimeTag.Time()
+		if !result1.Equal(expected1) {
+			t.Errorf("Got %v, expected %v", result1, expected1)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+//go:build linux
+
+package osc
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+/*
+setKernelTimestamps enables SO_TIMESTAMPNS on a socket, so that the kernel records the time each packet is received.
+*/
+func setKernelTimestamps(c syscall.RawConn) error {
+	var sockErr error
+
+	err := c.Control(func(fd uintptr) {
+		sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_TIMESTAMPNS, 1)
+	})
+	if err != nil {
+		return err
+	}
+
+	return sockErr
+}
+
+/*
+parseKernelTimestamp returns the receive time recorded by the kernel in the control messages of a packet.
+*/
+func parseKernelTimestamp(oob []byte) (time.Time, bool) {
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	for _, msg := range msgs {
+		if msg.Header.Level != syscall.SOL_SOCKET || msg.Header.Type != syscall.SCM_TIMESTAMPNS {
+			continue
+		}
+
+		if len(msg.Data) < int(unsafe.Sizeof(syscall.Timespec{})) {
+			return time.Time{}, false
+		}
+
+		ts := (*syscall.Timespec)(unsafe.Pointer(&msg.Data[0]))
+		return time.Unix(ts.Unix()), true
+	}
+
+	return time.Time{}, false
+}
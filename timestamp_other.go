@@ -0,0 +1,23 @@
+//go:build !linux
+
+package osc
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+)
+
+/*
+setKernelTimestamps is not supported on this platform.
+*/
+func setKernelTimestamps(c syscall.RawConn) error {
+	return fmt.Errorf("Kernel timestamps are not supported on this platform")
+}
+
+/*
+parseKernelTimestamp is not supported on this platform.
+*/
+func parseKernelTimestamp(oob []byte) (time.Time, bool) {
+	return time.Time{}, false
+}
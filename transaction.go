@@ -0,0 +1,137 @@
+package osc
+
+import (
+	"errors"
+	"sync"
+)
+
+/*
+Transaction collects packets to be sent together as a single bundle with an immediate time tag, so that the receiver
+applies them atomically (e.g. several mixer parameters at once). A Transaction is finished by Commit or Rollback, and
+cannot be used afterwards. It is safe for concurrent use.
+*/
+type Transaction struct {
+	client Client
+
+	mu       sync.Mutex
+	bundle   *Bundle
+	finished bool
+}
+
+/*
+NewTransaction creates a Transaction which sends through client.
+*/
+func NewTransaction(client Client) *Transaction {
+	return &Transaction{client: client, bundle: NewBundle()}
+}
+
+/*
+Add adds a packet to the transaction.
+*/
+func (tx *Transaction) Add(p Packet) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.finished {
+		return errors.New("Transaction is already finished")
+	}
+
+	tx.bundle.AddPacket(p)
+
+	return nil
+}
+
+/*
+AddMessage adds a message with the given address and arguments to the transaction.
+*/
+func (tx *Transaction) AddMessage(address string, args ...interface{}) error {
+	msg := NewMessage(address)
+	for _, arg := range args {
+		err := msg.AddArgument(arg)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Add(msg)
+}
+
+/*
+Commit sends the packets of the transaction as one bundle. If no packets were added, nothing is sent.
+*/
+func (tx *Transaction) Commit() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.finished {
+		return errors.New("Transaction is already finished")
+	}
+
+	tx.finished = true
+
+	if len(tx.bundle.Elements) == 0 {
+		return nil
+	}
+
+	return tx.client.Send(tx.bundle)
+}
+
+/*
+Rollback discards the packets of the transaction without sending them.
+*/
+func (tx *Transaction) Rollback() {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	tx.finished = true
+	tx.bundle = NewBundle()
+}
+
+/*
+Transaction returns a new Transaction sending through the client.
+*/
+func (c *UDPClient) Transaction() *Transaction {
+	return NewTransaction(c)
+}
+
+/*
+Transaction returns a new Transaction sending through the client.
+*/
+func (c *TCPClient) Transaction() *Transaction {
+	return NewTransaction(c)
+}
+
+/*
+Transaction returns a new Transaction sending through the client.
+*/
+func (c *SerialClient) Transaction() *Transaction {
+	return NewTransaction(c)
+}
+
+/*
+Transaction returns a new Transaction sending through the queue.
+*/
+func (q *PersistentQueue) Transaction() *Transaction {
+	return NewTransaction(q)
+}
+
+/*
+Transaction returns a new Transaction sending through the client.
+*/
+func (c *TransportClient) Transaction() *Transaction {
+	return NewTransaction(c)
+}
+
+/*
+Transaction returns a new Transaction sending through the client.
+*/
+func (c *ChaosClient) Transaction() *Transaction {
+	return NewTransaction(c)
+}
+
+/*
+Transaction returns a new Transaction sending through the recorder, so that the committed bundle is recorded.
+*/
+func (r *TimelineRecorder) Transaction() *Transaction {
+	return NewTransaction(r)
+}
@@ -0,0 +1,72 @@
+package osc
+
+import (
+	"testing"
+)
+
+func TestTransaction(t *testing.T) {
+	client := &testClient{}
+
+	tx := NewTransaction(client)
+	tx.AddMessage("/ch/1/fader", float32(0.5))
+	tx.AddMessage("/ch/2/fader", float32(0.75))
+
+	if len(client.sent) != 0 {
+		t.Fatal("Packets were sent before the transaction was committed")
+	}
+
+	err := tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(client.sent) != 1 {
+		t.Fatalf("Got %d sent packets, expected 1", len(client.sent))
+	}
+
+	bundle, ok := client.sent[0].(*Bundle)
+	if !ok || !bundle.TimeTag.Immediate || len(bundle.Elements) != 2 {
+		t.Errorf("Got %v, expected an immediate bundle of 2 messages", client.sent[0])
+	}
+
+	if err := tx.Commit(); err == nil {
+		t.Error("Expected an error committing a finished transaction")
+	}
+
+	tx = NewTransaction(client)
+	tx.AddMessage("/ch/1/fader", float32(0))
+	tx.Rollback()
+
+	if err := tx.AddMessage("/ch/1/fader", float32(0)); err == nil {
+		t.Error("Expected an error adding to a rolled back transaction")
+	}
+
+	if len(client.sent) != 1 {
+		t.Errorf("Got %d sent packets after rolling back, expected 1", len(client.sent))
+	}
+}
+
+func TestTimelineRecorderTransaction(t *testing.T) {
+	client := &testClient{}
+	recorder := NewTimelineRecorder(client)
+
+	tx := recorder.Transaction()
+	tx.AddMessage("/ch/1/fader", float32(0.5))
+
+	err := tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result1 := len(recorder.Timeline())
+	expected1 := 1
+	if result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+
+	result2 := len(client.sent)
+	expected2 := 1
+	if result2 != expected2 {
+		t.Errorf("Got %v, expected %v", result2, expected2)
+	}
+}
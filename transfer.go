@@ -0,0 +1,385 @@
+package osc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// TransferBeginAddress is the address of requests starting (or resuming) a transfer.
+	TransferBeginAddress = "/transfer/begin"
+	// TransferChunkAddress is the address of requests carrying a chunk of a transfer.
+	TransferChunkAddress = "/transfer/chunk"
+	// TransferEndAddress is the address of requests completing a transfer.
+	TransferEndAddress = "/transfer/end"
+
+	// TransferChunkSize is the size of the blob in each chunk, small enough for a chunk to fit in one UDP datagram on
+	// most networks.
+	TransferChunkSize = 1024
+)
+
+// DefaultMaxTransferSize is the size in bytes of the largest transfer received by HandleTransfers, unless changed with
+// SetMaxTransferSize.
+const DefaultMaxTransferSize = 64 << 20
+
+const (
+	// The time to wait for a reply to a transfer request before sending it again
+	transferRetryInterval = 500 * time.Millisecond
+	// The time after which a transfer with no requests is discarded by the receiver
+	transferTimeout = time.Minute
+	// The number of transfers which may be received at once
+	maxTransfers = 16
+)
+
+/*
+SendBlob sends data to a remote endpoint which receives transfers (see HandleTransfers) as a sequence of chunked blob
+messages, for pushing media or preset files to devices which only expose OSC. The transfer is identified by name and
+the SHA-256 hash of data; the receiver acknowledges each chunk, and chunks which are not acknowledged are sent again.
+If a transfer with the same name and hash was interrupted, it is resumed from the last acknowledged chunk.
+
+Requests are sent using client, and replies are received by the client's AddressSpace, in the same way as Ping.
+SendBlob returns when the receiver has verified the data, or with an error when ctx is done or the receiver rejects
+the transfer.
+*/
+func SendBlob(ctx context.Context, client Client, name string, data []byte) error {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	size := int64(len(data))
+
+	reply, err := transferRequest(ctx, client, TransferBeginAddress, name, hash, size)
+	if err != nil {
+		return err
+	}
+
+	offset, err := transferOffset(reply, size)
+	if err != nil {
+		return err
+	}
+
+	for offset < size {
+		end := offset + TransferChunkSize
+		if end > size {
+			end = size
+		}
+
+		reply, err = transferRequest(ctx, client, TransferChunkAddress, name, hash, offset, data[offset:end])
+		if err != nil {
+			return err
+		}
+
+		// The receiver replies with the offset it expects next, which is where to continue from if chunks were lost
+		offset, err = transferOffset(reply, size)
+		if err != nil {
+			return err
+		}
+	}
+
+	reply, err = transferRequest(ctx, client, TransferEndAddress, name, hash)
+	if err != nil {
+		return err
+	}
+
+	status, ok := reply.Arguments[1].(string)
+	if !ok {
+		return fmt.Errorf("Malformed transfer reply")
+	} else if status != "ok" {
+		return fmt.Errorf("Transfer of %s failed: %s", name, status)
+	}
+
+	return nil
+}
+
+/*
+SendFile sends the file at path with SendBlob, named by the last element of path.
+*/
+func SendFile(ctx context.Context, client Client, path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return SendBlob(ctx, client, filepath.Base(path), data)
+}
+
+/*
+transferRequest sends a transfer request, sending it again whenever no reply is received within
+transferRetryInterval, until ctx is done.
+*/
+func transferRequest(ctx context.Context, client Client, address string, args ...interface{}) (*Message, error) {
+	for {
+		attemptCtx, cancel := context.WithTimeout(ctx, transferRetryInterval)
+		reply, err := request(attemptCtx, client, address, address+replySuffix, 2, args...)
+		cancel()
+
+		if err == nil || ctx.Err() != nil || err != context.DeadlineExceeded {
+			return reply, err
+		}
+	}
+}
+
+/*
+transferOffset returns the offset in a reply to a transfer request.
+*/
+func transferOffset(reply *Message, size int64) (int64, error) {
+	offset, ok := reply.Arguments[1].(int64)
+	if !ok {
+		if status, ok := reply.Arguments[1].(string); ok {
+			return 0, fmt.Errorf("Transfer failed: %s", status)
+		}
+		return 0, fmt.Errorf("Malformed transfer reply")
+	} else if offset < 0 || offset > size {
+		return 0, fmt.Errorf("Transfer reply offset %d out of range", offset)
+	}
+
+	return offset, nil
+}
+
+/*
+incomingTransfer is a transfer being received, or recently completed.
+*/
+type incomingTransfer struct {
+	size    int64
+	data    []byte
+	done    bool
+	updated time.Time
+}
+
+/*
+SetMaxTransferSize sets the size in bytes of the largest transfer accepted by the methods added by HandleTransfers. A
+transfer declaring a larger size is rejected before any of its data is received. The default is
+DefaultMaxTransferSize.
+*/
+func (a *AddressSpace) SetMaxTransferSize(size int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.maxTransfer = size
+}
+
+func (a *AddressSpace) maxTransferSize() int64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.maxTransfer <= 0 {
+		return DefaultMaxTransferSize
+	}
+
+	return a.maxTransfer
+}
+
+/*
+HandleTransfers adds OSC methods to the AddressSpace which receive the transfers sent by SendBlob and SendFile. When all
+the data of a transfer has been received and its hash verified, receive is called with its name and data; if receive
+returns an error, the transfer fails, and the error is reported to the sender.
+
+Transfers are received into memory, so their size is limited (see SetMaxTransferSize), as is the number received at
+once. Partially received transfers are kept, so that an interrupted transfer can be resumed by sending it again, but
+are discarded once no request for them has been received for a minute. Completed transfers are released, and only
+remembered for the same time in case the sender did not receive the reply to its last request.
+*/
+func (a *AddressSpace) HandleTransfers(receive func(name string, data []byte) error) error {
+	var mu sync.Mutex
+	transfers := make(map[string]*incomingTransfer)
+
+	// lookup returns the transfer with the given key, after discarding stalled transfers. The lock must be held.
+	lookup := func(key string) (*incomingTransfer, bool) {
+		now := a.getClock().Now()
+		for k, t := range transfers {
+			if now.Sub(t.updated) > transferTimeout {
+				delete(transfers, k)
+			}
+		}
+
+		t, ok := transfers[key]
+		if ok {
+			t.updated = now
+		}
+
+		return t, ok
+	}
+
+	err := a.handlePeer(TransferBeginAddress, func(m *Message, peer *Peer) {
+		args := transferArguments(m)
+		name, hash, ok := transferKey(args)
+		if !ok || len(args) < 3 {
+			return
+		}
+
+		size, ok := args[2].(int64)
+		if !ok || size < 0 {
+			return
+		}
+
+		if max := a.maxTransferSize(); size > max {
+			replyTransfer(peer, m, fmt.Sprintf("Transfer size %d exceeds limit of %d bytes", size, max))
+			return
+		}
+
+		mu.Lock()
+		t, resumed := lookup(name + "\x00" + hash)
+		if !resumed || t.size != size {
+			if !resumed && len(transfers) >= maxTransfers {
+				mu.Unlock()
+				replyTransfer(peer, m, "Too many transfers in progress")
+				return
+			}
+
+			t = &incomingTransfer{size: size, updated: a.getClock().Now()}
+			transfers[name+"\x00"+hash] = t
+		}
+
+		// A completed transfer which is sent again only needs to be acknowledged
+		offset := int64(len(t.data))
+		if t.done {
+			offset = t.size
+		}
+		mu.Unlock()
+
+		replyTransfer(peer, m, offset)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = a.handlePeer(TransferChunkAddress, func(m *Message, peer *Peer) {
+		args := transferArguments(m)
+		name, hash, ok := transferKey(args)
+		if !ok || len(args) < 4 {
+			return
+		}
+
+		offset, ok := args[2].(int64)
+		chunk, isBlob := args[3].([]byte)
+		if !ok || !isBlob {
+			return
+		}
+
+		mu.Lock()
+		t, ok := lookup(name + "\x00" + hash)
+		if !ok {
+			mu.Unlock()
+			replyTransfer(peer, m, "Unknown transfer")
+			return
+		}
+
+		// Chunks which do not continue the data (e.g. duplicates) are ignored, and the expected offset is sent back
+		if !t.done && offset == int64(len(t.data)) && offset+int64(len(chunk)) <= t.size {
+			t.data = append(t.data, chunk...)
+		}
+
+		next := int64(len(t.data))
+		if t.done {
+			next = t.size
+		}
+		mu.Unlock()
+
+		replyTransfer(peer, m, next)
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.handlePeer(TransferEndAddress, func(m *Message, peer *Peer) {
+		args := transferArguments(m)
+		name, hash, ok := transferKey(args)
+		if !ok {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		key := name + "\x00" + hash
+		t, ok := lookup(key)
+		if !ok {
+			replyTransfer(peer, m, "Unknown transfer")
+			return
+		} else if t.done {
+			replyTransfer(peer, m, "ok")
+			return
+		} else if int64(len(t.data)) != t.size {
+			replyTransfer(peer, m, "Incomplete transfer")
+			return
+		}
+
+		sum := sha256.Sum256(t.data)
+		if hex.EncodeToString(sum[:]) != hash {
+			delete(transfers, key)
+			replyTransfer(peer, m, "Hash mismatch")
+			return
+		}
+
+		err := receive(name, t.data)
+		if err != nil {
+			delete(transfers, key)
+			replyTransfer(peer, m, err.Error())
+			return
+		}
+
+		// The data is released, but the transfer is remembered until it expires, in case the reply is lost and the end
+		// is sent again
+		t.data = nil
+		t.done = true
+
+		replyTransfer(peer, m, "ok")
+	})
+}
+
+/*
+ReceiveFiles returns a function for HandleTransfers which writes each received transfer to a file in dir. Only the
+last element of the transfer's name is used, so files cannot be written outside dir.
+*/
+func ReceiveFiles(dir string) func(name string, data []byte) error {
+	return func(name string, data []byte) error {
+		base := filepath.Base(name)
+		if base == "." || base == ".." || base == string(filepath.Separator) {
+			return errors.New("Invalid file name")
+		}
+
+		return ioutil.WriteFile(filepath.Join(dir, base), data, 0644)
+	}
+}
+
+/*
+transferArguments returns the arguments of a transfer request following its token.
+*/
+func transferArguments(m *Message) []interface{} {
+	if len(m.Arguments) == 0 {
+		return nil
+	}
+
+	return m.Arguments[1:]
+}
+
+/*
+transferKey returns the name and hash identifying the transfer of a request.
+*/
+func transferKey(args []interface{}) (string, string, bool) {
+	if len(args) < 2 {
+		return "", "", false
+	}
+
+	name, ok := args[0].(string)
+	hash, isString := args[1].(string)
+
+	return name, hash, ok && isString
+}
+
+/*
+replyTransfer replies to a transfer request with a single result, which is an offset or a status. The reply is sent
+back to the sender of the request.
+*/
+func replyTransfer(peer *Peer, m *Message, result interface{}) {
+	reply := NewMessage(m.Address + replySuffix)
+	reply.AddArgument(m.Arguments[0])
+	reply.AddArgument(result)
+
+	peer.Reply(reply)
+}
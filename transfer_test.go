@@ -0,0 +1,170 @@
+package osc
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSendBlob(t *testing.T) {
+	var mu sync.Mutex
+	received := make(map[string][]byte)
+
+	responder, _ := NewUDPServer("127.0.0.1", 0)
+	responder.(*UDPServer).HandleTransfers(func(name string, data []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		received[name] = data
+		return nil
+	})
+	err := responder.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer responder.StopListening()
+
+	client, _ := NewUDPClient("127.0.0.1", responder.LocalAddr().(*net.UDPAddr).Port)
+	err = client.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	data := make([]byte, 5*TransferChunkSize+100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	// Lost and duplicated requests are sent again, or ignored
+	lossy := NewChaosClient(client, ChaosConfig{DropRate: 0.2, DuplicateRate: 0.2, Seed: 1})
+
+	err = SendBlob(ctx, lossy, "preset.bin", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	result1 := received["preset.bin"]
+	mu.Unlock()
+	if !bytes.Equal(result1, data) {
+		t.Errorf("Got %d bytes, expected %d", len(result1), len(data))
+	}
+
+	// An interrupted transfer is resumed
+	sum := sha256.Sum256(data[:2*TransferChunkSize])
+	hash := hex.EncodeToString(sum[:])
+	_, err = transferRequest(ctx, client, TransferBeginAddress, "resumed.bin", hash, int64(2*TransferChunkSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = transferRequest(ctx, client, TransferChunkAddress, "resumed.bin", hash, int64(0), data[:TransferChunkSize])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = SendBlob(ctx, client, "resumed.bin", data[:2*TransferChunkSize])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	result2 := received["resumed.bin"]
+	mu.Unlock()
+	if !bytes.Equal(result2, data[:2*TransferChunkSize]) {
+		t.Errorf("Got %d bytes, expected %d", len(result2), 2*TransferChunkSize)
+	}
+}
+
+func TestTransferLimits(t *testing.T) {
+	clock := NewSimulatedClock(time.Unix(0, 0))
+
+	responder, _ := NewUDPServer("127.0.0.1", 0)
+	responder.(*UDPServer).SetClock(clock)
+	responder.(*UDPServer).SetMaxTransferSize(TransferChunkSize)
+	responder.(*UDPServer).HandleTransfers(func(name string, data []byte) error {
+		return nil
+	})
+	err := responder.StartListening()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer responder.StopListening()
+
+	client, _ := NewUDPClient("127.0.0.1", responder.LocalAddr().(*net.UDPAddr).Port)
+	err = client.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// A transfer larger than the limit is rejected
+	err = SendBlob(ctx, client, "large.bin", make([]byte, TransferChunkSize+1))
+	if err == nil {
+		t.Errorf("Got %v, expected an error", err)
+	}
+
+	// A stalled transfer is discarded
+	data := []byte("preset")
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	_, err = transferRequest(ctx, client, TransferBeginAddress, "stalled.bin", hash, int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(transferTimeout + time.Second)
+
+	reply, err := transferRequest(ctx, client, TransferChunkAddress, "stalled.bin", hash, int64(0), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result1 := reply.Arguments[1]
+	expected1 := "Unknown transfer"
+	if result1 != expected1 {
+		t.Errorf("Got %v, expected %v", result1, expected1)
+	}
+}
+
+func TestReceiveFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "transfer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	receive := ReceiveFiles(dir)
+
+	err = receive("../../show.json", []byte("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result1, err := ioutil.ReadFile(filepath.Join(dir, "show.json"))
+	expected1 := "{}"
+	if err != nil {
+		t.Fatal(err)
+	} else if string(result1) != expected1 {
+		t.Errorf("Got %v, expected %v", string(result1), expected1)
+	}
+
+	err = receive("..", nil)
+	if err == nil {
+		t.Errorf("Got %v, expected an error", err)
+	}
+}
@@ -0,0 +1,259 @@
+package osc
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+TransformFunc changes a message as it is forwarded by a Router. The message passed in is a copy which may be modified
+and returned. If the returned message is nil, the message is not forwarded.
+*/
+type TransformFunc func(m *Message) (*Message, error)
+
+/*
+ParseTransform compiles a transform expression, so that routes can be adapted between devices without writing code.
+An expression is a list of statements separated by semicolons, applied in order:
+
+	address /new/address          replace the address
+	scale N inMin inMax outMin outMax
+	                              linearly map numeric argument N from one range to another
+	exp N inMin inMax outMin outMax exponent
+	                              map numeric argument N exponentially from one range to another
+	todb N floor                  convert numeric argument N from a linear gain to decibels (at least floor)
+	togain N floor                convert numeric argument N from decibels to a linear gain (0 at floor or below)
+	swap N M                      swap arguments N and M
+	drop N                        remove argument N
+	convert N T                   convert numeric argument N to type tag T (i, h, f or d)
+
+The mapping operations (scale, exp, todb and togain) keep the type of float arguments, and map integer arguments to
+float32s; follow them with convert to send another type. Arguments are numbered from 0. For example, "address /ch/01/mix/fader; scale 0 0 127 0 1" converts a MIDI-style
+controller value into a normalised fader level.
+*/
+func ParseTransform(expr string) (TransformFunc, error) {
+	var steps []TransformFunc
+
+	for _, statement := range strings.Split(expr, ";") {
+		fields := strings.Fields(statement)
+		if len(fields) == 0 {
+			continue
+		}
+
+		step, err := parseTransformStatement(fields[0], fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid transform statement \"%s\": %v", strings.TrimSpace(statement), err)
+		}
+
+		steps = append(steps, step)
+	}
+
+	return func(m *Message) (*Message, error) {
+		for _, step := range steps {
+			var err error
+			m, err = step(m)
+			if err != nil || m == nil {
+				return nil, err
+			}
+		}
+
+		return m, nil
+	}, nil
+}
+
+func parseTransformStatement(op string, operands []string) (TransformFunc, error) {
+	switch op {
+	case "address":
+		if len(operands) != 1 || !strings.HasPrefix(operands[0], "/") {
+			return nil, fmt.Errorf("expected an address starting with '/'")
+		}
+
+		address := operands[0]
+		return func(m *Message) (*Message, error) {
+			m.Address = address
+			return m, nil
+		}, nil
+
+	case "scale":
+		values, err := parseTransformNumbers(operands, 5)
+		if err != nil {
+			return nil, err
+		}
+
+		i := int(values[0])
+		inMin, inMax, outMin, outMax := values[1], values[2], values[3], values[4]
+		if inMin == inMax {
+			return nil, fmt.Errorf("input range is empty")
+		}
+
+		return MapArgument(i, Linear(inMin, inMax, outMin, outMax)), nil
+
+	case "exp":
+		values, err := parseTransformNumbers(operands, 6)
+		if err != nil {
+			return nil, err
+		}
+
+		if values[1] == values[2] {
+			return nil, fmt.Errorf("input range is empty")
+		}
+
+		return MapArgument(int(values[0]), Exponential(values[1], values[2], values[3], values[4], values[5])), nil
+
+	case "todb", "togain":
+		values, err := parseTransformNumbers(operands, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		curve := GainToDecibels(values[1])
+		if op == "togain" {
+			curve = DecibelsToGain(values[1])
+		}
+
+		return MapArgument(int(values[0]), curve), nil
+
+	case "swap":
+		values, err := parseTransformNumbers(operands, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		i, j := int(values[0]), int(values[1])
+		return func(m *Message) (*Message, error) {
+			if err := checkArgumentIndex(m, i); err != nil {
+				return nil, err
+			}
+			if err := checkArgumentIndex(m, j); err != nil {
+				return nil, err
+			}
+
+			m.Arguments[i], m.Arguments[j] = m.Arguments[j], m.Arguments[i]
+			return m, nil
+		}, nil
+
+	case "drop":
+		values, err := parseTransformNumbers(operands, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		i := int(values[0])
+		return func(m *Message) (*Message, error) {
+			if err := checkArgumentIndex(m, i); err != nil {
+				return nil, err
+			}
+
+			m.Arguments = append(m.Arguments[:i], m.Arguments[i+1:]...)
+			return m, nil
+		}, nil
+
+	case "convert":
+		if len(operands) != 2 {
+			return nil, fmt.Errorf("expected 2 operands, got %d", len(operands))
+		}
+
+		values, err := parseTransformNumbers(operands[:1], 1)
+		if err != nil {
+			return nil, err
+		}
+
+		i, typeTag := int(values[0]), operands[1]
+		if !strings.Contains("ihfd", typeTag) || len(typeTag) != 1 {
+			return nil, fmt.Errorf("cannot convert to type '%s'", typeTag)
+		}
+
+		return func(m *Message) (*Message, error) {
+			if err := checkArgumentIndex(m, i); err != nil {
+				return nil, err
+			}
+
+			converted, err := convertNumber(m.Arguments[i], typeTag[0])
+			if err != nil {
+				return nil, err
+			}
+
+			m.Arguments[i] = converted
+			return m, nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown operation")
+}
+
+func parseTransformNumbers(operands []string, count int) ([]float64, error) {
+	if len(operands) != count {
+		return nil, fmt.Errorf("expected %d operands, got %d", count, len(operands))
+	}
+
+	values := make([]float64, count)
+	for i, operand := range operands {
+		v, err := strconv.ParseFloat(operand, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+
+	return values, nil
+}
+
+func checkArgumentIndex(m *Message, i int) error {
+	if i < 0 || i >= len(m.Arguments) {
+		return fmt.Errorf("Message %s has no argument %d", m.Address, i)
+	}
+
+	return nil
+}
+
+/*
+mapNumber applies fn to a numeric argument. Floats keep their type; integers are mapped to float32s, as a curve usually
+maps a range of integers (e.g. MIDI controller values) onto a fraction of a continuous range. Use convertNumber to get
+an integer back.
+*/
+func mapNumber(arg interface{}, fn func(float64) float64) (interface{}, error) {
+	switch v := arg.(type) {
+	case int32:
+		return float32(fn(float64(v))), nil
+	case int64:
+		return float32(fn(float64(v))), nil
+	case float32:
+		return float32(fn(float64(v))), nil
+	case float64:
+		return fn(v), nil
+	}
+
+	return nil, fmt.Errorf("Argument %v is not a number", arg)
+}
+
+/*
+convertNumber converts a numeric argument to the type with the given type tag. Floats are rounded when converted to
+integers.
+*/
+func convertNumber(arg interface{}, typeTag byte) (interface{}, error) {
+	var f float64
+	switch v := arg.(type) {
+	case int32:
+		f = float64(v)
+	case int64:
+		f = float64(v)
+	case float32:
+		f = float64(v)
+	case float64:
+		f = v
+	default:
+		return nil, fmt.Errorf("Argument %v is not a number", arg)
+	}
+
+	switch typeTag {
+	case 'i':
+		return int32(math.Round(f)), nil
+	case 'h':
+		return int64(math.Round(f)), nil
+	case 'f':
+		return float32(f), nil
+	}
+
+	return f, nil
+}
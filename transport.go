@@ -0,0 +1,349 @@
+package osc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+/*
+Transport carries encoded OSC packets between two endpoints, so that clients and servers can run over links other than
+the network (e.g. shared memory, radio modules, or test doubles). Packet encoding, dispatch and statistics are handled
+by TransportClient and TransportServer; a Transport only moves bytes.
+*/
+type Transport interface {
+	// Open starts the transport, calling receive with the data of each packet received until it is closed.
+	Open(receive func(data []byte)) error
+	// Send sends the data of a single packet.
+	Send(data []byte) error
+	// Close stops the transport.
+	Close() error
+}
+
+/*
+StreamTransport is a Transport over a byte stream, such as a pipe or a radio module presenting a serial interface, with
+packets delimited by a Framing. Packets received are limited in size, whatever the framing.
+*/
+type StreamTransport struct {
+	stream  io.ReadWriteCloser
+	reader  *bufio.Reader
+	framing limitedFraming
+	writeMu sync.Mutex
+	logger  Logger
+
+	// onClose is called once the stream can no longer be read
+	onClose func()
+}
+
+/*
+NewStreamTransport creates a Transport sending and receiving packets over stream, delimited by framing.
+*/
+func NewStreamTransport(stream io.ReadWriteCloser, framing Framing) *StreamTransport {
+	return &StreamTransport{
+		stream:  stream,
+		reader:  bufio.NewReader(stream),
+		framing: limitedFraming{Framing: framing, limit: maxStreamPacketSize},
+	}
+}
+
+/*
+SetLogger sets the Logger used to report malformed packets and errors received from the stream. By default, messages are logged
+to standard error. It must be called before Open.
+*/
+func (t *StreamTransport) SetLogger(logger Logger) {
+	t.logger = logger
+}
+
+/*
+Open starts reading packets from the stream, until it is closed or cannot be read. Malformed packets are logged and
+skipped, and errors other than the stream being closed are logged before reading stops.
+*/
+func (t *StreamTransport) Open(receive func(data []byte)) error {
+	logger := t.logger
+	if logger == nil {
+		logger = defaultLogger
+	}
+
+	go func() {
+		if t.onClose != nil {
+			defer t.onClose()
+		}
+
+		for {
+			data, err := t.framing.ReadPacket(t.reader)
+			if err == errMalformedSLIP {
+				logger.Printf("%v", err)
+				continue
+			} else if err != nil {
+				if !isStreamClosed(err) {
+					logger.Printf("%v", err)
+				}
+				return
+			}
+
+			receive(data)
+		}
+	}()
+
+	return nil
+}
+
+/*
+isStreamClosed returns true if err only means that a stream has been closed, by either end.
+*/
+func isStreamClosed(err error) bool {
+	if _, ok := err.(net.Error); ok {
+		return true
+	}
+
+	return err == io.EOF || errors.Is(err, io.ErrClosedPipe) || errors.Is(err, os.ErrClosed)
+}
+
+/*
+Send writes a framed packet to the stream.
+*/
+func (t *StreamTransport) Send(data []byte) error {
+	_, err := t.send(data)
+
+	return err
+}
+
+/*
+send writes a framed packet to the stream, returning the number of bytes written.
+*/
+func (t *StreamTransport) send(data []byte) (int, error) {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+
+	w := &countingWriter{w: t.stream}
+	err := t.framing.WritePacket(w, data)
+
+	return w.n, err
+}
+
+/*
+Close closes the stream.
+*/
+func (t *StreamTransport) Close() error {
+	return t.stream.Close()
+}
+
+/*
+TransportClient provides functionality to exchange OSC packets over a user-supplied Transport. Packets received over
+the transport are dispatched to the client's AddressSpace.
+*/
+type TransportClient struct {
+	transport Transport
+	mu        sync.Mutex
+	connected bool
+	compatOptions
+	timeOffsetOptions
+	sendStats
+
+	AddressSpace
+}
+
+// Compile-time check to ensure TransportClient implements the Client interface.
+var _ Client = &TransportClient{}
+
+/*
+NewTransportClient creates a new OSC client communicating over transport.
+*/
+func NewTransportClient(transport Transport) *TransportClient {
+	return &TransportClient{transport: transport}
+}
+
+/*
+SetAddr is not supported by transport clients, which are bound to the transport given to NewTransportClient.
+*/
+func (c *TransportClient) SetAddr(ip string, port int) error {
+	return fmt.Errorf("Transport clients do not have a network address")
+}
+
+/*
+SetLocalAddr is not supported by transport clients, which are bound to the transport given to NewTransportClient.
+*/
+func (c *TransportClient) SetLocalAddr(ip string, port int) error {
+	return fmt.Errorf("Transport clients do not have a network address")
+}
+
+/*
+Connect opens the transport.
+*/
+func (c *TransportClient) Connect() error {
+	if c.transport == nil {
+		return fmt.Errorf("Client has no transport")
+	}
+
+	err := c.transport.Open(func(data []byte) {
+		c.AddressSpace.dispatchData(data, newPeer(nil, c.Send))
+	})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.connected = true
+	c.mu.Unlock()
+
+	return nil
+}
+
+/*
+Disconnect closes the transport.
+*/
+func (c *TransportClient) Disconnect() error {
+	if !c.IsConnected() {
+		return nil
+	}
+
+	c.mu.Lock()
+	c.connected = false
+	c.mu.Unlock()
+
+	return c.transport.Close()
+}
+
+/*
+IsConnected returns true if the transport is open.
+*/
+func (c *TransportClient) IsConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.transport != nil && c.connected
+}
+
+/*
+Send sends an OSC packet (message or bundle) over the transport.
+*/
+func (c *TransportClient) Send(p Packet) error {
+	n, err := c.send(p)
+	c.recordSend(n, err)
+
+	return err
+}
+
+func (c *TransportClient) send(p Packet) (int, error) {
+	if !c.IsConnected() {
+		return 0, fmt.Errorf("Client is not connected")
+	}
+
+	data, err := c.marshal(p)
+	if err != nil {
+		return 0, err
+	}
+
+	logWire(wireOut, "transport", data)
+
+	err = c.transport.Send(data)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+/*
+SendAt sends an OSC packet wrapped in a bundle with a time tag of t plus the client's time tag offset (see
+SetTimeTagOffset), so that the receiver executes it at that time.
+*/
+func (c *TransportClient) SendAt(p Packet, t time.Time) error {
+	return c.Send(c.timedBundle(p, t))
+}
+
+/*
+TransportServer provides functionality to receive OSC messages over a user-supplied Transport. Methods replying to a
+message send their reply back over the transport.
+*/
+type TransportServer struct {
+	transport Transport
+	listening listenState
+
+	AddressSpace
+}
+
+// Compile-time check to ensure TransportServer implements the Server interface.
+var _ Server = &TransportServer{}
+
+/*
+NewTransportServer creates an OSC server receiving packets over transport.
+*/
+func NewTransportServer(transport Transport) *TransportServer {
+	return &TransportServer{transport: transport}
+}
+
+/*
+SetLocalAddr is not supported by transport servers, which are bound to the transport given to NewTransportServer.
+*/
+func (s *TransportServer) SetLocalAddr(ip string, port int) error {
+	return fmt.Errorf("Transport servers do not have a network address")
+}
+
+/*
+StartListening opens the transport, and starts dispatching the packets received over it.
+*/
+func (s *TransportServer) StartListening() error {
+	if s.transport == nil {
+		return fmt.Errorf("Server has no transport")
+	}
+
+	err := s.transport.Open(func(data []byte) {
+		s.AddressSpace.dispatchData(data, newPeer(nil, s.reply))
+	})
+	if err != nil {
+		return err
+	}
+
+	s.listening.setListening(nil)
+
+	return nil
+}
+
+/*
+reply sends a packet back over the transport.
+*/
+func (s *TransportServer) reply(p Packet) error {
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	logWire(wireOut, "transport", data)
+
+	return s.transport.Send(data)
+}
+
+/*
+StopListening closes the transport, and cancels any running tasks and scheduled bundles.
+*/
+func (s *TransportServer) StopListening() error {
+	var err error
+	if s.transport != nil {
+		err = s.transport.Close()
+	}
+
+	s.AddressSpace.CancelTasks()
+	s.AddressSpace.CancelScheduled()
+
+	return err
+}
+
+/*
+LocalAddr returns nil, as transports do not have a network address.
+*/
+func (s *TransportServer) LocalAddr() net.Addr {
+	return nil
+}
+
+/*
+Ready returns a channel that is closed once the transport has been opened.
+*/
+func (s *TransportServer) Ready() <-chan struct{} {
+	return s.listening.readyChan()
+}
@@ -0,0 +1,169 @@
+package osc
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+// loopbackTransport delivers packets sent over one end to the receiver of the other.
+type loopbackTransport struct {
+	receive chan func([]byte)
+	other   *loopbackTransport
+}
+
+func newLoopbackTransports() (*loopbackTransport, *loopbackTransport) {
+	a := &loopbackTransport{receive: make(chan func([]byte), 1)}
+	b := &loopbackTransport{receive: make(chan func([]byte), 1), other: a}
+	a.other = b
+
+	return a, b
+}
+
+func (t *loopbackTransport) Open(receive func([]byte)) error {
+	t.receive <- receive
+	return nil
+}
+
+func (t *loopbackTransport) Send(data []byte) error {
+	receive := <-t.other.receive
+	t.other.receive <- receive
+
+	go receive(append([]byte(nil), data...))
+	return nil
+}
+
+func (t *loopbackTransport) Close() error {
+	return nil
+}
+
+func TestTransport(t *testing.T) {
+	clientEnd, serverEnd := newLoopbackTransports()
+
+	server := NewTransportServer(serverEnd)
+	server.handlePeer("/ping", func(m *Message, peer *Peer) {
+		peer.Reply(NewMessage("/pong"))
+	})
+	if err := server.StartListening(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopListening()
+
+	client := NewTransportClient(clientEnd)
+	received := make(chan string, 1)
+	client.Handle("/pong", func(m *Message) {
+		received <- m.Address
+	})
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	if err := client.Send(NewMessage("/ping")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case address := <-received:
+		if address != "/pong" {
+			t.Errorf("Got %s, expected /pong", address)
+		}
+	case <-time.After(time.Second):
+		t.Error("Reply was not received")
+	}
+
+	if stats := client.Stats(); stats.PacketsSent != 1 {
+		t.Errorf("Got %d packets sent, expected 1", stats.PacketsSent)
+	}
+}
+
+func TestSerialClient(t *testing.T) {
+	clientEnd, deviceEnd := net.Pipe()
+	defer deviceEnd.Close()
+
+	logger := &testLogger{}
+	client := NewSerialClient(clientEnd)
+	client.SetLogger(logger)
+	received := make(chan string, 1)
+	client.Handle("/status", func(m *Message) {
+		received <- m.Address
+	})
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	// A malformed packet is logged and skipped
+	data, _ := NewMessage("/status").MarshalBinary()
+	go deviceEnd.Write(append([]byte{slipEnd, '/', slipEsc, 0, slipEnd}, encodeSLIP(data)...))
+
+	select {
+	case address := <-received:
+		if address != "/status" {
+			t.Errorf("Got %s, expected /status", address)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Packet was not received")
+	}
+
+	if len(logger.lines) != 1 {
+		t.Errorf("Got %d log lines, expected 1", len(logger.lines))
+	}
+}
+
+func TestSerialClientClosed(t *testing.T) {
+	clientEnd, deviceEnd := net.Pipe()
+
+	logger := &testLogger{}
+	client := NewSerialClient(clientEnd)
+	client.SetLogger(logger)
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	// Closing the device's end disconnects the client, without logging an error
+	deviceEnd.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for client.IsConnected() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if client.IsConnected() {
+		t.Error("Client is still connected after the device closed the port")
+	}
+
+	if count := logger.count(); count != 0 {
+		t.Errorf("Got %d log lines, expected 0", count)
+	}
+}
+
+func TestSerialClientPacketLimit(t *testing.T) {
+	clientEnd, deviceEnd := net.Pipe()
+	defer deviceEnd.Close()
+
+	logger := &testLogger{}
+	client := NewSerialClient(clientEnd)
+	client.SetLogger(logger)
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect()
+
+	// A SLIP packet is never ended, so that it exceeds the limit; reading stops and the error is logged
+	go deviceEnd.Write(make([]byte, 2*maxStreamPacketSize+16))
+
+	deadline := time.Now().Add(time.Second)
+	for client.IsConnected() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if client.IsConnected() {
+		t.Error("Client is still connected after an oversized packet")
+	}
+
+	if count := logger.count(); count != 1 {
+		t.Errorf("Got %d log lines, expected 1", count)
+	}
+}
@@ -0,0 +1,91 @@
+package osc
+
+import (
+	"fmt"
+	"sync"
+)
+
+/*
+UnhandledPolicy selects what an AddressSpace does with a message that no method handles.
+*/
+type UnhandledPolicy int
+
+const (
+	// IgnoreUnhandled drops unhandled messages silently, only counting them. This is the default.
+	IgnoreUnhandled UnhandledPolicy = iota
+	// ReplyUnhandled replies to the sender of an unhandled message with an "/error" message containing its address and
+	// "unhandled". Unhandled "/reply" and "/error" messages are never replied to, so that two peers cannot reply to each
+	// other indefinitely.
+	ReplyUnhandled
+	// DefaultUnhandled passes unhandled messages to the handler set with HandleUnhandled.
+	DefaultUnhandled
+)
+
+// The error text of replies to unhandled messages
+const unhandledError = "unhandled"
+
+/*
+unhandled holds an AddressSpace's policy for unhandled messages, and counts them.
+*/
+type unhandled struct {
+	mu     sync.Mutex
+	policy UnhandledPolicy
+	fn     func(*Message, *Peer)
+	count  uint64
+}
+
+/*
+SetUnhandledPolicy sets what the AddressSpace does with messages that no method handles (because no method's address
+pattern matches, or the matching methods' filters or ACLs reject the message). Unhandled messages are counted whatever
+the policy; see UnhandledCount.
+*/
+func (a *AddressSpace) SetUnhandledPolicy(policy UnhandledPolicy) {
+	a.unhandled.mu.Lock()
+	defer a.unhandled.mu.Unlock()
+
+	a.unhandled.policy = policy
+}
+
+/*
+HandleUnhandled sets the handler passed messages that no method handles, when the policy is DefaultUnhandled.
+*/
+func (a *AddressSpace) HandleUnhandled(fn MessageHandleFunc) {
+	a.unhandled.mu.Lock()
+	defer a.unhandled.mu.Unlock()
+
+	a.unhandled.fn = func(m *Message, peer *Peer) {
+		fn(m)
+	}
+}
+
+/*
+UnhandledCount returns the number of messages that no method has handled.
+*/
+func (a *AddressSpace) UnhandledCount() uint64 {
+	a.unhandled.mu.Lock()
+	defer a.unhandled.mu.Unlock()
+
+	return a.unhandled.count
+}
+
+/*
+handleUnhandled counts a message that no method handled, and applies the policy to it.
+*/
+func (a *AddressSpace) handleUnhandled(m *Message, peer *Peer) {
+	a.unhandled.mu.Lock()
+	a.unhandled.count++
+	policy := a.unhandled.policy
+	fn := a.unhandled.fn
+	a.unhandled.mu.Unlock()
+
+	switch policy {
+	case ReplyUnhandled:
+		if m.Address != ReplyAddress && m.Address != ErrorAddress && peer != nil {
+			peer.Reply(NewErrorReply(m, fmt.Errorf(unhandledError)))
+		}
+	case DefaultUnhandled:
+		if fn != nil {
+			fn(m, peer)
+		}
+	}
+}
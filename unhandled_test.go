@@ -0,0 +1,51 @@
+package osc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnhandledPolicy(t *testing.T) {
+	var a AddressSpace
+	a.Handle("/handled", func(m *Message) {})
+
+	var replies []Packet
+	peer := newPeer(nil, func(p Packet) error {
+		replies = append(replies, p)
+		return nil
+	})
+
+	a.DispatchFrom(NewMessage("/handled"), peer)
+	a.DispatchFrom(NewMessage("/missing"), peer)
+
+	if len(replies) != 0 {
+		t.Errorf("Got %v, expected unhandled messages to be dropped", replies)
+	}
+
+	a.SetUnhandledPolicy(ReplyUnhandled)
+	a.DispatchFrom(NewMessage("/missing"), peer)
+	a.DispatchFrom(NewMessage(ErrorAddress), peer)
+
+	expected1 := []interface{}{"/missing", "unhandled"}
+	if len(replies) != 1 || !reflect.DeepEqual(replies[0].(*Message).Arguments, expected1) {
+		t.Errorf("Got %v, expected a single /error reply with arguments %v", replies, expected1)
+	}
+
+	var defaulted []string
+	a.SetUnhandledPolicy(DefaultUnhandled)
+	a.HandleUnhandled(func(m *Message) {
+		defaulted = append(defaulted, m.Address)
+	})
+	a.DispatchFrom(NewMessage("/other"), peer)
+
+	expected2 := []string{"/other"}
+	if !reflect.DeepEqual(defaulted, expected2) {
+		t.Errorf("Got %v, expected %v", defaulted, expected2)
+	}
+
+	result3 := a.UnhandledCount()
+	expected3 := uint64(4)
+	if result3 != expected3 {
+		t.Errorf("Got %v, expected %v", result3, expected3)
+	}
+}
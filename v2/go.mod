@@ -0,0 +1,8 @@
+module github.com/dougfinl/go-osc/v2
+
+go 1.20
+
+require github.com/dougfinl/go-osc v0.0.0
+
+// Until v1 is tagged, v2 builds against the v1 tree in this repository
+replace github.com/dougfinl/go-osc => ../
@@ -0,0 +1,149 @@
+/*
+Package osc is version 2 of go-osc, whose redesigned API is described in V2.md. It is incomplete: so far it provides a
+UDP client created with options, whose Send honours a context. Messages and bundles are pointers to the v1 codec
+types, so that packets can be passed between v1 and v2 code while migrating.
+*/
+package osc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/dougfinl/go-osc/codec"
+)
+
+/*
+Packet is an OSC message or bundle.
+*/
+type Packet = codec.Packet
+
+/*
+Message is an OSC message.
+*/
+type Message = codec.Message
+
+/*
+Bundle is an OSC bundle.
+*/
+type Bundle = codec.Bundle
+
+/*
+NewMessage returns a message with the given address, and no arguments.
+*/
+func NewMessage(address string) *Message {
+	return codec.NewMessage(address)
+}
+
+/*
+NewMessageFromData decodes an encoded message.
+*/
+func NewMessageFromData(data []byte) (*Message, error) {
+	return codec.NewMessageFromData(data)
+}
+
+/*
+Option configures a client created by NewUDPClient.
+*/
+type Option func(*options) error
+
+type options struct {
+	localAddr *net.UDPAddr
+}
+
+/*
+WithLocalAddr sets the local address ("host:port") that packets are sent from.
+*/
+func WithLocalAddr(addr string) Option {
+	return func(o *options) error {
+		localAddr, err := net.ResolveUDPAddr("udp", addr)
+		if err != nil {
+			return err
+		}
+
+		o.localAddr = localAddr
+
+		return nil
+	}
+}
+
+/*
+UDPClient sends OSC packets over UDP to a single destination. It is safe for concurrent use.
+*/
+type UDPClient struct {
+	conn *net.UDPConn
+
+	// writeMu keeps each write with the deadline set for it
+	writeMu sync.Mutex
+}
+
+/*
+NewUDPClient creates a client sending to addr ("host:port"), configured by opts. Its socket is open until Close is
+called.
+*/
+func NewUDPClient(addr string, opts ...Option) (*UDPClient, error) {
+	var o options
+	for _, opt := range opts {
+		err := opt(&o)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	raddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialUDP("udp", o.localAddr, raddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UDPClient{conn: conn}, nil
+}
+
+/*
+Send sends an OSC packet. If ctx is done before the packet is sent, its error is returned, and if ctx has a deadline,
+it applies to the write.
+*/
+func (c *UDPClient) Send(ctx context.Context, p Packet) error {
+	if p == nil {
+		return errors.New("cannot send a nil packet")
+	}
+
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	// Without a deadline, the zero time clears any set by a previous send
+	deadline, _ := ctx.Deadline()
+	err = c.conn.SetWriteDeadline(deadline)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.conn.Write(data)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
+
+/*
+Close closes the client's socket.
+*/
+func (c *UDPClient) Close() error {
+	return c.conn.Close()
+}
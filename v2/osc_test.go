@@ -0,0 +1,52 @@
+package osc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestUDPClient(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client, err := NewUDPClient(conn.LocalAddr().String(), WithLocalAddr("127.0.0.1:0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	msg := NewMessage("/cue/go")
+	msg.AddArgument(int32(1))
+	err = client.Send(ctx, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result1, err := NewMessageFromData(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	} else if !result1.Equals(msg) {
+		t.Errorf("Got %v, expected %v", result1, msg)
+	}
+
+	// A cancelled context stops the send
+	cancel()
+	if err := client.Send(ctx, msg); err != context.Canceled {
+		t.Errorf("Got %v, expected %v", err, context.Canceled)
+	}
+}
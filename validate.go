@@ -0,0 +1,31 @@
+package osc
+
+import (
+	"github.com/dougfinl/go-osc/codec"
+)
+
+/*
+CheckPayloadless inspects an encoded message for interoperability problems with the payload-less T, F, N and I
+arguments, returning a warning for each problem found.
+*/
+func CheckPayloadless(data []byte) []string {
+	return codec.CheckPayloadless(data)
+}
+
+/*
+PayloadlessCheck returns a PacketHook which logs the warnings of CheckPayloadless for each received message, along with
+the peer that sent it. If logger is nil, warnings are logged to standard error. The hook never consumes packets.
+*/
+func PayloadlessCheck(logger Logger) PacketHook {
+	if logger == nil {
+		logger = defaultLogger
+	}
+
+	return func(data []byte, peer *Peer) bool {
+		for _, warning := range CheckPayloadless(data) {
+			logger.Printf("%s (from %s)", warning, peer)
+		}
+
+		return true
+	}
+}
@@ -0,0 +1,99 @@
+package osc
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+/*
+StuckHandlerFunc is called when a method's handler has run for longer than the AddressSpace's handler timeout. It is
+given the address of the message being handled, how long the handler has been running, and the stack trace of the
+goroutine running it.
+*/
+type StuckHandlerFunc func(address string, elapsed time.Duration, stack []byte)
+
+/*
+watchdog reports handlers which run for longer than a timeout.
+*/
+type watchdog struct {
+	timeout time.Duration
+	onStuck StuckHandlerFunc
+}
+
+/*
+SetHandlerTimeout sets how long a method's handler may run before it is reported as stuck, e.g. because it is blocked
+on I/O. A stuck handler is reported to onStuck, or, if onStuck is nil, logged along with its stack trace. Stuck handlers
+are not interrupted, and are reported only once. A timeout of 0 (the default) disables the watchdog.
+*/
+func (a *AddressSpace) SetHandlerTimeout(timeout time.Duration, onStuck StuckHandlerFunc) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.watchdog = watchdog{timeout: timeout, onStuck: onStuck}
+}
+
+/*
+call invokes a method, reporting it if it runs for longer than the timeout.
+*/
+func (w watchdog) call(h Method, m *Message, peer *Peer, logger Logger) {
+	if w.timeout <= 0 {
+		h.call(m, peer)
+		return
+	}
+
+	goroutine := currentGoroutine()
+	start := time.Now()
+
+	timer := time.AfterFunc(w.timeout, func() {
+		elapsed := time.Since(start)
+		stack := goroutineStack(goroutine)
+
+		if w.onStuck != nil {
+			w.onStuck(m.Address, elapsed, stack)
+		} else {
+			logger.Printf("Handler for %s has been running for %v:\n%s", m.Address, elapsed, stack)
+		}
+	})
+	defer timer.Stop()
+
+	h.call(m, peer)
+}
+
+/*
+currentGoroutine returns the header line identifying the calling goroutine in stack traces, e.g. "goroutine 7 ".
+*/
+func currentGoroutine() []byte {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	if i := bytes.IndexByte(buf, '['); i > 0 {
+		return buf[:i]
+	}
+
+	return nil
+}
+
+/*
+goroutineStack returns the stack trace of the goroutine identified by header, as returned by currentGoroutine.
+*/
+func goroutineStack(header []byte) []byte {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	for _, stack := range bytes.Split(buf, []byte("\n\n")) {
+		if header != nil && bytes.HasPrefix(stack, header) {
+			return stack
+		}
+	}
+
+	return []byte(fmt.Sprintf("(stack of %s not found)", bytes.TrimSpace(header)))
+}
@@ -0,0 +1,47 @@
+package osc
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestHandlerTimeout(t *testing.T) {
+	var a AddressSpace
+
+	type report struct {
+		address string
+		stack   []byte
+	}
+	reports := make(chan report, 1)
+
+	a.SetHandlerTimeout(10*time.Millisecond, func(address string, elapsed time.Duration, stack []byte) {
+		reports <- report{address, stack}
+	})
+
+	a.Handle("/slow", func(m *Message) {
+		time.Sleep(50 * time.Millisecond)
+	})
+	a.Handle("/fast", func(m *Message) {})
+
+	a.Dispatch(NewMessage("/fast"))
+	a.Dispatch(NewMessage("/slow"))
+
+	select {
+	case r := <-reports:
+		if r.address != "/slow" {
+			t.Errorf("Got %v, expected /slow", r.address)
+		}
+		if !bytes.Contains(r.stack, []byte("TestHandlerTimeout")) {
+			t.Errorf("Got stack %s, expected it to contain the handler", r.stack)
+		}
+	default:
+		t.Error("Slow handler was not reported")
+	}
+
+	select {
+	case r := <-reports:
+		t.Errorf("Got a second report for %s, expected only one", r.address)
+	default:
+	}
+}
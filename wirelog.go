@@ -0,0 +1,111 @@
+package osc
+
+import (
+	"sync"
+)
+
+// DebugLoggingAddress is the address of the method added by HandleDebugLogging.
+const DebugLoggingAddress = "/debug/logging"
+
+// Directions of packets in the wire log
+const (
+	wireIn  = "<-"
+	wireOut = "->"
+)
+
+/*
+wireLog holds the state of wire logging, shared by all clients and servers.
+*/
+var wireLog struct {
+	mu      sync.RWMutex
+	enabled bool
+	logger  Logger
+}
+
+/*
+SetWireLogging enables or disables logging of every packet sent or received by any client or server, with its
+direction, peer, size and content, for debugging. It may be called at any time. Packets are logged to the logger set
+with SetWireLogger, or to standard error.
+*/
+func SetWireLogging(enabled bool) {
+	wireLog.mu.Lock()
+	defer wireLog.mu.Unlock()
+
+	wireLog.enabled = enabled
+}
+
+/*
+WireLogging returns true if wire logging is enabled.
+*/
+func WireLogging() bool {
+	wireLog.mu.RLock()
+	defer wireLog.mu.RUnlock()
+
+	return wireLog.enabled
+}
+
+/*
+SetWireLogger sets the Logger that packets are logged to when wire logging is enabled. If logger is nil, packets are
+logged to standard error.
+*/
+func SetWireLogger(logger Logger) {
+	wireLog.mu.Lock()
+	defer wireLog.mu.Unlock()
+
+	wireLog.logger = logger
+}
+
+/*
+HandleDebugLogging adds an OSC method to the AddressSpace which enables or disables wire logging (see SetWireLogging)
+when it receives a message at DebugLoggingAddress. The message's argument may be a boolean, or a number which is
+non-zero to enable logging.
+*/
+func (a *AddressSpace) HandleDebugLogging() error {
+	return a.Handle(DebugLoggingAddress, func(m *Message) {
+		if len(m.Arguments) != 1 {
+			return
+		}
+
+		switch v := m.Arguments[0].(type) {
+		case bool:
+			SetWireLogging(v)
+		case int32:
+			SetWireLogging(v != 0)
+		case int64:
+			SetWireLogging(v != 0)
+		case float32:
+			SetWireLogging(v != 0)
+		case float64:
+			SetWireLogging(v != 0)
+		}
+	})
+}
+
+/*
+logWire logs the data of a packet sent to or received from peer, if wire logging is enabled.
+*/
+func logWire(direction string, peer interface{}, data []byte) {
+	wireLog.mu.RLock()
+	enabled, logger := wireLog.enabled, wireLog.logger
+	wireLog.mu.RUnlock()
+
+	if !enabled {
+		return
+	}
+
+	if logger == nil {
+		logger = defaultLogger
+	}
+
+	if peer == nil {
+		peer = "(unknown peer)"
+	}
+
+	p, err := decodeLenient(data)
+	if err != nil {
+		logger.Printf("%s %v %d bytes: malformed packet (%v)", direction, peer, len(data), err)
+		return
+	}
+
+	logger.Printf("%s %v %d bytes: %v", direction, peer, len(data), p)
+}
@@ -0,0 +1,50 @@
+package osc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWireLogging(t *testing.T) {
+	logger := &testLogger{}
+	SetWireLogger(logger)
+	defer SetWireLogger(nil)
+	defer SetWireLogging(false)
+
+	var a AddressSpace
+	a.HandleDebugLogging()
+
+	enable := NewMessage(DebugLoggingAddress)
+	enable.AddArgument(true)
+	data, _ := enable.MarshalBinary()
+
+	// The packet enabling logging is received before logging is enabled
+	a.dispatchData(data, nil)
+	if !WireLogging() {
+		t.Fatal("Expected wire logging to be enabled")
+	}
+
+	msg := NewMessage("/fader")
+	msg.AddArgument(float32(0.5))
+	data, _ = msg.MarshalBinary()
+	a.dispatchData(data, nil)
+
+	if len(logger.lines) != 1 {
+		t.Fatalf("Got %d log lines, expected 1", len(logger.lines))
+	}
+
+	expected := "<- (unknown peer) 16 bytes: Message: /fader (f)0.5"
+	if logger.lines[0] != expected {
+		t.Errorf("Got %v, expected %v", logger.lines[0], expected)
+	}
+
+	disable := NewMessage(DebugLoggingAddress)
+	disable.AddArgument(int32(0))
+	data, _ = disable.MarshalBinary()
+	a.dispatchData(data, nil)
+	a.dispatchData(data, nil)
+
+	if WireLogging() || len(logger.lines) != 2 || !strings.HasPrefix(logger.lines[1], "<-") {
+		t.Errorf("Got %v, expected logging to stop after the second packet", logger.lines)
+	}
+}